@@ -0,0 +1,155 @@
+"""CloudEvents output for event-driven automation (Knative, Argo Events...).
+
+Each weekly report emits report.generated, one finding.created per finding
+reported for the first time and alert.fired when the cluster is red. Events
+are CloudEvents 1.0, posted to a single sink in binary (Knative default) or
+structured content mode.
+"""
+
+import uuid
+from datetime import datetime, timezone
+from typing import Optional
+
+import httpx
+import structlog
+
+from src.config import settings
+
+logger = structlog.get_logger()
+
+EVENT_TYPE_PREFIX = "com.helmcode.watchdog"
+SPEC_VERSION = "1.0"
+
+
+def cloudevents_active() -> bool:
+    """CloudEvents are only sent when a sink is configured."""
+    return bool(settings.cloudevents_sink_url)
+
+
+def build_cloud_event(event_type: str, data: dict, subject: Optional[str] = None) -> dict:
+    """Wrap a payload in a CloudEvents 1.0 envelope.
+
+    Args:
+        event_type: Short type (report.generated, finding.created, alert.fired)
+        data: Event payload
+        subject: Resource the event is about (report ID, finding resource...)
+
+    Returns:
+        CloudEvent as a dict of attributes plus "data"
+    """
+    event = {
+        "specversion": SPEC_VERSION,
+        "id": str(uuid.uuid4()),
+        "source": f"/k8s-watchdog-ai/{settings.client_name}/{settings.cluster_name}",
+        "type": f"{EVENT_TYPE_PREFIX}.{event_type}",
+        "time": datetime.now(timezone.utc).replace(microsecond=0).isoformat(),
+        "datacontenttype": "application/json",
+        "data": data,
+    }
+    if subject:
+        event["subject"] = subject
+    return event
+
+
+def build_report_events(report_id: int, metadata: dict, new_findings: list[dict]) -> list[dict]:
+    """Build the CloudEvents of a stored weekly report.
+
+    Args:
+        report_id: Stored report ID
+        metadata: Report metadata (cluster_stats and report_data)
+        new_findings: Findings reported for the first time in this report
+
+    Returns:
+        report.generated, then finding.created per new finding, then
+        alert.fired when the health status is red
+    """
+    report_data = metadata.get("report_data") or {}
+    stats = metadata.get("cluster_stats") or {}
+    health_status = report_data.get("health_status")
+    findings = report_data.get("findings") or []
+
+    events = [build_cloud_event(
+        "report.generated",
+        {
+            "report_id": report_id,
+            "cluster": settings.cluster_name,
+            "client": settings.client_name,
+            "health_status": health_status,
+            "total_pods": stats.get("total_pods"),
+            "running_pods": stats.get("running_pods"),
+            "findings": len(findings),
+            "new_findings": len(new_findings),
+            "recommendations": len(report_data.get("recommendations") or []),
+            "model": metadata.get("model"),
+            "cost_usd": metadata.get("total_cost_usd"),
+        },
+        subject=f"reports/{report_id}",
+    )]
+
+    for finding in new_findings:
+        resource = "/".join(part for part in (finding.get("namespace"), finding.get("resource")) if part)
+        events.append(build_cloud_event(
+            "finding.created",
+            {
+                "report_id": report_id,
+                "cluster": settings.cluster_name,
+                **{key: finding.get(key) for key in ("kind", "namespace", "resource", "reason", "severity", "title")},
+            },
+            subject=resource or None,
+        ))
+
+    if health_status == "red":
+        events.append(build_cloud_event(
+            "alert.fired",
+            {
+                "report_id": report_id,
+                "cluster": settings.cluster_name,
+                "health_status": health_status,
+                "critical_findings": [
+                    {key: f.get(key) for key in ("kind", "namespace", "resource", "reason", "title")}
+                    for f in findings if f.get("severity") == "critical"
+                ],
+            },
+            subject=f"reports/{report_id}",
+        ))
+
+    return events
+
+
+async def send_cloud_events(events: list[dict]) -> int:
+    """Post CloudEvents to the configured sink, one request per event.
+
+    Args:
+        events: Output of build_report_events()
+
+    Returns:
+        Number of events delivered (0 when no sink is configured)
+    """
+    if not cloudevents_active():
+        return 0
+
+    delivered = 0
+    async with httpx.AsyncClient(timeout=10.0) as client:
+        for event in events:
+            if settings.cloudevents_mode == "structured":
+                response = await client.post(
+                    settings.cloudevents_sink_url,
+                    json=event,
+                    headers={"Content-Type": "application/cloudevents+json"},
+                )
+            else:
+                attributes = {key: value for key, value in event.items() if key not in ("data", "datacontenttype")}
+                response = await client.post(
+                    settings.cloudevents_sink_url,
+                    json=event["data"],
+                    headers={
+                        **{f"ce-{key}": str(value) for key, value in attributes.items()},
+                        "Content-Type": event["datacontenttype"],
+                    },
+                )
+            response.raise_for_status()
+            delivered += 1
+
+    logger.info("cloudevents_sent", events=delivered, sink=settings.cloudevents_sink_url)
+
+    return delivered
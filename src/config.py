@@ -1,7 +1,13 @@
 import os
-from typing import Optional
+from typing import Literal, Optional
 from pydantic_settings import BaseSettings, SettingsConfigDict
 
+from src.tools.namespaces import namespace_in_scope, parse_patterns
+
+# Optional dotenv-style file (a mounted ConfigMap) read after .env and re-read
+# by reload_settings(); environment variables still take precedence
+SETTINGS_FILE = os.environ.get("SETTINGS_FILE", "")
+
 
 class Settings(BaseSettings):
     """Application settings loaded from environment variables."""
@@ -11,45 +17,215 @@ class Settings(BaseSettings):
     anthropic_model: str = "claude-sonnet-4-20250514"
     claude_max_turns: int = 25
     claude_timeout: int = 300
+    report_max_attempts: int = 3  # Corrective re-prompts when the report fails validation
+    llm_record_path: Optional[str] = None  # Append every Claude Code output to this JSON lines file
+    llm_fixture_path: Optional[str] = None  # Replay recorded outputs instead of calling Claude Code
+
+    # Model Routing Configuration
+    model_routing_enabled: bool = False
+    model_routing_small_model: str = "claude-3-5-haiku-20241022"
+    model_routing_large_model: str = "claude-opus-4-20250514"
+    model_routing_small_max_pods: int = 100  # Use the small model at or below this size
+    model_routing_large_min_pods: int = 1000  # Upgrade to the large model from this size
+    model_routing_incident_min_restarts: int = 50  # Upgrade on incident-heavy weeks
+    model_routing_upgrade_budget_usd: float = 0.0  # Monthly spend cap for upgrades (0 = no cap)
+    llm_monthly_budget_usd: float = 0.0  # Hard monthly LLM spend cap; statistics-only reports once reached (0 = no cap)
 
     # Prometheus Configuration
     prometheus_url: str = "http://host.docker.internal:9090"
     kubeconfig_path: str = "~/.kube/config"
+    # Where workload state comes from: "api" (Kubernetes API), "ksm" (kube-state-metrics
+    # through Prometheus, for restricted service accounts) or "both"
+    workload_data_source: Literal["api", "ksm", "both"] = "api"
+    # "full" or "collect-only" (collect and store snapshots without calling the LLM or Slack,
+    # to soak-test the collector before enabling AI analysis)
+    observer_mode: str = "full"
+    # Deadline per statistics collector; on timeout the snapshot goes on without that section
+    # (pods, nodes, events and exclusions are required: the snapshot fails instead)
+    collector_timeout_seconds: float = 120.0  # 0 = no deadline
+    collector_timeouts: str = ""  # Per-section overrides, e.g. "node_usage=30,control_plane=60"
+    # Directory (or file) of `kubectl get -o json|yaml` exports to audit instead of a live
+    # API server; logs, metrics and live watchers are unavailable in this mode
+    offline_manifests_path: Optional[str] = None
 
     # Cluster Configuration
     cluster_name: str = "default"
     client_name: str = "default"
+    # Comma-separated namespace names, globs (ci-*) or regexes with a re: prefix (re:pr-\d+)
     namespaces_exclude: str = "kube-system,kube-public,kube-node-lease"
+    namespaces_include: str = ""  # Empty = every namespace not excluded
+    # Report CoreDNS, CNI, metrics-server etc. in a separate "Control Plane & Add-ons"
+    # section, even when their namespaces are excluded from the application analysis
+    system_components_enabled: bool = False
+    system_namespaces: str = "kube-system"
+    # Flag missing, expired and unused image pull secrets and long-lived service account
+    # tokens (needs list access to Secrets and ServiceAccounts; credentials never leave the pod)
+    credential_checks_enabled: bool = False
+    # Store the full JSON of unhealthy pods/nodes (compressed) with each snapshot, so
+    # postmortems can see the exact object state; kept as long as the report
+    raw_object_archive_enabled: bool = False
+    raw_object_archive_max: int = 50  # Objects archived per snapshot
+    # Pod label/annotation keys kept in snapshots and shown to the agent (keep it small)
+    pod_label_allowlist: str = "app.kubernetes.io/name,app.kubernetes.io/version,team"
 
     # Report Configuration
     report_language: str = "spanish"
+    report_csv_attachments: bool = True  # Attach CSV appendices next to the PDF
+    # PDF output: "pdf/ua-1" produces a tagged (accessible) PDF; empty = plain PDF
+    pdf_variant: str = ""
+    pdf_zoom: float = 1.0  # Scale of the rendered content
+    pdf_jpeg_quality: int = 0  # Recompress embedded images (1-95); 0 = keep originals
+    pdf_dpi: int = 0  # Downscale embedded images to this resolution; 0 = keep originals
+    privacy_mode: bool = False  # Pseudonymize names and withhold event messages from the LLM
+    # Per-namespace severity thresholds, e.g. "batch:restarts=50;payments:restarts=0,warnings=0"
+    # (metrics: restarts, warnings; namespace names, globs or re: regexes, first match wins)
+    namespace_thresholds: str = ""
+    # Event reason severity overrides on top of the built-in mapping, e.g.
+    # "Unhealthy=warning,BackoffLimitExceeded=info" (critical, warning or info)
+    event_severity_overrides: str = ""
+    # Free-text organizational context appended to the system prompt (re-read for every report)
+    cluster_context_path: Optional[str] = None
+    # Redaction of the final report (PDF, CSV appendices, Slack message): semicolon-separated
+    # regexes of internal domains, customer names... (e.g. "[\w.-]+\.corp\.internal;Acme")
+    report_redact_patterns: str = ""
+    report_redact_private_ips: bool = False  # Also redact RFC 1918 / CGNAT addresses and ranges
 
     # Slack Configuration
     slack_webhook_url: str
     slack_bot_token: Optional[str] = None
     slack_channel: Optional[str] = None
+    slack_dm_user_ids: str = ""  # Comma-separated user IDs that also receive the report by DM
+    # Review mode: reports go to this channel/user first and are only published
+    # to the main channel after someone clicks "Approve & Publish"
+    slack_review_channel: Optional[str] = None
+    slack_signing_secret: Optional[str] = None  # Verifies interactivity requests
+    # Extra destinations by report health, e.g. "red=C0ONCALL,yellow=C0TEAM".
+    # The full report always goes to slack_channel.
+    slack_severity_channels: str = ""
+    # Keep the channel topic showing the latest health status and report date
+    # (needs the channels:write.topic / groups:write.topic bot scopes)
+    slack_update_topic: bool = False
+    slack_topic_channel: Optional[str] = None  # Defaults to slack_channel
+    # What to send for weekly reports without critical or high findings on a green cluster:
+    # "full" (the PDF as usual), "heartbeat" (a one-line "all healthy" message with key
+    # figures; the full report stays stored) or "silent" (nothing)
+    quiet_week_behavior: str = "full"
+
+    # Outbox Configuration: failed Slack deliveries are retried with exponential backoff
+    outbox_max_attempts: int = 10
+    outbox_backoff_seconds: int = 60  # Delay before the first retry, doubled after each failure
+    outbox_poll_interval: int = 30
+
+    # Pod Watcher Configuration
+    pod_watcher_enabled: bool = False  # Record short-lived pod failures between reports
+    # Describe-like detail (conditions, events, volumes, node state) stored with unhealthy
+    # pod transitions, rate-limited to keep API calls and rows small
+    pod_details_enabled: bool = False
+    pod_details_max_per_hour: int = 30
+    pod_details_cooldown_minutes: int = 60  # Per pod
+    # Sample watched workloads (WATCHED_WORKLOADS, or annotated with
+    # watchdog.helmcode.com/watch: "true") every WATCHED_SAMPLE_INTERVAL seconds
+    watched_workloads_enabled: bool = False
+    watched_workloads: str = ""  # Comma-separated namespace/name entries
+    watched_sample_interval: int = 600
+    # Active HTTP checks run at each snapshot: comma-separated name=url entries of in-cluster
+    # health URLs (e.g. "checkout=http://checkout.shop.svc:8080/healthz"); empty = disabled
+    endpoint_probes: str = ""
+    endpoint_probe_timeout: float = 5.0
+    endpoint_probe_attempts: int = 3  # Requests per endpoint and snapshot
 
     # Storage Configuration
     data_dir: str = "/app/data"
     retention_weeks: int = 2
+    audit_retention_days: int = 365  # Audit trail of API actions, kept longer than reports
+    max_database_size_mb: int = 0  # Downsample old reports above this size (0 = unlimited)
+    downsample_keep_every: int = 4  # Keep 1 of N old reports when downsampling
+    sqlite_busy_timeout: float = 30.0  # Seconds a write waits for the SQLite lock before failing
+
+    # API Configuration
+    api_token: Optional[str] = None  # Bearer token for destructive endpoints (disabled if unset)
+    # Accept synthetic statistics on POST /snapshots/synthetic (staging, CI and demos only)
+    synthetic_snapshots_enabled: bool = False
 
     # Job Queue Configuration
     job_poll_interval: int = 5  # Seconds between queue polls
     job_max_retries: int = 3  # Maximum retry attempts for failed jobs
 
+    # Tracing Configuration (requires the `tracing` extra)
+    otel_exporter_otlp_endpoint: Optional[str] = None  # e.g. http://otel-collector:4318
+    otel_service_name: str = "k8s-watchdog-ai"
+
+    # CloudEvents Configuration: report.generated, finding.created and alert.fired
+    # events posted to a sink (Knative broker, Argo Events webhook...)
+    cloudevents_sink_url: Optional[str] = None
+    cloudevents_mode: str = "binary"  # "binary" (ce-* headers) or "structured" (JSON envelope)
+
+    # Telemetry Configuration (opt-in; both settings are required to send anything)
+    telemetry_enabled: bool = False
+    telemetry_endpoint: Optional[str] = None  # Receives anonymized aggregate health metrics
+
     # Logging Configuration
     log_level: str = "INFO"
 
     model_config = SettingsConfigDict(
-        env_file=".env",
+        env_file=(".env", SETTINGS_FILE) if SETTINGS_FILE else ".env",
         env_file_encoding="utf-8",
         case_sensitive=False,
     )
 
+    @property
+    def collect_only(self) -> bool:
+        """Return True when no LLM call, notification or delivery may be made (OBSERVER_MODE)."""
+        return self.observer_mode == "collect-only"
+
     @property
     def excluded_namespaces(self) -> list[str]:
-        """Return list of excluded namespaces."""
-        return [ns.strip() for ns in self.namespaces_exclude.split(",")]
+        """Return list of excluded namespace patterns."""
+        return parse_patterns(self.namespaces_exclude)
+
+    @property
+    def included_namespaces(self) -> list[str]:
+        """Return list of included namespace patterns (empty = all)."""
+        return parse_patterns(self.namespaces_include)
+
+    def namespace_in_scope(self, namespace: str) -> bool:
+        """Return True when a namespace passes the include and exclude patterns."""
+        return namespace_in_scope(namespace, self.included_namespaces, self.excluded_namespaces)
+
+    @property
+    def system_namespace_list(self) -> list[str]:
+        """Return namespaces inspected for the control plane & add-ons section."""
+        return [ns.strip() for ns in self.system_namespaces.split(",") if ns.strip()]
+
+    @property
+    def watched_workload_list(self) -> list[str]:
+        """Return the namespace/name of the workloads watched through configuration."""
+        return [w.strip() for w in self.watched_workloads.split(",") if w.strip()]
+
+    @property
+    def pod_label_keys(self) -> list[str]:
+        """Return the pod label/annotation keys to capture."""
+        return [key.strip() for key in self.pod_label_allowlist.split(",") if key.strip()]
+
+    @property
+    def report_redaction_patterns(self) -> list[str]:
+        """Parse the semicolon-separated redaction regexes."""
+        return [p.strip() for p in self.report_redact_patterns.split(";") if p.strip()]
+
+    @property
+    def slack_dm_users(self) -> list[str]:
+        """Return list of Slack user IDs that receive the report by DM."""
+        return [u.strip() for u in self.slack_dm_user_ids.split(",") if u.strip()]
+
+    @property
+    def slack_severity_routes(self) -> dict[str, str]:
+        """Return health status -> Slack channel routing map."""
+        routes = {}
+        for item in self.slack_severity_channels.split(","):
+            status, _, channel = item.partition("=")
+            if status.strip() and channel.strip():
+                routes[status.strip().lower()] = channel.strip()
+        return routes
 
     @property
     def sqlite_path(self) -> str:
@@ -57,5 +233,51 @@ class Settings(BaseSettings):
         return os.path.join(self.data_dir, "watchdog.db")
 
 
+# Read once at startup by long-lived components; reloading them needs a restart
+RESTART_REQUIRED_SETTINGS = {
+    "data_dir",
+    "job_poll_interval",
+    "outbox_poll_interval",
+    "pod_watcher_enabled",
+    "pod_details_enabled",
+    "pod_details_max_per_hour",
+    "pod_details_cooldown_minutes",
+    "watched_workloads_enabled",
+    "watched_sample_interval",
+    "otel_exporter_otlp_endpoint",
+    "otel_service_name",
+    "log_level",
+    "offline_manifests_path",
+}
+
+
 # Global settings instance
 settings = Settings()
+
+
+def reload_settings() -> dict[str, list[str]]:
+    """Re-read the environment, .env and SETTINGS_FILE and apply changed settings in place.
+
+    Everything read per report (Slack routing, namespace filters, prompts,
+    thresholds...) takes effect on the next job. Settings in
+    RESTART_REQUIRED_SETTINGS are left untouched. A running container's
+    environment never changes, so in Kubernetes only SETTINGS_FILE (mounted
+    from a ConfigMap) can bring new values.
+
+    Returns:
+        Names of the applied settings and of the changed settings that need a restart
+    """
+    fresh = Settings()
+    applied, restart_required = [], []
+
+    for name in Settings.model_fields:
+        value = getattr(fresh, name)
+        if value == getattr(settings, name):
+            continue
+        if name in RESTART_REQUIRED_SETTINGS:
+            restart_required.append(name)
+            continue
+        setattr(settings, name, value)
+        applied.append(name)
+
+    return {"applied": applied, "restart_required": restart_required}
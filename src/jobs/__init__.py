@@ -2,6 +2,7 @@
 
 from .queue import JobQueue, Job
 from .worker import start_worker
+from .outbox import start_outbox_sender
 from .processors import process_job
 
-__all__ = ["JobQueue", "Job", "start_worker", "process_job"]
+__all__ = ["JobQueue", "Job", "start_worker", "start_outbox_sender", "process_job"]
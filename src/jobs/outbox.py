@@ -0,0 +1,78 @@
+import asyncio
+import structlog
+
+from src.config import settings
+from src.jobs.processors import deliver_outbox_entry
+from src.storage import ReportStorage
+
+logger = structlog.get_logger()
+
+
+async def start_outbox_sender(storage: ReportStorage) -> asyncio.Task:
+    """Start the background task that retries failed report deliveries.
+
+    Args:
+        storage: ReportStorage instance holding the outbox
+
+    Returns:
+        asyncio.Task that can be cancelled during shutdown
+    """
+    task = asyncio.create_task(_outbox_loop(storage))
+    logger.info("outbox_sender_started", source="outbox")
+    return task
+
+
+async def _outbox_loop(storage: ReportStorage) -> None:
+    """Deliver due outbox entries, rescheduling failures with exponential backoff.
+
+    Args:
+        storage: ReportStorage instance holding the outbox
+    """
+    while True:
+        try:
+            # Collect-only mode sends nothing; entries wait until it is turned off
+            entries = [] if settings.collect_only else await storage.get_due_outbox()
+            for entry in entries:
+                attempts = entry["attempts"] + 1
+                try:
+                    # PDF rendering and uploads block; keep them off the event loop
+                    await asyncio.to_thread(deliver_outbox_entry, entry)
+                except Exception as e:
+                    next_attempt = await storage.retry_outbox(entry["id"], attempts, f"{type(e).__name__}: {e}")
+                    logger.error(
+                        "outbox_delivery_failed",
+                        outbox_id=entry["id"],
+                        report_id=entry["report_id"],
+                        attempts=attempts,
+                        next_attempt_at=next_attempt.isoformat() if next_attempt else None,
+                        gave_up=next_attempt is None,
+                        error=str(e),
+                        source="outbox",
+                    )
+                    continue
+
+                await storage.complete_outbox(entry["id"])
+                logger.info(
+                    "outbox_delivered",
+                    outbox_id=entry["id"],
+                    report_id=entry["report_id"],
+                    kind=entry["kind"],
+                    attempts=attempts,
+                    source="outbox",
+                )
+
+            await asyncio.sleep(settings.outbox_poll_interval)
+
+        except asyncio.CancelledError:
+            logger.info("outbox_sender_shutting_down", source="outbox")
+            raise
+
+        except Exception as loop_error:
+            logger.error(
+                "outbox_loop_error",
+                error=str(loop_error),
+                error_type=type(loop_error).__name__,
+                source="outbox",
+                exc_info=True,
+            )
+            await asyncio.sleep(5)
@@ -1,18 +1,61 @@
 import asyncio
 import structlog
-from datetime import datetime
-from typing import TYPE_CHECKING
+from datetime import datetime, timedelta
+from typing import TYPE_CHECKING, Optional
 
+from src.cloudevents import build_report_events, cloudevents_active, send_cloud_events
 from src.config import settings
 from src.orchestrator import K8sWatchdogAgent
+from src.orchestrator.routing import select_model
 from src.reporter import SlackReporter
+from src.reporter.addons import build_platform_hygiene_section
+from src.reporter.coverage import build_coverage_section
+from src.reporter.csv_export import appendix_tables, build_csv_attachments
+from src.reporter.deterministic import build_deterministic_report
+from src.reporter.redaction import compile_rules, redact_html, redact_text
+from src.reporter.exit_codes import build_exit_code_section
+from src.reporter.findings import build_findings_section
+from src.reporter.followup import build_followup_section
+from src.reporter.node_pools import build_node_pools_section
+from src.reporter.node_stability import build_node_stability_section
+from src.reporter.system_components import build_system_components_section
+from src.reporter.heatmap import build_restart_heatmap
+from src.kube import pop_api_warnings
+from src.reporter.rollup import (
+    ROLLUP_PERIODS,
+    build_rollup_report,
+    format_rollup_for_prompt,
+    summarize_rollup,
+)
+from src.reporter.sanitize import sanitize_report_html
+from src.reporter.watched import build_watched_workloads_section
+from src.reporter.sections import (
+    build_control_plane_section,
+    build_exclusions_section,
+    build_stats_header,
+    build_upgrade_readiness_section,
+    insert_after_header,
+    insert_section,
+    set_document_metadata,
+)
+from src.stats import collect_cluster_stats, infer_dependencies
+from src.stats.archive import collect_unhealthy_objects
+from src.stats.exclusions import is_excluded
+from src.stats.ksm import collect_ksm_stats
+from src.stats.probes import parse_probe_targets, run_endpoint_probes, summarize_endpoint_probes
+from src.stats.watched import summarize_workload_samples
 from src.storage import ReportStorage
+from src.telemetry import build_telemetry_payload, send_telemetry, telemetry_active
+from src.tracing import record_stage_timings, span
 
 if TYPE_CHECKING:
     from src.jobs.queue import Job
 
 logger = structlog.get_logger()
 
+# Report language -> HTML/PDF language code
+LANGUAGE_CODES = {"english": "en", "spanish": "es"}
+
 
 def process_job(job: "Job") -> dict:
     """Process a job based on its type.
@@ -41,10 +84,22 @@ def process_job(job: "Job") -> dict:
         source="processor",
     )
 
-    if job.type == "generate_report":
-        return process_report_generation(job)
-    else:
-        raise ValueError(f"Unknown job type: {job.type}")
+    with record_stage_timings() as timings:
+        try:
+            with span(f"job.{job.type}", job_id=job.id, cluster=settings.cluster_name):
+                if job.type == "generate_report":
+                    return process_report_generation(job)
+                elif job.type == "publish_report":
+                    return process_report_publication(job)
+                elif job.type == "check_database":
+                    return process_database_check(job)
+                elif job.type == "generate_rollup":
+                    return process_rollup_generation(job)
+                else:
+                    raise ValueError(f"Unknown job type: {job.type}")
+        finally:
+            if timings:
+                _save_stage_timings(job, timings)
 
 
 def process_report_generation(job: "Job") -> dict:
@@ -91,15 +146,129 @@ def process_report_generation(job: "Job") -> dict:
         asyncio.set_event_loop(loop)
 
         try:
+            # Weekly period claimed by this job, and its report once stored
+            idempotency_key = None
+            report_id = None
+
             # Initialize components (these need to be created in the thread)
             agent = K8sWatchdogAgent()
             storage = ReportStorage()
 
-            # Generate report using Claude AI
-            # This is the longest operation (~60-70 seconds)
-            report_html, metadata = loop.run_until_complete(
-                agent.generate_weekly_report()
-            )
+            # Ad hoc namespace deep-dive instead of the weekly cluster-wide report
+            namespace = (job.payload or {}).get("namespace")
+            since_hours = (job.payload or {}).get("since_hours")
+
+            # Dry runs (prompt development) generate the report but store and send nothing
+            dry_run = bool((job.payload or {}).get("dry_run"))
+            replay_report_id = (job.payload or {}).get("replay_report_id")
+
+            # Scheduled weekly reports are delivered once per ISO week, even if the
+            # scheduler fires twice; ad hoc, dry-run and forced reports are exempt.
+            # The week is claimed before anything is stored or sent, and released
+            # if this job fails before the report is handed over for delivery
+            if not (namespace or dry_run or settings.collect_only or (job.payload or {}).get("force")):
+                year, week, _ = datetime.now().isocalendar()
+                key = f"weekly:{year}-W{week:02d}"
+                if not loop.run_until_complete(storage.claim_delivery(key)):
+                    delivered = loop.run_until_complete(storage.get_delivery(key)) or {}
+                    logger.info(
+                        "report_already_delivered",
+                        job_id=job.id,
+                        idempotency_key=key,
+                        report_id=delivered.get("report_id"),
+                        source="processor",
+                    )
+                    return {"status": "duplicate", "report_id": delivered.get("report_id")}
+                idempotency_key = key
+
+            # Compute hard numbers before the AI analysis
+            pop_api_warnings()
+            raw_objects = []
+            if replay_report_id:
+                cluster_stats = _load_replay_stats(loop, storage, replay_report_id)
+            else:
+                with span("collector.cluster_stats"):
+                    cluster_stats = _collect_cluster_stats(loop, storage)
+                with span("collector.dependencies"):
+                    _refresh_dependencies(loop, storage)
+                if settings.raw_object_archive_enabled and not dry_run:
+                    # Captured now, not after the LLM call, so objects match the statistics
+                    raw_objects = _collect_raw_objects()
+
+            if settings.collect_only:
+                if dry_run:
+                    logger.info("collect_only_job_skipped", job_id=job.id, job_type=job.type, source="processor")
+                    return {"status": "skipped", "reason": "collect-only mode"}
+                return _store_collected_snapshot(loop, storage, cluster_stats, start_time, raw_objects)
+
+            # Pick the model for this report
+            month_start = datetime.now().replace(day=1, hour=0, minute=0, second=0, microsecond=0)
+            month_spend = loop.run_until_complete(storage.get_spend_since(month_start))
+            budget_notice = _budget_notice(month_spend)
+
+            if budget_notice:
+                # Hard cap reached: no LLM call until next month, dry runs included
+                logger.warning(
+                    "llm_budget_exceeded",
+                    job_id=job.id,
+                    month_spend_usd=month_spend,
+                    budget_usd=settings.llm_monthly_budget_usd,
+                    source="processor",
+                )
+                routing_reason = "monthly LLM budget exceeded"
+                report_html, metadata = build_deterministic_report(
+                    None if namespace else cluster_stats, budget_notice, "budget"
+                )
+            else:
+                model, routing_reason = select_model(cluster_stats, month_spend)
+
+                logger.info(
+                    "model_selected",
+                    job_id=job.id,
+                    model=model,
+                    reason=routing_reason,
+                    month_spend_usd=month_spend,
+                    source="processor",
+                )
+
+                # A replay cannot check the follow-ups without tools
+                open_recommendations = []
+                if not (namespace or replay_report_id):
+                    open_recommendations = loop.run_until_complete(storage.get_open_recommendations())
+
+                # Generate report using Claude AI
+                # This is the longest operation (~60-70 seconds)
+                with span("analyzer.generate_report", model=model, namespace=namespace):
+                    try:
+                        report_html, metadata = loop.run_until_complete(
+                            agent.generate_weekly_report(
+                                # Cluster-wide figures would contradict a namespace-scoped report
+                                cluster_stats=None if namespace else cluster_stats,
+                                model=model,
+                                open_recommendations=open_recommendations,
+                                namespace=namespace,
+                                since_hours=since_hours,
+                                system_prompt=(job.payload or {}).get("system_prompt") if dry_run else None,
+                                replay=bool(replay_report_id),
+                            )
+                        )
+                    finally:
+                        # Every call counts against the budget, even if no report comes out of it
+                        loop.run_until_complete(storage.record_llm_spend(
+                            agent.calls,
+                            "dry_run" if dry_run else "deep_dive" if namespace else "report",
+                            job_id=job.id,
+                        ))
+
+            # Model output is untrusted: strip scripts and remote resources before rendering
+            report_html = sanitize_report_html(report_html)
+            metadata["model_routing_reason"] = routing_reason
+            if namespace:
+                metadata["scope"] = {"namespace": namespace, "since_hours": since_hours}
+
+            if cluster_stats and not namespace:
+                report_html = insert_after_header(report_html, build_stats_header(cluster_stats))
+                metadata["cluster_stats"] = cluster_stats
 
             generation_time = (datetime.now() - start_time).total_seconds()
 
@@ -111,9 +280,164 @@ def process_report_generation(job: "Job") -> dict:
                 source="processor",
             )
 
+            # Add restart heatmap from pod watcher history
+            if settings.pod_watcher_enabled:
+                restart_rows = loop.run_until_complete(storage.get_restarts_by_day())
+                if namespace:
+                    restart_rows = [row for row in restart_rows if row["namespace"] == namespace]
+                if cluster_stats:
+                    restart_rows = [
+                        row for row in restart_rows
+                        if not is_excluded(cluster_stats.get("exclusions"), row["namespace"], row["pod"])
+                    ]
+                heatmap_html = build_restart_heatmap(restart_rows)
+                if heatmap_html:
+                    report_html = insert_section(report_html, heatmap_html)
+
+            # OOM, segfault and application error classes behind the restarts
+            if cluster_stats and not namespace and cluster_stats.get("exit_codes"):
+                exit_code_html = build_exit_code_section(cluster_stats["exit_codes"])
+                if exit_code_html:
+                    report_html = insert_section(report_html, exit_code_html)
+
+            # Request/limit governance with the trend of previous weekly reports
+            if cluster_stats and not namespace and cluster_stats.get("resource_coverage"):
+                previous_reports = loop.run_until_complete(storage.get_recent_report_metadata())
+                history = [
+                    report["metadata"].get("cluster_stats", {}).get("resource_coverage") or []
+                    for report in previous_reports
+                ]
+                coverage_html = build_coverage_section(cluster_stats["resource_coverage"], history)
+                if coverage_html:
+                    report_html = insert_section(report_html, coverage_html)
+
+            # Watched workloads are always shown, whatever the model chose to cover
+            if cluster_stats and not namespace and cluster_stats.get("watched_workloads"):
+                report_html = insert_section(
+                    report_html, build_watched_workloads_section(cluster_stats["watched_workloads"])
+                )
+
+            # Capacity and instability per node pool / node group
+            if cluster_stats and not namespace and cluster_stats.get("node_pools"):
+                report_html = insert_section(report_html, build_node_pools_section(cluster_stats["node_pools"]))
+
+            # Reboots, kernel panics and kubelet restarts per node
+            if cluster_stats and not namespace and cluster_stats.get("node_stability"):
+                report_html = insert_section(
+                    report_html, build_node_stability_section(cluster_stats["node_stability"])
+                )
+
+            # CoreDNS, CNI, metrics-server... kept out of the application findings
+            if cluster_stats and not namespace and cluster_stats.get("system_components"):
+                report_html = insert_section(
+                    report_html, build_system_components_section(cluster_stats["system_components"])
+                )
+
+            # API server / etcd health; workload symptoms often start there
+            if cluster_stats and not namespace and cluster_stats.get("control_plane"):
+                control_plane_html = build_control_plane_section(cluster_stats["control_plane"])
+                if control_plane_html:
+                    report_html = insert_section(report_html, control_plane_html)
+
+            # Add-on versions: end-of-life and outdated CoreDNS, CNI, ingress controller...
+            if cluster_stats and not namespace and cluster_stats.get("addon_inventory"):
+                report_html = insert_section(
+                    report_html, build_platform_hygiene_section(cluster_stats["addon_inventory"])
+                )
+
+            # Deprecation warnings returned by the API server during collection
+            api_warnings = pop_api_warnings()
+            if api_warnings:
+                report_html = insert_section(report_html, build_upgrade_readiness_section(api_warnings))
+                metadata["api_warnings"] = api_warnings
+
+            # Appendix of namespaces and workloads annotated to opt out
+            if cluster_stats and not namespace and cluster_stats.get("exclusions"):
+                exclusions_html = build_exclusions_section(cluster_stats["exclusions"])
+                if exclusions_html:
+                    report_html = insert_section(report_html, exclusions_html)
+
+            # Title, author, cluster and period in the PDF document information
+            period_end = datetime.now()
+            period_start = period_end - timedelta(hours=since_hours or 168)
+            period = f"{period_start:%Y-%m-%d} – {period_end:%Y-%m-%d}"
+            report_html = set_document_metadata(
+                report_html,
+                title=(
+                    f"Namespace {namespace} report – {settings.cluster_name}" if namespace
+                    else f"Kubernetes health report – {settings.cluster_name}"
+                ),
+                author=settings.client_name,
+                description=f"Cluster {settings.cluster_name}, {period}",
+                keywords=[
+                    "kubernetes", "health report", settings.cluster_name, settings.client_name,
+                    *([namespace] if namespace else []),
+                ],
+                created=period_end.replace(microsecond=0).isoformat(),
+                lang=LANGUAGE_CODES.get(settings.report_language.lower(), "en"),
+            )
+
+            if dry_run:
+                loop.run_until_complete(agent.cleanup())
+                logger.info("dry_run_completed", job_id=job.id, source="processor")
+                return {
+                    "status": "dry_run",
+                    "generation_time_seconds": generation_time,
+                    "report_html": report_html,
+                    "metadata": metadata,
+                }
+
+            # Close recommendations the agent verified as acted on; a deterministic
+            # report has no findings, which must not close the open ones
+            report_data = metadata.get("report_data", {})
+            if not namespace and not metadata.get("deterministic"):
+                followups = loop.run_until_complete(
+                    storage.apply_recommendation_followup(report_data.get("recommendation_followup", []))
+                )
+                recommendation_stats = loop.run_until_complete(storage.get_recommendation_stats())
+                followup_html = build_followup_section(followups, recommendation_stats)
+                if followup_html:
+                    report_html = insert_section(report_html, followup_html)
+                metadata["recommendation_stats"] = recommendation_stats
+
+                # Age of each finding, tracked by fingerprint across weekly reports
+                open_findings = loop.run_until_complete(storage.get_open_findings())
+                findings_html = build_findings_section(
+                    report_data.get("findings", []), open_findings, ReportStorage.finding_fingerprint
+                )
+                if findings_html:
+                    report_html = insert_section(report_html, findings_html)
+
+            # Internal hostnames, IPs and customer names must not leak in shared reports
+            report_html = _redact_report(report_html, job.id)
+
+            # Build informative message about data sources
+            tools_message = _build_tools_info_message(metadata, generation_time)
+            tools_message, _ = redact_text(tools_message, _redaction_rules())
+
+            scope = f"{namespace}-" if namespace else ""
+            basename = (
+                f"k8s-report-{settings.client_name}-{settings.cluster_name}-{scope}"
+                f"{datetime.now().strftime('%Y%m%d-%H%M')}"
+            )
+
+            # Kept so an approved report can be published later with the same files;
+            # the CSV appendices come from the collected data, never from the model
+            metadata["delivery"] = {
+                "basename": basename,
+                "message": tools_message,
+                "csv_tables": appendix_tables(cluster_stats, namespace),
+            }
+
+            review_mode = bool(settings.slack_review_channel)
+
             # Save to storage
             report_id = loop.run_until_complete(
-                storage.save_report(report_html)
+                storage.save_report(
+                    report_html,
+                    metadata,
+                    status="pending_review" if review_mode else "published",
+                )
             )
 
             logger.info(
@@ -122,26 +446,65 @@ def process_report_generation(job: "Job") -> dict:
                 report_id=report_id,
                 source="processor",
             )
+            if idempotency_key:
+                loop.run_until_complete(storage.assign_delivery(idempotency_key, report_id))
+            loop.run_until_complete(storage.save_raw_objects(report_id, raw_objects))
 
-            # Build informative message about data sources
-            tools_message = _build_tools_info_message(metadata, generation_time)
+            new_findings = []
+            if not namespace and not metadata.get("deterministic"):
+                loop.run_until_complete(
+                    storage.record_recommendations(report_id, report_data.get("recommendations", []))
+                )
+                if cloudevents_active():
+                    open_findings = loop.run_until_complete(storage.get_open_findings())
+                    new_findings = [
+                        f for f in report_data.get("findings", [])
+                        if f.get("resource") and f.get("reason")
+                        and ReportStorage.finding_fingerprint(f) not in open_findings
+                    ]
+                loop.run_until_complete(
+                    storage.record_findings(report_id, report_data.get("findings", []))
+                )
+                if telemetry_active():
+                    _send_telemetry(loop, metadata)
+            if not namespace and cloudevents_active():
+                _send_cloud_events(loop, report_id, metadata, new_findings)
+
+            loop.run_until_complete(storage.enforce_size_quota())
 
-            # Send to Slack
+            # Send to Slack (or to the reviewers first)
             reporter = SlackReporter()
-            loop.run_until_complete(
-                reporter.send_html_report(
-                    html_content=report_html,
-                    filename=f"k8s-report-{settings.client_name}-{settings.cluster_name}-{datetime.now().strftime('%Y%m%d-%H%M')}.pdf",
-                    message=tools_message,
+            try:
+                _send_report(loop, storage, reporter, report_id, report_html, metadata, review=review_mode)
+            except Exception as e:
+                # The report is stored: the outbox retries the delivery, not the analysis
+                loop.run_until_complete(
+                    storage.enqueue_outbox(report_id, "review" if review_mode else "report", str(e))
                 )
-            )
+                # The outbox owns the delivery now: the claim must stay
+                idempotency_key = None
+                loop.run_until_complete(agent.cleanup())
+                return {
+                    "status": "delivery_pending",
+                    "report_id": report_id,
+                    "generation_time_seconds": generation_time,
+                    "error": str(e),
+                }
+
+            # Sent: a later failure must not let a retry send the period again
+            idempotency_key = None
 
             logger.info(
                 "report_sent_in_worker",
                 job_id=job.id,
+                review_mode=review_mode,
                 source="processor",
             )
 
+            if not review_mode:
+                _route_by_severity(loop, reporter, metadata)
+                _update_channel_topic(loop, reporter, metadata)
+
             # Cleanup agent resources
             loop.run_until_complete(agent.cleanup())
 
@@ -152,6 +515,33 @@ def process_report_generation(job: "Job") -> dict:
                 "report_size_kb": len(report_html) / 1024,
             }
 
+        except Exception as e:
+            if idempotency_key and report_id is None:
+                # Nothing was stored: let the retry of this job claim the period again
+                loop.run_until_complete(storage.release_delivery(idempotency_key))
+            elif idempotency_key:
+                # The report is stored and some side effects already ran: releasing the
+                # claim would let a retry generate and send the period again
+                logger.warning(
+                    "report_post_save_failed",
+                    job_id=job.id,
+                    report_id=report_id,
+                    error=str(e),
+                    error_type=type(e).__name__,
+                    source="processor",
+                )
+                loop.run_until_complete(
+                    storage.enqueue_outbox(report_id, "review" if review_mode else "report", str(e))
+                )
+                loop.run_until_complete(agent.cleanup())
+                return {
+                    "status": "delivery_pending",
+                    "report_id": report_id,
+                    "generation_time_seconds": generation_time,
+                    "error": str(e),
+                }
+            raise
+
         finally:
             # Clean up the event loop
             loop.close()
@@ -168,6 +558,732 @@ def process_report_generation(job: "Job") -> dict:
         raise
 
 
+def process_report_publication(job: "Job") -> dict:
+    """Publish a report approved in the review channel.
+
+    Args:
+        job: Job instance with {"report_id": int} payload
+
+    Returns:
+        Dict with publication outcome
+
+    Raises:
+        ValueError: If the report does not exist or was not approved
+    """
+    report_id = (job.payload or {}).get("report_id")
+
+    loop = asyncio.new_event_loop()
+    asyncio.set_event_loop(loop)
+
+    try:
+        storage = ReportStorage()
+        report = loop.run_until_complete(storage.get_report(report_id))
+
+        if not report:
+            raise ValueError(f"Report {report_id} not found")
+
+        if report["status"] == "published":
+            # Already delivered by a previous attempt of this job
+            return {"status": "success", "report_id": report_id}
+
+        if report["status"] != "approved":
+            raise ValueError(f"Report {report_id} is {report['status']}, not approved")
+
+        if settings.collect_only:
+            # Stays approved; publish it again once OBSERVER_MODE is back to full
+            logger.info("collect_only_job_skipped", job_id=job.id, job_type=job.type, source="processor")
+            return {"status": "skipped", "reason": "collect-only mode", "report_id": report_id}
+
+        reporter = SlackReporter()
+        _send_report(loop, storage, reporter, report_id, report["report_html"], report["metadata"], review=False)
+
+        _route_by_severity(loop, reporter, report["metadata"])
+        _update_channel_topic(loop, reporter, report["metadata"])
+
+        loop.run_until_complete(
+            storage.transition_report_status(report_id, "approved", "published")
+        )
+
+        logger.info(
+            "report_published_in_worker",
+            job_id=job.id,
+            report_id=report_id,
+            source="processor",
+        )
+
+        return {"status": "success", "report_id": report_id}
+
+    finally:
+        loop.close()
+
+
+def process_database_check(job: "Job") -> dict:
+    """Check SQLite integrity; back up a healthy file or recover a corrupted one.
+
+    Args:
+        job: Job instance (no payload)
+
+    Returns:
+        Dict with the check outcome
+    """
+    loop = asyncio.new_event_loop()
+    asyncio.set_event_loop(loop)
+
+    try:
+        storage = ReportStorage()
+        problems = loop.run_until_complete(storage.check_integrity())
+
+        if not problems:
+            loop.run_until_complete(storage.backup())
+            logger.info("database_integrity_ok", job_id=job.id, source="processor")
+            return {"status": "success", "integrity": "ok"}
+
+        logger.error(
+            "database_corruption_detected",
+            job_id=job.id,
+            problems=problems[:10],
+            source="processor",
+        )
+
+        outcome = loop.run_until_complete(storage.recover())
+
+        if settings.collect_only:
+            return {"status": "success", "integrity": "corrupted", "recovery": outcome}
+
+        try:
+            loop.run_until_complete(
+                SlackReporter().send_message(
+                    f"⚠️ K8s Watchdog database for `{settings.cluster_name}` was corrupted "
+                    f"and has been {'restored from the latest backup' if outcome == 'restored_from_backup' else 'rebuilt empty'}."
+                )
+            )
+        except Exception as e:
+            logger.warning("database_corruption_notice_failed", error=str(e), source="processor")
+
+        return {"status": "success", "integrity": "corrupted", "recovery": outcome}
+
+    finally:
+        loop.close()
+
+
+def process_rollup_generation(job: "Job") -> dict:
+    """Generate and send a monthly or quarterly rollup from stored weekly data.
+
+    The trends (health trajectory, capacity growth, recurring findings) are
+    computed from stored reports and findings; the LLM only writes a short
+    narrative on top of them.
+
+    Args:
+        job: Job instance with {"period": "month" | "quarter"} payload
+
+    Returns:
+        Dict with the rollup outcome
+
+    Raises:
+        ValueError: If the period is unknown
+    """
+    period = (job.payload or {}).get("period", "month")
+    if period not in ROLLUP_PERIODS:
+        raise ValueError(f"Unknown rollup period: {period}")
+
+    if settings.collect_only:
+        logger.info("collect_only_job_skipped", job_id=job.id, job_type=job.type, source="processor")
+        return {"status": "skipped", "reason": "collect-only mode"}
+
+    loop = asyncio.new_event_loop()
+    asyncio.set_event_loop(loop)
+
+    try:
+        storage = ReportStorage()
+        period_end = datetime.now()
+        period_start = period_end - timedelta(days=ROLLUP_PERIODS[period])
+
+        reports = loop.run_until_complete(
+            storage.get_recent_report_metadata(limit=200, since=period_start)
+        )
+        if not reports:
+            logger.warning("rollup_skipped_no_reports", job_id=job.id, period=period, source="processor")
+            return {"status": "skipped", "reason": "no weekly reports in the period"}
+
+        findings = loop.run_until_complete(storage.get_findings_seen_since(period_start))
+        summary = summarize_rollup(reports, findings)
+
+        month_start = datetime.now().replace(day=1, hour=0, minute=0, second=0, microsecond=0)
+        budget_notice = _budget_notice(loop.run_until_complete(storage.get_spend_since(month_start)))
+        if budget_notice:
+            narrative, metadata = budget_notice, {"total_cost_usd": 0.0}
+        else:
+            agent = K8sWatchdogAgent()
+            with span("analyzer.rollup_narrative", period=period):
+                try:
+                    narrative, metadata = loop.run_until_complete(
+                        agent.generate_rollup_narrative(period, format_rollup_for_prompt(summary))
+                    )
+                finally:
+                    # Rollups are not stored as reports, so their cost only lives in the ledger
+                    loop.run_until_complete(storage.record_llm_spend(agent.calls, "rollup", job_id=job.id))
+
+        report_html = build_rollup_report(summary, period, period_start, period_end, narrative)
+        report_html = set_document_metadata(
+            report_html,
+            title=f"Kubernetes {period}ly health rollup – {settings.cluster_name}",
+            author=settings.client_name,
+            description=f"Cluster {settings.cluster_name}, {period_start:%Y-%m-%d} – {period_end:%Y-%m-%d}",
+            keywords=["kubernetes", "health rollup", settings.cluster_name, settings.client_name],
+            created=period_end.replace(microsecond=0).isoformat(),
+            lang=LANGUAGE_CODES.get(settings.report_language.lower(), "en"),
+        )
+        report_html = _redact_report(report_html, job.id)
+
+        loop.run_until_complete(
+            SlackReporter().send_html_report(
+                html_content=report_html,
+                filename=f"k8s-{period}ly-rollup-{settings.cluster_name}-{period_end:%Y%m%d}.pdf",
+                message=(
+                    f"📈 {period.capitalize()}ly health rollup for `{settings.cluster_name}` "
+                    f"({len(summary['weeks'])} weekly reports)"
+                ),
+            )
+        )
+
+        logger.info(
+            "rollup_sent",
+            job_id=job.id,
+            period=period,
+            weeks=len(summary["weeks"]),
+            recurring_findings=len(summary["recurring_findings"]),
+            cost_usd=metadata["total_cost_usd"],
+            source="processor",
+        )
+
+        return {
+            "status": "success",
+            "period": period,
+            "weeks": len(summary["weeks"]),
+            "cost_usd": metadata["total_cost_usd"],
+        }
+
+    finally:
+        loop.close()
+
+
+def _store_collected_snapshot(
+    loop: asyncio.AbstractEventLoop,
+    storage: ReportStorage,
+    cluster_stats: Optional[dict],
+    start_time: datetime,
+    raw_objects: list[dict],
+) -> dict:
+    """Store the collected statistics without calling the LLM or Slack (collect-only mode).
+
+    Args:
+        loop: Event loop of the worker thread
+        storage: ReportStorage instance
+        cluster_stats: Collected statistics (None when collection failed)
+        start_time: When the job started
+        raw_objects: Unhealthy objects to archive with the snapshot
+
+    Returns:
+        Job result dict
+    """
+    html = "<!DOCTYPE html><html><head><meta charset=\"UTF-8\"></head><body>"
+    if cluster_stats:
+        html += build_stats_header(cluster_stats)
+    html += "</body></html>"
+
+    report_id = loop.run_until_complete(
+        storage.save_report(
+            html,
+            {"cluster_stats": cluster_stats, "observer_mode": "collect-only"},
+            status="collected",
+        )
+    )
+    loop.run_until_complete(storage.save_raw_objects(report_id, raw_objects))
+    loop.run_until_complete(storage.enforce_size_quota())
+
+    collection_time = (datetime.now() - start_time).total_seconds()
+    logger.info(
+        "snapshot_collected",
+        report_id=report_id,
+        collection_time_seconds=collection_time,
+        stats_available=cluster_stats is not None,
+        source="processor",
+    )
+
+    return {
+        "status": "collected",
+        "report_id": report_id,
+        "collection_time_seconds": collection_time,
+    }
+
+
+def _load_replay_stats(
+    loop: asyncio.AbstractEventLoop, storage: ReportStorage, report_id: int
+) -> Optional[dict]:
+    """Load the cluster statistics stored with a previous report.
+
+    Args:
+        loop: Event loop of the worker thread
+        storage: ReportStorage instance
+        report_id: Report whose inputs are replayed
+
+    Returns:
+        Statistics dict, or None if the report had none
+
+    Raises:
+        ValueError: If the report does not exist
+    """
+    report = loop.run_until_complete(storage.get_report(report_id))
+    if not report:
+        raise ValueError(f"Report {report_id} not found")
+
+    return report["metadata"].get("cluster_stats")
+
+
+def _collect_cluster_stats(
+    loop: asyncio.AbstractEventLoop, storage: ReportStorage
+) -> Optional[dict]:
+    """Collect deterministic cluster statistics, tolerating API failures.
+
+    Args:
+        loop: Event loop of the worker thread
+        storage: ReportStorage instance (for pod watcher restart history)
+
+    Returns:
+        Statistics dict, or None if collection failed
+    """
+    try:
+        restarts_this_week = None
+        terminations = None
+        if settings.pod_watcher_enabled:
+            rows = loop.run_until_complete(storage.get_restarts_by_day())
+            restarts_this_week = sum(row["restarts"] for row in rows)
+            terminations = loop.run_until_complete(storage.get_container_terminations())
+
+        if settings.workload_data_source == "ksm":
+            # The service account is not expected to list resources
+            stats = collect_ksm_stats(restarts_this_week)
+        else:
+            stats = collect_cluster_stats(restarts_this_week, terminations)
+        if settings.watched_workloads_enabled:
+            samples = loop.run_until_complete(storage.get_workload_samples())
+            stats["watched_workloads"] = summarize_workload_samples(samples)
+        stats["endpoint_probes"] = _probe_endpoints(loop, storage)
+        return stats
+    except Exception as e:
+        logger.warning(
+            "cluster_stats_failed",
+            error=str(e),
+            error_type=type(e).__name__,
+            source="processor",
+        )
+        return None
+
+
+def _send_cloud_events(
+    loop: asyncio.AbstractEventLoop, report_id: int, metadata: dict, new_findings: list[dict]
+) -> None:
+    """Emit the CloudEvents of a stored report; failures never fail the report job.
+
+    Args:
+        loop: Event loop of the worker thread
+        report_id: Stored report ID
+        metadata: Report metadata
+        new_findings: Findings reported for the first time
+    """
+    rules = _redaction_rules()
+
+    def redact(value):
+        if isinstance(value, str):
+            return redact_text(value, rules)[0]
+        if isinstance(value, dict):
+            return {key: redact(item) for key, item in value.items()}
+        if isinstance(value, list):
+            return [redact(item) for item in value]
+        return value
+
+    try:
+        events = build_report_events(report_id, metadata, new_findings)
+        if rules:
+            events = [{**event, "data": redact(event["data"])} for event in events]
+        loop.run_until_complete(send_cloud_events(events))
+    except Exception as e:
+        logger.warning("cloudevents_failed", report_id=report_id, error=str(e), source="processor")
+
+
+def _send_telemetry(loop: asyncio.AbstractEventLoop, metadata: dict) -> None:
+    """Send anonymized telemetry; failures never fail the report job.
+
+    Args:
+        loop: Event loop of the worker thread
+        metadata: Report metadata
+    """
+    try:
+        loop.run_until_complete(send_telemetry(build_telemetry_payload(metadata)))
+    except Exception as e:
+        logger.warning("telemetry_failed", error=str(e), source="processor")
+
+
+def deliver_outbox_entry(entry: dict) -> None:
+    """Retry the delivery of a stored report queued in the outbox.
+
+    Runs in a worker thread, like process_job().
+
+    Args:
+        entry: Outbox row (report_id, kind)
+
+    Raises:
+        ValueError: If the report no longer exists
+        Exception: Any delivery error, so the outbox schedules another attempt
+    """
+    loop = asyncio.new_event_loop()
+    asyncio.set_event_loop(loop)
+
+    try:
+        storage = ReportStorage()
+        report = loop.run_until_complete(storage.get_report(entry["report_id"]))
+        if not report:
+            raise ValueError(f"Report {entry['report_id']} not found")
+
+        reporter = SlackReporter()
+        review = entry["kind"] == "review"
+        _send_report(
+            loop, storage, reporter, entry["report_id"], report["report_html"], report["metadata"], review=review
+        )
+        if not review:
+            _route_by_severity(loop, reporter, report["metadata"])
+            _update_channel_topic(loop, reporter, report["metadata"])
+
+    finally:
+        loop.close()
+
+
+def _send_report(
+    loop: asyncio.AbstractEventLoop,
+    storage: ReportStorage,
+    reporter: SlackReporter,
+    report_id: int,
+    report_html: str,
+    metadata: dict,
+    review: bool,
+) -> None:
+    """Send a stored report to Slack, or to the reviewers first.
+
+    The outcome per destination is stored, failed attempts included.
+
+    Args:
+        loop: Event loop of the worker thread
+        storage: ReportStorage instance (delivery history)
+        reporter: SlackReporter instance
+        report_id: Stored report ID
+        report_html: Report HTML
+        metadata: Report metadata (delivery basename and message, report data)
+        review: Send to the review channel with approve/discard buttons
+    """
+    try:
+        _deliver_report(loop, reporter, report_id, report_html, metadata, review)
+    finally:
+        attempts, reporter.deliveries = reporter.deliveries, []
+        try:
+            loop.run_until_complete(storage.record_delivery_attempts(report_id, attempts))
+        except Exception as e:
+            # Never mask the delivery outcome with a history write failure
+            logger.warning("delivery_history_failed", report_id=report_id, error=str(e), source="processor")
+
+
+def _deliver_report(
+    loop: asyncio.AbstractEventLoop,
+    reporter: SlackReporter,
+    report_id: int,
+    report_html: str,
+    metadata: dict,
+    review: bool,
+) -> None:
+    """Send a report to Slack as described in _send_report()."""
+    delivery = metadata.get("delivery", {})
+    basename = delivery.get("basename", f"k8s-report-{settings.cluster_name}-{report_id}")
+
+    attachments = []
+    if settings.report_csv_attachments:
+        rules = _redaction_rules()
+        attachments = [
+            (filename, redact_text(content.decode("utf-8"), rules)[0].encode("utf-8"))
+            for filename, content in build_csv_attachments(delivery.get("csv_tables", {}), basename)
+        ]
+
+    if review:
+        loop.run_until_complete(
+            reporter.send_review_request(
+                report_id=report_id,
+                html_content=report_html,
+                filename=f"{basename}.pdf",
+                message=delivery.get("message"),
+                attachments=attachments,
+            )
+        )
+    elif settings.quiet_week_behavior in ("heartbeat", "silent") and _is_quiet_week(metadata):
+        # Nothing worth a PDF: keep the channel quiet, the report stays stored
+        if settings.quiet_week_behavior == "heartbeat":
+            loop.run_until_complete(reporter.send_status_message(_heartbeat_message(report_id, metadata)))
+        logger.info(
+            "quiet_week_report_not_sent",
+            report_id=report_id,
+            behavior=settings.quiet_week_behavior,
+            source="processor",
+        )
+    else:
+        loop.run_until_complete(
+            reporter.send_html_report(
+                html_content=report_html,
+                filename=f"{basename}.pdf",
+                message=delivery.get("message"),
+                attachments=attachments,
+            )
+        )
+
+
+def _is_quiet_week(metadata: dict) -> bool:
+    """Return True for a green weekly report without critical or high findings.
+
+    Namespace deep-dives were asked for explicitly and are always sent.
+
+    Args:
+        metadata: Report metadata (report data and scope)
+
+    Returns:
+        Whether the full report can be replaced by a status message
+    """
+    report_data = metadata.get("report_data") or {}
+    if metadata.get("scope") or report_data.get("health_status") != "green":
+        return False
+    return not any(f.get("severity") in ("critical", "high") for f in report_data.get("findings") or [])
+
+
+def _heartbeat_message(report_id: int, metadata: dict) -> str:
+    """Build the "all healthy" message sent instead of the PDF on quiet weeks.
+
+    Args:
+        report_id: Stored report ID
+        metadata: Report metadata (cluster statistics and report data)
+
+    Returns:
+        Slack mrkdwn text with a one-line summary of the key figures
+    """
+    stats = metadata.get("cluster_stats") or {}
+    figures = []
+    if stats:
+        figures.append(f"{stats['running_pods']}/{stats['total_pods']} pods running")
+        figures.append(f"{stats['ready_nodes']}/{stats['total_nodes']} nodes ready")
+        if stats.get("restarts_this_week") is not None:
+            figures.append(f"{stats['restarts_this_week']} restarts this week")
+    findings = (metadata.get("report_data") or {}).get("findings") or []
+    if findings:
+        figures.append(f"{len(findings)} minor findings")
+
+    return (
+        f"🟢 *{settings.cluster_name}*: all healthy this week, no critical or high findings."
+        + (f"\n{' · '.join(figures)}" if figures else "")
+        + f"\nFull report stored as #{report_id}."
+    )
+
+
+def _route_by_severity(
+    loop: asyncio.AbstractEventLoop, reporter: SlackReporter, metadata: dict
+) -> None:
+    """Notify the channel mapped to the report's health status, if any.
+
+    Args:
+        loop: Event loop of the worker thread
+        reporter: SlackReporter instance
+        metadata: Report metadata (health status comes from report_data)
+    """
+    if metadata.get("scope"):
+        # Ad hoc deep-dives are requested by someone already looking at the namespace
+        return
+
+    health_status = metadata.get("report_data", {}).get("health_status")
+    channel = settings.slack_severity_routes.get(health_status or "")
+    if not channel or not reporter.bot_token:
+        return
+
+    emoji = {"red": "🔴", "yellow": "🟡", "green": "🟢"}.get(health_status, "⚠️")
+    issues = len(metadata.get("report_data", {}).get("high_restart_pods", []))
+    text = (
+        f"{emoji} Cluster `{settings.cluster_name}` weekly health status: *{health_status.upper()}*"
+        f" ({issues} high-restart pods). Full report in <#{settings.slack_channel}>."
+    )
+
+    try:
+        loop.run_until_complete(reporter.post_message(channel, text))
+        logger.info(
+            "severity_route_notified",
+            health_status=health_status,
+            channel=channel,
+            source="processor",
+        )
+    except Exception as e:
+        logger.warning(
+            "severity_route_failed",
+            health_status=health_status,
+            channel=channel,
+            error=str(e),
+            source="processor",
+        )
+
+
+def _update_channel_topic(
+    loop: asyncio.AbstractEventLoop, reporter: SlackReporter, metadata: dict
+) -> None:
+    """Show the latest health status and report date in the channel topic.
+
+    Args:
+        loop: Event loop of the worker thread
+        reporter: SlackReporter instance
+        metadata: Report metadata (health status comes from report_data)
+    """
+    channel = settings.slack_topic_channel or settings.slack_channel
+    if not settings.slack_update_topic or metadata.get("scope") or not channel or not reporter.bot_token:
+        return
+
+    health_status = metadata.get("report_data", {}).get("health_status")
+    emoji = {"red": "🔴", "yellow": "🟡", "green": "🟢"}.get(health_status, "⚪")
+    stats = metadata.get("cluster_stats") or {}
+    pods = f" · {stats['running_pct']}% pods running" if stats.get("running_pct") is not None else ""
+    topic = (
+        f"{emoji} {settings.cluster_name}: {(health_status or 'unknown').upper()}{pods}"
+        f" · last report {datetime.now():%Y-%m-%d}"
+    )
+
+    try:
+        loop.run_until_complete(reporter.set_channel_topic(channel, topic))
+    except Exception as e:
+        # The report is delivered; a stale topic is not worth failing the job
+        logger.warning("channel_topic_update_failed", channel=channel, error=str(e), source="processor")
+
+
+def _probe_endpoints(loop: asyncio.AbstractEventLoop, storage: ReportStorage) -> Optional[list[dict]]:
+    """Run the configured endpoint probes and summarize them with the week's history.
+
+    Args:
+        loop: Event loop of the worker thread
+        storage: ReportStorage instance
+
+    Returns:
+        Output of summarize_endpoint_probes(), or None when no probe is configured
+    """
+    targets = parse_probe_targets(settings.endpoint_probes)
+    if not targets:
+        return None
+
+    results = loop.run_until_complete(
+        run_endpoint_probes(targets, settings.endpoint_probe_timeout, settings.endpoint_probe_attempts)
+    )
+    loop.run_until_complete(storage.insert_probe_results(results))
+    history = loop.run_until_complete(storage.get_probe_results())
+    return summarize_endpoint_probes(results, history)
+
+
+def _collect_raw_objects() -> list[dict]:
+    """Capture unhealthy pods and nodes for the archive, tolerating API failures.
+
+    Returns:
+        Output of collect_unhealthy_objects(), or an empty list on failure
+    """
+    if settings.workload_data_source == "ksm":
+        return []
+
+    try:
+        return collect_unhealthy_objects(settings.raw_object_archive_max)
+    except Exception as e:
+        logger.warning(
+            "raw_object_archive_failed",
+            error=str(e),
+            error_type=type(e).__name__,
+            source="processor",
+        )
+        return []
+
+
+def _refresh_dependencies(loop: asyncio.AbstractEventLoop, storage: ReportStorage) -> None:
+    """Re-infer the workload dependency graph used for blast-radius notes.
+
+    Failures are logged and the previous graph is kept.
+
+    Args:
+        loop: Event loop of the worker thread
+        storage: ReportStorage instance
+    """
+    if settings.workload_data_source == "ksm":
+        return
+
+    try:
+        edges = infer_dependencies()
+        loop.run_until_complete(storage.replace_workload_dependencies(edges))
+    except Exception as e:
+        logger.warning(
+            "dependency_inference_failed",
+            error=str(e),
+            error_type=type(e).__name__,
+            source="processor",
+        )
+
+
+def _save_stage_timings(job: "Job", timings: dict) -> None:
+    """Store the stage durations of a job, failed ones included.
+
+    Args:
+        job: Processed job
+        timings: Stage -> seconds, from record_stage_timings()
+    """
+    try:
+        asyncio.run(ReportStorage().record_stage_timings(job.id, timings))
+    except Exception as e:
+        # Timings are diagnostics; never fail the job over them
+        logger.warning("stage_timings_not_saved", job_id=job.id, error=str(e), source="processor")
+        return
+
+    logger.info("job_stage_timings", job_id=job.id, job_type=job.type, **timings, source="processor")
+
+
+def _redaction_rules() -> list:
+    """Compile the configured report redaction rules."""
+    return compile_rules(settings.report_redaction_patterns, settings.report_redact_private_ips)
+
+
+def _redact_report(report_html: str, job_id: int) -> str:
+    """Apply the configured redaction rules to a final report.
+
+    Args:
+        report_html: Final report HTML
+        job_id: Job being processed (for logging)
+
+    Returns:
+        Redacted HTML
+    """
+    report_html, redactions = redact_html(report_html, _redaction_rules())
+    if redactions:
+        logger.info("report_redacted", job_id=job_id, redactions=redactions, source="processor")
+    return report_html
+
+
+def _budget_notice(month_spend: float) -> Optional[str]:
+    """Return the notice shown instead of the AI analysis once the monthly LLM budget is spent.
+
+    Args:
+        month_spend: LLM spend recorded this month (USD)
+
+    Returns:
+        Notice text, or None while under budget (or without a budget)
+    """
+    budget = settings.llm_monthly_budget_usd
+    if not budget or month_spend < budget:
+        return None
+    return (
+        f"Monthly LLM budget reached (${month_spend:.2f} of ${budget:.2f}): this report was built "
+        "from the collected statistics only, without AI analysis, until next month."
+    )
+
+
 def _build_tools_info_message(metadata: dict, generation_time: float) -> str:
     """Build informative message about tools used in report generation.
 
@@ -178,12 +1294,29 @@ def _build_tools_info_message(metadata: dict, generation_time: float) -> str:
     Returns:
         Formatted message string for Slack
     """
+    scope = metadata.get("scope")
+    title = (
+        f"🔎 *Namespace deep-dive: `{scope['namespace']}`* (last {scope['since_hours']}h)"
+        if scope else "🤖 *Weekly Cluster Health Report*"
+    )
+
     message_parts = [
-        "🤖 *Weekly Cluster Health Report*",
+        title,
         f"⏱️ Generation time: {generation_time:.1f}s",
         f"🔧 Cluster: `{settings.cluster_name}`",
     ]
 
+    identity = metadata.get("cluster_stats", {}).get("cluster_identity") or {}
+    if identity.get("provider"):
+        location = " ".join(
+            value for value in (identity["provider"], identity.get("region"), identity.get("cluster_id"))
+            if value
+        )
+        message_parts.append(f"☁️ Cloud: `{location}`")
+
+    if metadata.get("notice"):
+        message_parts.append(f"⚠️ {metadata['notice']}")
+
     if metadata.get("model"):
         message_parts.append(f"🧠 Model: `{metadata['model']}`")
 
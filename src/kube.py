@@ -0,0 +1,88 @@
+import os
+import re
+import threading
+
+import structlog
+from kubernetes import client, config
+
+from src.config import settings
+from src.tools.offline import OfflineApiClient
+
+logger = structlog.get_logger()
+
+_loaded = False
+_api_client = None
+
+# Warning header values: <code> <agent> "<text>", possibly several comma-joined
+WARNING_PATTERN = re.compile(r'\d{3} \S+ "((?:[^"\\]|\\.)*)"')
+
+_warnings: set[str] = set()
+_warnings_lock = threading.Lock()
+
+
+class WarningRecordingApiClient(client.ApiClient):
+    """ApiClient that records the Warning headers returned by the API server.
+
+    The API server uses Warning headers for deprecated API versions and other
+    problems with requests (e.g., "policy/v1beta1 PodDisruptionBudget is
+    deprecated in v1.21+, unavailable in v1.25+").
+    """
+
+    def request(self, method, url, *args, **kwargs):
+        response = super().request(method, url, *args, **kwargs)
+
+        header = response.getheader("Warning") if hasattr(response, "getheader") else None
+        if header:
+            with _warnings_lock:
+                _warnings.update(WARNING_PATTERN.findall(header) or [header])
+
+        return response
+
+
+def load_kube_config() -> None:
+    """Load in-cluster config, falling back to the configured kubeconfig.
+
+    Safe to call repeatedly; the config is only loaded once per process.
+    """
+    global _loaded
+    if _loaded:
+        return
+
+    try:
+        config.load_incluster_config()
+        logger.info("kube_config_loaded", source="in_cluster")
+    except config.ConfigException:
+        config.load_kube_config(config_file=os.path.expanduser(settings.kubeconfig_path))
+        logger.info("kube_config_loaded", source="kubeconfig")
+
+    _loaded = True
+
+
+def get_api_client() -> client.ApiClient:
+    """Return the shared API client that records API server warnings.
+
+    In offline mode (OFFLINE_MANIFESTS_PATH) requests are answered from the
+    exported manifests and no cluster config is loaded.
+    """
+    global _api_client
+    if _api_client is None:
+        if settings.offline_manifests_path:
+            _api_client = OfflineApiClient(settings.offline_manifests_path)
+            logger.info(
+                "kube_config_loaded",
+                source="offline",
+                path=settings.offline_manifests_path,
+                objects=len(_api_client.objects),
+            )
+        else:
+            load_kube_config()
+            _api_client = WarningRecordingApiClient()
+    return _api_client
+
+
+def pop_api_warnings() -> list[str]:
+    """Return and clear the warnings recorded since the last call."""
+    with _warnings_lock:
+        warnings = sorted(_warnings)
+        _warnings.clear()
+    return warnings
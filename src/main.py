@@ -1,15 +1,31 @@
 from contextlib import asynccontextmanager
+from datetime import datetime
 from typing import Optional
 import asyncio
+import hashlib
+import hmac
+import re
+import time
 
 import structlog
-from fastapi import FastAPI, HTTPException
+from fastapi import Depends, FastAPI, HTTPException, Query, Request
+from fastapi.security import HTTPAuthorizationCredentials, HTTPBearer
 from pydantic import BaseModel
 
 from src import __version__
-from src.config import settings
+from src.config import reload_settings, settings
 from src.storage import ReportStorage
-from src.jobs import JobQueue, start_worker
+from src.jobs import JobQueue, start_outbox_sender, start_worker
+from src.orchestrator import K8sWatchdogAgent
+from src.reporter import SlackReporter
+from src.reporter import commands
+from src.reporter.interactions import parse_review_action, verify_slack_signature
+from src.reporter.rollup import ROLLUP_PERIODS
+from src.reporter.sections import build_stats_header
+from src.stats.snapshot import validate_snapshot
+from src.watcher import PodWatcher, WorkloadSampler
+from src.telemetry import build_telemetry_payload, telemetry_active
+from src.tracing import init_tracing, shutdown_tracing
 
 
 # Configure structured logging
@@ -30,6 +46,100 @@ logger = structlog.get_logger()
 storage: Optional[ReportStorage] = None
 job_queue: Optional[JobQueue] = None
 worker_task = None
+outbox_task = None
+pod_watcher: Optional[PodWatcher] = None
+workload_sampler: Optional[WorkloadSampler] = None
+
+
+bearer_scheme = HTTPBearer(auto_error=False)
+
+# Kubernetes namespace names are RFC 1123 labels
+NAMESPACE_PATTERN = re.compile(r"[a-z0-9]([-a-z0-9]*[a-z0-9])?")
+NAMESPACE_MAX_LENGTH = 63
+
+
+def require_api_token(
+    credentials: Optional[HTTPAuthorizationCredentials] = Depends(bearer_scheme),
+) -> None:
+    """Protect destructive endpoints with the API_TOKEN bearer token."""
+    if not settings.api_token:
+        raise HTTPException(status_code=403, detail="Set API_TOKEN to enable this endpoint")
+
+    if not credentials or not hmac.compare_digest(credentials.credentials, settings.api_token):
+        raise HTTPException(status_code=401, detail="Invalid or missing API token")
+
+
+def _actor(request: Request) -> str:
+    """Identify the caller without storing secrets: a token fingerprint, or anonymous."""
+    scheme, _, token = request.headers.get("Authorization", "").partition(" ")
+    if scheme.lower() == "bearer" and token:
+        return f"token:{hashlib.sha256(token.encode('utf-8')).hexdigest()[:12]}"
+    return "anonymous"
+
+
+async def _audit(
+    request: Request,
+    action: str,
+    target: Optional[str] = None,
+    actor: Optional[str] = None,
+    **details,
+) -> None:
+    """Record an API action in the audit trail; failures never fail the request.
+
+    Args:
+        request: Incoming request
+        action: What was done (report.trigger, report.approve, data.purge...)
+        target: Object acted on
+        actor: Who acted, when not identified by the request's bearer token
+        **details: Request parameters worth keeping
+    """
+    actor = actor or _actor(request)
+    client_ip = request.client.host if request.client else None
+    details = {key: value for key, value in details.items() if value is not None}
+
+    logger.info("audit_event", actor=actor, action=action, target=target, client_ip=client_ip, **details)
+
+    if not storage:
+        return
+    try:
+        await storage.record_audit(actor, action, target, client_ip, details or None)
+    except Exception as e:
+        logger.warning("audit_record_failed", action=action, error=str(e))
+
+
+class ReportRequest(BaseModel):
+    """Optional report parameters; empty body generates the weekly report."""
+    namespace: Optional[str] = None  # Deep-dive on a single namespace
+    since_hours: Optional[int] = None  # Deep-dive time window (default 168)
+    # Prompt development: generate without storing or sending anything
+    dry_run: bool = False
+    replay_report_id: Optional[int] = None  # Regenerate from a report's statistics, without cluster tools
+    system_prompt: Optional[str] = None  # Custom system prompt (dry runs only)
+    force: bool = False  # Deliver even if this week's report was already delivered
+
+
+class RollupRequest(BaseModel):
+    """Parameters of a rollup report built from stored weekly reports."""
+    period: str = "month"  # "month" or "quarter"
+
+
+class TestNotificationRequest(BaseModel):
+    """Notifier to test."""
+    channel: str = "slack"
+
+
+class SyntheticSnapshotRequest(BaseModel):
+    """Synthetic statistics, shaped like the output of collect_cluster_stats()."""
+    cluster_stats: dict
+    # Also enqueue a dry-run report generated from these statistics
+    generate_report: bool = False
+
+
+class PromptPreviewRequest(BaseModel):
+    """Parameters to preview the prompts of a report without calling the model."""
+    namespace: Optional[str] = None
+    since_hours: Optional[int] = None
+    replay_report_id: Optional[int] = None
 
 
 class ReportResponse(BaseModel):
@@ -40,6 +150,13 @@ class ReportResponse(BaseModel):
     generation_time_seconds: Optional[float] = None
 
 
+class JobResponse(BaseModel):
+    """Response model for enqueued maintenance jobs."""
+    status: str
+    message: str
+    job_id: int
+
+
 class HealthResponse(BaseModel):
     """Health check response."""
     status: str
@@ -50,7 +167,7 @@ class HealthResponse(BaseModel):
 @asynccontextmanager
 async def lifespan(app: FastAPI):
     """Manage application lifecycle."""
-    global storage, job_queue, worker_task
+    global storage, job_queue, worker_task, outbox_task, pod_watcher, workload_sampler
 
     logger.info(
         "k8s_watchdog_ai_starting",
@@ -59,15 +176,34 @@ async def lifespan(app: FastAPI):
         language=settings.report_language,
     )
 
-    # Initialize storage
+    init_tracing()
+
+    # Initialize storage, recovering from a corrupted file if needed
     storage = ReportStorage()
+    problems = await storage.check_integrity()
+    if problems:
+        logger.error("database_corruption_detected", problems=problems[:10])
+        await storage.recover()
     await storage.initialize()
     logger.info("storage_initialized")
 
+    deleted = await storage.cleanup_old_audit_log()
+    logger.info("old_audit_entries_cleaned", count=deleted)
+
     # Clean up old reports
     deleted = await storage.cleanup_old_reports()
     logger.info("old_reports_cleaned", count=deleted)
 
+    deleted = await storage.cleanup_old_pod_transitions()
+    logger.info("old_pod_transitions_cleaned", count=deleted)
+
+    removed = await storage.enforce_size_quota()
+    logger.info(
+        "database_quota_checked",
+        removed=len(removed),
+        size_bytes=storage.get_database_size(),
+    )
+
     # Initialize job queue
     job_queue = JobQueue(storage)
     logger.info("job_queue_initialized")
@@ -76,15 +212,42 @@ async def lifespan(app: FastAPI):
     worker_task = await start_worker(job_queue)
     logger.info("worker_task_started")
 
+    # Retry Slack deliveries that failed after the report was stored
+    outbox_task = await start_outbox_sender(storage)
+
+    # Live watchers need an API server; an offline audit only has the export
+    if settings.offline_manifests_path:
+        logger.info("offline_mode_enabled", path=settings.offline_manifests_path)
+
+    # Start pod watcher to catch failures between reports
+    if settings.pod_watcher_enabled and not settings.offline_manifests_path:
+        pod_watcher = PodWatcher(storage)
+        pod_watcher.start()
+
+    # Sample watched workloads for finer-grained trends
+    if settings.watched_workloads_enabled and not settings.offline_manifests_path:
+        workload_sampler = WorkloadSampler(storage)
+        workload_sampler.start()
+
     yield
 
-    # Shutdown: stop worker gracefully
-    if worker_task:
-        worker_task.cancel()
-        try:
-            await worker_task
-        except asyncio.CancelledError:
-            pass
+    if pod_watcher:
+        pod_watcher.stop()
+
+    if workload_sampler:
+        workload_sampler.stop()
+
+    # Shutdown: interrupt running queries, then stop the worker and the outbox sender
+    ReportStorage.interrupt_all()
+    for task in (worker_task, outbox_task):
+        if task:
+            task.cancel()
+            try:
+                await task
+            except asyncio.CancelledError:
+                pass
+
+    shutdown_tracing()
 
     logger.info("k8s_watchdog_ai_shutdown")
 
@@ -98,6 +261,29 @@ app = FastAPI(
 )
 
 
+@app.middleware("http")
+async def access_log(request: Request, call_next):
+    """Log every request with its status, duration and caller."""
+    start = time.monotonic()
+    status_code = 500
+    try:
+        response = await call_next(request)
+        status_code = response.status_code
+        return response
+    finally:
+        # Probes hit /health every few seconds; keep them out of the default log level
+        log = logger.debug if request.url.path == "/health" else logger.info
+        log(
+            "http_request",
+            method=request.method,
+            path=request.url.path,
+            status=status_code,
+            duration_ms=round((time.monotonic() - start) * 1000, 1),
+            client_ip=request.client.host if request.client else None,
+            actor=_actor(request),
+        )
+
+
 @app.get("/health", response_model=HealthResponse)
 async def health_check():
     """Health check endpoint.
@@ -113,12 +299,23 @@ async def health_check():
 
 
 @app.post("/report", response_model=ReportResponse, status_code=202)
-async def trigger_report():
+async def trigger_report(
+    http_request: Request,
+    request: Optional[ReportRequest] = None,
+    credentials: Optional[HTTPAuthorizationCredentials] = Depends(bearer_scheme),
+):
     """Trigger report generation by enqueuing a job.
 
     This endpoint adds a report generation job to the queue and returns immediately.
     The worker task processes the job asynchronously, keeping the event loop free
     for handling health checks and other requests.
+
+    Send {"namespace": "payments", "since_hours": 48} for a focused deep-dive
+    on one namespace instead of the weekly cluster-wide report.
+
+    Dry runs (with their custom system prompt and replayed statistics) require
+    API_TOKEN: the prompt drives the model and the result is readable through
+    GET /jobs/{id}.
     """
     if not job_queue:
         raise HTTPException(
@@ -126,13 +323,58 @@ async def trigger_report():
             detail="Job queue not initialized"
         )
 
+    payload = None
+    if request and request.namespace:
+        # The namespace reaches the prompt and the upload filename
+        if len(request.namespace) > NAMESPACE_MAX_LENGTH or not NAMESPACE_PATTERN.fullmatch(request.namespace):
+            raise HTTPException(status_code=422, detail="namespace must be a valid Kubernetes namespace name")
+        if not settings.namespace_in_scope(request.namespace):
+            raise HTTPException(
+                status_code=422, detail="namespace is filtered out by NAMESPACES_INCLUDE/NAMESPACES_EXCLUDE"
+            )
+        if request.since_hours is not None and request.since_hours <= 0:
+            raise HTTPException(status_code=422, detail="since_hours must be positive")
+        payload = {"namespace": request.namespace, "since_hours": request.since_hours or 168}
+
+    if request and (request.system_prompt or request.replay_report_id) and not request.dry_run:
+        raise HTTPException(
+            status_code=422,
+            detail="system_prompt and replay_report_id are only allowed with dry_run",
+        )
+
+    if request and request.replay_report_id and request.namespace:
+        raise HTTPException(status_code=422, detail="replay_report_id cannot be combined with namespace")
+
+    if request and request.dry_run:
+        require_api_token(credentials)
+
+    if request and request.force:
+        payload = {**(payload or {}), "force": True}
+
+    if request and request.dry_run:
+        payload = {
+            **(payload or {}),
+            "dry_run": True,
+            "replay_report_id": request.replay_report_id,
+            "system_prompt": request.system_prompt,
+        }
+
     # Enqueue job (returns immediately)
-    job_id = await job_queue.enqueue("generate_report")
+    job_id = await job_queue.enqueue("generate_report", payload)
+    await _audit(
+        http_request,
+        "report.trigger",
+        f"job:{job_id}",
+        namespace=(payload or {}).get("namespace"),
+        dry_run=(payload or {}).get("dry_run"),
+        force=(payload or {}).get("force"),
+    )
 
     logger.info(
         "report_job_enqueued",
         job_id=job_id,
         cluster=settings.cluster_name,
+        namespace=payload["namespace"] if payload else None,
     )
 
     return ReportResponse(
@@ -141,6 +383,248 @@ async def trigger_report():
     )
 
 
+@app.post("/report/rollup", response_model=JobResponse, status_code=202)
+async def trigger_rollup(http_request: Request, request: Optional[RollupRequest] = None):
+    """Enqueue a monthly or quarterly rollup report.
+
+    Trends come from the weekly reports and findings stored over the period,
+    so RETENTION_WEEKS must cover it (at least 13 weeks for a quarter).
+    """
+    if not job_queue:
+        raise HTTPException(status_code=503, detail="Job queue not initialized")
+
+    period = request.period if request else "month"
+    if period not in ROLLUP_PERIODS:
+        raise HTTPException(status_code=422, detail=f"period must be one of: {', '.join(ROLLUP_PERIODS)}")
+
+    job_id = await job_queue.enqueue("generate_rollup", {"period": period})
+    await _audit(http_request, "rollup.trigger", f"job:{job_id}", period=period)
+
+    return JobResponse(
+        status="accepted",
+        message=f"{period.capitalize()}ly rollup enqueued (job_id={job_id})",
+        job_id=job_id,
+    )
+
+
+@app.post("/maintenance/integrity-check", response_model=JobResponse, status_code=202)
+async def trigger_integrity_check(http_request: Request):
+    """Enqueue a database integrity check.
+
+    A healthy database is backed up; a corrupted one is restored from the
+    latest backup (or rebuilt empty) and a Slack notice is sent.
+    """
+    if not job_queue:
+        raise HTTPException(status_code=503, detail="Job queue not initialized")
+
+    job_id = await job_queue.enqueue("check_database")
+    await _audit(http_request, "maintenance.integrity_check", f"job:{job_id}")
+
+    return JobResponse(
+        status="accepted",
+        message=f"Database integrity check enqueued (job_id={job_id})",
+        job_id=job_id,
+    )
+
+
+@app.post("/config/reload", dependencies=[Depends(require_api_token)])
+async def reload_config(http_request: Request):
+    """Re-read the environment, .env and SETTINGS_FILE without restarting.
+
+    Changes apply to the next report (Slack routing, namespace filters,
+    prompts...). Only setting names are returned, never their values.
+    """
+    result = reload_settings()
+
+    logger.info("config_reloaded", **result)
+    await _audit(http_request, "config.reload", applied=result["applied"] or None)
+
+    return result
+
+
+@app.post("/notify/test", dependencies=[Depends(require_api_token)])
+async def test_notification(http_request: Request, request: Optional[TestNotificationRequest] = None):
+    """Send a test message and a small PDF through the configured notifiers.
+
+    Checks credentials and formatting without generating a report. Slack
+    is the only notifier; every configured destination (webhook, channel,
+    DM users, review channel, severity channels) is tried.
+    """
+    channel = request.channel if request else "slack"
+    if channel != "slack":
+        raise HTTPException(status_code=422, detail=f"Notifier '{channel}' is not supported; only 'slack' is")
+
+    if settings.collect_only:
+        raise HTTPException(status_code=409, detail="OBSERVER_MODE is collect-only: notifications are disabled")
+
+    results = await SlackReporter().send_test_notification()
+    await _audit(http_request, "notify.test", channel)
+
+    return {
+        "status": "ok" if results and all(r == "ok" for r in results.values()) else "failed",
+        "destinations": results,
+    }
+
+
+@app.post("/snapshots/synthetic", status_code=201, dependencies=[Depends(require_api_token)])
+async def inject_synthetic_snapshot(http_request: Request, request: SyntheticSnapshotRequest):
+    """Store synthetic cluster statistics to exercise the report pipeline.
+
+    Meant for staging, CI and demo environments: the snapshot is validated,
+    stored like a collect-only snapshot (kept out of trends) and, with
+    generate_report, replayed by a dry-run report whose HTML is returned by
+    GET /jobs/{job_id}. Combine with LLM_FIXTURE_PATH for hermetic runs.
+    """
+    if not settings.synthetic_snapshots_enabled:
+        raise HTTPException(status_code=404, detail="Set SYNTHETIC_SNAPSHOTS_ENABLED to enable this endpoint")
+
+    if not storage or not job_queue:
+        raise HTTPException(status_code=503, detail="Storage not initialized")
+
+    errors = validate_snapshot(request.cluster_stats)
+    if errors:
+        raise HTTPException(status_code=422, detail=errors)
+
+    html = (
+        "<!DOCTYPE html><html><head><meta charset=\"UTF-8\"></head><body>"
+        + build_stats_header(request.cluster_stats)
+        + "</body></html>"
+    )
+    report_id = await storage.save_report(
+        html,
+        {"cluster_stats": request.cluster_stats, "observer_mode": "synthetic"},
+        status="collected",
+    )
+
+    job_id = None
+    if request.generate_report:
+        job_id = await job_queue.enqueue("generate_report", {"dry_run": True, "replay_report_id": report_id})
+
+    logger.info("synthetic_snapshot_stored", report_id=report_id, job_id=job_id)
+    await _audit(http_request, "snapshot.synthetic", f"report:{report_id}", job_id=job_id)
+
+    return {"status": "stored", "report_id": report_id, "job_id": job_id}
+
+
+@app.delete("/data", dependencies=[Depends(require_api_token)])
+async def purge_data(
+    http_request: Request,
+    cluster: str = Query(..., description="Cluster whose data is deleted"),
+    namespace: Optional[str] = Query(None, description="Only delete data about this namespace"),
+    before: Optional[datetime] = Query(None, description="Only delete data recorded before this time"),
+    after: Optional[datetime] = Query(None, description="Only delete data recorded at or after this time"),
+):
+    """Delete stored data for a cluster, namespace and/or time range.
+
+    Used after a namespace is decommissioned or for data deletion requests.
+    The cluster must always be given explicitly.
+    """
+    if not storage:
+        raise HTTPException(status_code=503, detail="Storage not initialized")
+
+    if before and after and after >= before:
+        raise HTTPException(status_code=422, detail="after must be earlier than before")
+
+    deleted = await storage.purge(cluster, namespace=namespace, before=before, after=after)
+    await _audit(
+        http_request,
+        "data.purge",
+        f"cluster:{cluster}",
+        namespace=namespace,
+        before=before.isoformat() if before else None,
+        after=after.isoformat() if after else None,
+        deleted=deleted,
+    )
+
+    return {
+        "status": "purged",
+        "cluster": cluster,
+        "namespace": namespace,
+        "deleted": deleted,
+        "database_size_bytes": storage.get_database_size(),
+    }
+
+
+@app.post("/prompt/preview", dependencies=[Depends(require_api_token)])
+async def preview_prompt(request: PromptPreviewRequest):
+    """Return the prompts a report would be generated with, without calling the model.
+
+    With replay_report_id the statistics stored with that report are used;
+    otherwise no statistics block is included.
+    """
+    if not storage:
+        raise HTTPException(status_code=503, detail="Storage not initialized")
+
+    cluster_stats = None
+    if request.replay_report_id:
+        report = await storage.get_report(request.replay_report_id)
+        if not report:
+            raise HTTPException(status_code=404, detail="Report not found")
+        cluster_stats = report["metadata"].get("cluster_stats")
+
+    open_recommendations = []
+    if not request.namespace:
+        open_recommendations = await storage.get_open_recommendations()
+
+    system_prompt, user_prompt = K8sWatchdogAgent().build_prompts(
+        cluster_stats=None if request.namespace else cluster_stats,
+        open_recommendations=open_recommendations,
+        namespace=request.namespace,
+        since_hours=request.since_hours,
+        replay=bool(request.replay_report_id),
+    )
+
+    return {"system_prompt": system_prompt, "user_prompt": user_prompt}
+
+
+@app.get("/telemetry/preview")
+async def preview_telemetry():
+    """Show exactly what telemetry would send for the latest weekly report."""
+    if not storage:
+        raise HTTPException(status_code=503, detail="Storage not initialized")
+
+    reports = await storage.get_recent_report_metadata(limit=1)
+    if not reports:
+        raise HTTPException(status_code=404, detail="No weekly report generated yet")
+
+    return {
+        "enabled": telemetry_active(),
+        "endpoint": settings.telemetry_endpoint,
+        "payload": build_telemetry_payload(reports[0]["metadata"]),
+    }
+
+
+@app.get("/pipeline/timings")
+async def get_pipeline_timings(days: int = 30):
+    """Show per-stage durations of recent jobs (collection, LLM, PDF render, delivery)."""
+    if not storage:
+        raise HTTPException(status_code=503, detail="Storage not initialized")
+
+    return await storage.get_stage_timing_stats(days=days)
+
+
+@app.get("/audit", dependencies=[Depends(require_api_token)])
+async def get_audit_log(limit: int = 100, action: Optional[str] = None, actor: Optional[str] = None):
+    """List who triggered reports, reviewed them or changed data, newest first."""
+    if not storage:
+        raise HTTPException(status_code=503, detail="Storage not initialized")
+
+    return {"entries": await storage.get_audit_log(limit=min(limit, 1000), action=action, actor=actor)}
+
+
+@app.get("/jobs/{job_id}", dependencies=[Depends(require_api_token)])
+async def get_job(job_id: int):
+    """Get a job's status and result (e.g., the HTML of a dry run)."""
+    if not storage:
+        raise HTTPException(status_code=503, detail="Storage not initialized")
+
+    job = await storage.get_job(job_id)
+    if not job:
+        raise HTTPException(status_code=404, detail="Job not found")
+
+    return job
+
+
 @app.get("/reports")
 async def list_reports(limit: int = 10):
     """List recent reports."""
@@ -149,13 +633,159 @@ async def list_reports(limit: int = 10):
 
     stats = await storage.get_report_stats()
 
+    latest = await storage.get_recent_report_metadata(limit=1)
+    identity = latest[0]["metadata"].get("cluster_stats", {}).get("cluster_identity") if latest else None
+
     return {
         "cluster": settings.cluster_name,
+        "cluster_identity": identity,
         "statistics": stats,
         "message": "Use report ID to retrieve specific reports from storage",
     }
 
 
+@app.get("/deliveries", dependencies=[Depends(require_api_token)])
+async def get_deliveries(
+    limit: int = 50, report_id: Optional[int] = None, failed: bool = False, days: int = 30
+):
+    """Show where reports were delivered: per-destination summary and recent attempts."""
+    if not storage:
+        raise HTTPException(status_code=503, detail="Storage not initialized")
+
+    return {
+        "cluster": settings.cluster_name,
+        "summary": await storage.get_delivery_summary(days=days),
+        "attempts": await storage.get_delivery_attempts(
+            limit=min(limit, 1000), report_id=report_id, failed_only=failed
+        ),
+    }
+
+
+@app.get("/reports/{report_id}/objects", dependencies=[Depends(require_api_token)])
+async def list_report_objects(report_id: int):
+    """List the unhealthy pods and nodes archived with a report."""
+    if not storage:
+        raise HTTPException(status_code=503, detail="Storage not initialized")
+
+    return {"report_id": report_id, "objects": await storage.get_raw_objects(report_id)}
+
+
+@app.get("/reports/{report_id}/objects/{object_id}", dependencies=[Depends(require_api_token)])
+async def get_report_object(report_id: int, object_id: int):
+    """Get the full JSON of an archived object as it was at snapshot time."""
+    if not storage:
+        raise HTTPException(status_code=503, detail="Storage not initialized")
+
+    obj = await storage.get_raw_object(report_id, object_id)
+    if obj is None:
+        raise HTTPException(status_code=404, detail="Archived object not found")
+
+    return obj
+
+
+@app.post("/slack/interactions")
+async def slack_interactions(request: Request):
+    """Handle the Approve & Publish / Discard buttons of review mode.
+
+    Publishing renders the PDF and uploads files, which takes longer than the
+    3 seconds Slack allows, so approval only enqueues a publish job.
+    """
+    if not settings.slack_signing_secret:
+        raise HTTPException(status_code=503, detail="Slack interactivity not configured")
+
+    if not storage or not job_queue:
+        raise HTTPException(status_code=503, detail="Storage not initialized")
+
+    body = await request.body()
+    if not verify_slack_signature(
+        settings.slack_signing_secret,
+        request.headers.get("X-Slack-Request-Timestamp", ""),
+        body,
+        request.headers.get("X-Slack-Signature", ""),
+    ):
+        raise HTTPException(status_code=401, detail="Invalid Slack signature")
+
+    action = parse_review_action(body)
+    if not action:
+        return {}
+
+    report_id = action["report_id"]
+
+    if action["action"] == "approve_report":
+        applied = await storage.transition_report_status(report_id, "pending_review", "approved")
+        if applied:
+            job_id = await job_queue.enqueue("publish_report", {"report_id": report_id})
+            text = f"✅ Report #{report_id} approved by <@{action['user_id']}> and queued for publishing (job_id={job_id})"
+        else:
+            text = f"Report #{report_id} was already reviewed"
+    else:
+        applied = await storage.transition_report_status(report_id, "pending_review", "discarded")
+        text = (
+            f"🗑️ Report #{report_id} discarded by <@{action['user_id']}>"
+            if applied else f"Report #{report_id} was already reviewed"
+        )
+
+    logger.info(
+        "report_review_action",
+        report_id=report_id,
+        action=action["action"],
+        user_id=action["user_id"],
+        applied=applied,
+    )
+    await _audit(
+        request,
+        "report.approve" if action["action"] == "approve_report" else "report.discard",
+        f"report:{report_id}",
+        actor=f"slack:{action['user_id']}",
+        applied=applied,
+    )
+
+    if action["response_url"]:
+        try:
+            await SlackReporter().update_review_message(action["response_url"], text)
+        except Exception as e:
+            logger.warning("slack_review_update_failed", report_id=report_id, error=str(e))
+
+    return {}
+
+
+@app.post("/slack/commands")
+async def slack_commands(request: Request):
+    """Answer `/k8s` slash commands from stored data, without calling the LLM."""
+    if not settings.slack_signing_secret:
+        raise HTTPException(status_code=503, detail="Slack interactivity not configured")
+
+    if not storage:
+        raise HTTPException(status_code=503, detail="Storage not initialized")
+
+    body = await request.body()
+    if not verify_slack_signature(
+        settings.slack_signing_secret,
+        request.headers.get("X-Slack-Request-Timestamp", ""),
+        body,
+        request.headers.get("X-Slack-Signature", ""),
+    ):
+        raise HTTPException(status_code=401, detail="Invalid Slack signature")
+
+    command = commands.parse_slash_command(body)
+    latest = await storage.get_recent_report_metadata(limit=1)
+    metadata = latest[0]["metadata"] if latest else {}
+
+    logger.info("slack_command_received", subcommand=command["subcommand"], user_id=command["user_id"])
+
+    if command["subcommand"] == "top-restarts":
+        return commands.top_restarts_response(
+            await storage.get_restarts_by_day(), metadata.get("cluster_stats")
+        )
+    if command["subcommand"] == "nodes":
+        return commands.nodes_response(metadata.get("cluster_stats"))
+    if command["subcommand"] == "events":
+        namespace = command["args"][0] if command["args"] else None
+        return commands.events_response(metadata.get("cluster_stats"), namespace)
+
+    return commands.usage_response()
+
+
 @app.get("/")
 async def root():
     """Root endpoint."""
@@ -166,7 +796,19 @@ async def root():
         "endpoints": {
             "health": "/health",
             "trigger_report": "POST /report",
+            "trigger_rollup": "POST /report/rollup",
+            "integrity_check": "POST /maintenance/integrity-check",
             "list_reports": "/reports",
+            "report_objects": "/reports/{id}/objects",
+            "delivery_history": "/deliveries",
+            "pipeline_timings": "/pipeline/timings",
+            "audit_log": "/audit",
+            "purge_data": "DELETE /data",
+            "reload_config": "POST /config/reload",
+            "test_notification": "POST /notify/test",
+            "synthetic_snapshot": "POST /snapshots/synthetic",
+            "slack_interactions": "POST /slack/interactions",
+            "slack_commands": "POST /slack/commands",
             "docs": "/docs",
         }
     }
@@ -1,3 +1,4 @@
 from .agent import K8sWatchdogAgent
+from .providers import FixtureProvider, LLMProvider, fake_output
 
-__all__ = ["K8sWatchdogAgent"]
+__all__ = ["K8sWatchdogAgent", "FixtureProvider", "LLMProvider", "fake_output"]
@@ -1,16 +1,94 @@
 import asyncio
 import json
 import os
+import re
+import secrets
 import sys
 import tempfile
+from datetime import datetime
+from typing import Optional
 
 import structlog
 
 from src.config import settings
-from src.orchestrator.prompts import get_system_prompt
+from src.orchestrator.formats import detect_output_format, render_markdown_report
+from src.orchestrator.prompts import (
+    get_namespace_deep_dive_prompt,
+    get_rollup_prompt,
+    get_system_prompt,
+)
+from src.orchestrator.providers import FixtureProvider, LLMProvider, record_output
+from src.reporter.deterministic import build_deterministic_report
+from src.reporter.text import html_to_slack_text
+from src.stats import format_stats_for_prompt
+from src.stats.thresholds import format_thresholds_for_prompt, parse_thresholds
+from src.orchestrator.validation import (
+    build_correction_prompt,
+    validate_report_data,
+    validate_report_html,
+)
+from src.tools.anonymizer import deanonymize, load_mapping
+from src.tracing import span
 
 logger = structlog.get_logger()
 
+REPORT_DATA_PATTERN = re.compile(
+    r"<script[^>]*id=[\"']watchdog-data[\"'][^>]*>(.*?)</script>",
+    re.IGNORECASE | re.DOTALL,
+)
+
+
+def extract_report_data(report_html: str) -> tuple[str, dict]:
+    """Split the structured data block out of the generated HTML.
+
+    Args:
+        report_html: HTML report as returned by Claude
+
+    Returns:
+        Tuple of (HTML without the data block, parsed data dict).
+        The dict is empty when the block is missing or invalid.
+    """
+    match = REPORT_DATA_PATTERN.search(report_html)
+    if not match:
+        logger.warning("report_data_block_missing")
+        return report_html, {}
+
+    cleaned_html = report_html[:match.start()] + report_html[match.end():]
+
+    try:
+        report_data = json.loads(match.group(1))
+    except json.JSONDecodeError as e:
+        logger.warning("report_data_block_invalid", error=str(e))
+        return cleaned_html, {}
+
+    if not isinstance(report_data, dict):
+        logger.warning("report_data_block_invalid", error="not a JSON object")
+        return cleaned_html, {}
+
+    return cleaned_html, report_data
+
+
+def _strip_preamble(result: str) -> str:
+    """Clean up any accidental text before the HTML document."""
+    if "<!DOCTYPE" in result:
+        return result[result.index("<!DOCTYPE"):]
+    if "<html" in result.lower():
+        return result[result.lower().index("<html"):]
+    return result
+
+
+def _read_cluster_context() -> str:
+    """Read the operator-provided cluster context document, if configured."""
+    if not settings.cluster_context_path:
+        return ""
+
+    try:
+        with open(settings.cluster_context_path, encoding="utf-8") as context_file:
+            return context_file.read()
+    except OSError as e:
+        logger.warning("cluster_context_unreadable", path=settings.cluster_context_path, error=str(e))
+        return ""
+
 
 class K8sWatchdogAgent:
     """Orchestrator for AI-powered Kubernetes cluster analysis.
@@ -19,17 +97,32 @@ class K8sWatchdogAgent:
     to analyze cluster health.
     """
 
-    def __init__(self) -> None:
-        """Initialize the watchdog agent."""
+    def __init__(self, provider: Optional[LLMProvider] = None) -> None:
+        """Initialize the watchdog agent.
+
+        Args:
+            provider: Replaces the Claude Code CLI (recorded fixtures or fakes);
+                defaults to replaying LLM_FIXTURE_PATH when it is set
+        """
+        if provider is None and settings.llm_fixture_path:
+            provider = FixtureProvider.from_file(settings.llm_fixture_path)
+        self.provider = provider
+        # Every model call, failed attempts included, for the caller's spend ledger
+        self.calls: list[dict] = []
+
         logger.info(
             "watchdog_agent_initialized",
             model=settings.anthropic_model,
-            auth_method="claude_code_oauth",
+            auth_method="fixture" if provider else "claude_code_oauth",
         )
 
-    def _build_mcp_config(self) -> dict:
+    def _build_mcp_config(self, anonymizer_env: dict) -> dict:
         """Build MCP server configuration for Claude Code.
 
+        Args:
+            anonymizer_env: Privacy mode variables shared by both MCP servers
+                (empty when privacy mode is off)
+
         Returns:
             MCP config dictionary
         """
@@ -40,59 +133,215 @@ class K8sWatchdogAgent:
             os.path.dirname(os.path.dirname(__file__)), "tools", "mcp_prometheus.py"
         )
 
-        return {
-            "mcpServers": {
-                "kubernetes": {
-                    "type": "stdio",
-                    "command": sys.executable,
-                    "args": [mcp_k8s_path],
+        servers = {
+            "prometheus": {
+                "type": "stdio",
+                "command": sys.executable,
+                "args": [mcp_prom_path],
+                "env": {
+                    "PROMETHEUS_URL": settings.prometheus_url,
+                    "NAMESPACES_EXCLUDE": settings.namespaces_exclude,
+                    "NAMESPACES_INCLUDE": settings.namespaces_include,
+                    **anonymizer_env,
                 },
-                "prometheus": {
-                    "type": "stdio",
-                    "command": sys.executable,
-                    "args": [mcp_prom_path],
-                    "env": {
-                        "PROMETHEUS_URL": settings.prometheus_url,
-                    },
+            },
+        }
+
+        # With a kube-state-metrics only source the service account may not be
+        # allowed to list resources, so the Kubernetes server is left out
+        if settings.workload_data_source in ("api", "both"):
+            servers["kubernetes"] = {
+                "type": "stdio",
+                "command": sys.executable,
+                "args": [mcp_k8s_path],
+                "env": {
+                    "WATCHDOG_DB_PATH": settings.sqlite_path,
+                    "CLUSTER_NAME": settings.cluster_name,
+                    "POD_LABEL_ALLOWLIST": settings.pod_label_allowlist,
+                    "NAMESPACES_EXCLUDE": settings.namespaces_exclude,
+                    "NAMESPACES_INCLUDE": settings.namespaces_include,
+                    **anonymizer_env,
                 },
             }
-        }
+            if settings.offline_manifests_path:
+                servers["kubernetes"]["env"]["OFFLINE_MANIFESTS_PATH"] = settings.offline_manifests_path
+
+        return {"mcpServers": servers}
 
     async def cleanup(self) -> None:
         """Cleanup resources."""
         logger.info("tools_cleaned_up")
 
-    async def generate_weekly_report(self) -> tuple[str, dict]:
-        """Generate a weekly cluster health report using Claude Code headless mode.
+    async def _run_claude(
+        self, prompt: str, model: str, mcp_config_path: str, prompt_path: str
+    ) -> dict:
+        """Run Claude Code in headless mode and return its parsed JSON output.
 
-        The agent will:
-        1. Write system prompt and MCP config to temp files
-        2. Invoke claude -p with MCP servers for K8s and Prometheus
-        3. Parse JSON output to extract HTML report
-        4. Return report and metadata
+        Args:
+            prompt: User prompt
+            model: Claude model to use
+            mcp_config_path: Path to the MCP servers config file
+            prompt_path: Path to the system prompt file
 
         Returns:
-            Tuple of (HTML report as string, metadata dict)
+            Claude Code JSON output
         """
-        logger.info("starting_weekly_report_generation", cluster=settings.cluster_name)
+        # Build claude command
+        cmd = [
+            "claude",
+            "-p", prompt,
+            "--output-format", "json",
+            "--model", model,
+            "--max-turns", str(settings.claude_max_turns),
+            "--mcp-config", mcp_config_path,
+            "--append-system-prompt-file", prompt_path,
+            "--dangerously-skip-permissions",
+            "--no-session-persistence",
+        ]
+
+        # Set environment with OAuth token
+        env = {**os.environ}
+        env["CLAUDE_CODE_OAUTH_TOKEN"] = settings.claude_code_oauth_token
 
+        logger.info(
+            "calling_claude_code_headless",
+            model=model,
+            max_turns=settings.claude_max_turns,
+            timeout=settings.claude_timeout,
+        )
+
+        # Run claude CLI
+        process = await asyncio.create_subprocess_exec(
+            *cmd,
+            stdout=asyncio.subprocess.PIPE,
+            stderr=asyncio.subprocess.PIPE,
+            env=env,
+        )
+
+        try:
+            stdout, stderr = await asyncio.wait_for(
+                process.communicate(),
+                timeout=settings.claude_timeout,
+            )
+        except asyncio.TimeoutError:
+            process.kill()
+            await process.communicate()
+            raise RuntimeError(
+                f"Claude Code timed out after {settings.claude_timeout}s"
+            )
+
+        stdout_str = stdout.decode("utf-8", errors="replace")
+        stderr_str = stderr.decode("utf-8", errors="replace")
+
+        if stderr_str:
+            logger.debug("claude_code_stderr", stderr=stderr_str[:500])
+
+        if process.returncode != 0:
+            logger.error(
+                "claude_code_failed",
+                returncode=process.returncode,
+                stderr=stderr_str[:1000],
+            )
+            raise RuntimeError(
+                f"Claude Code exited with code {process.returncode}: {stderr_str[:500]}"
+            )
+
+        # Parse JSON output
+        try:
+            return json.loads(stdout_str)
+        except json.JSONDecodeError as e:
+            logger.error(
+                "claude_code_invalid_json",
+                error=str(e),
+                stdout_preview=stdout_str[:500],
+            )
+            raise RuntimeError(f"Failed to parse Claude Code output: {str(e)}")
+
+    async def _complete(
+        self, prompt: str, model: str, mcp_config_path: str, prompt_path: str
+    ) -> dict:
+        """Get the model output from the provider, or from Claude Code (recording it if enabled).
+
+        Args:
+            prompt: User prompt
+            model: Claude model to use
+            mcp_config_path: Path to the MCP servers config file
+            prompt_path: Path to the system prompt file
+
+        Returns:
+            Claude Code JSON output
+        """
+        if self.provider:
+            output = await self.provider.run(prompt, model, mcp_config_path, prompt_path)
+        else:
+            output = await self._run_claude(prompt, model, mcp_config_path, prompt_path)
+            if settings.llm_record_path:
+                record_output(settings.llm_record_path, prompt, model, output)
+
+        self.calls.append({
+            "recorded_at": datetime.now().isoformat(),
+            "model": model,
+            "cost_usd": output.get("cost_usd", 0.0),
+            "input_tokens": output.get("usage", {}).get("input_tokens", 0),
+            "output_tokens": output.get("usage", {}).get("output_tokens", 0),
+        })
+        return output
+
+    def build_prompts(
+        self,
+        cluster_stats: Optional[dict] = None,
+        open_recommendations: Optional[list[dict]] = None,
+        namespace: Optional[str] = None,
+        since_hours: Optional[int] = None,
+        replay: bool = False,
+    ) -> tuple[str, str]:
+        """Build the system and user prompts for a report.
+
+        Args:
+            cluster_stats: Deterministic cluster statistics to anchor key figures
+            open_recommendations: Recommendations from previous reports to follow up on
+            namespace: Namespace for a deep-dive instead of the weekly report
+            since_hours: Time window of the deep-dive (defaults to 7 days)
+            replay: Write the report from cluster_stats alone, without tools
+
+        Returns:
+            Tuple of (system prompt, user prompt)
+        """
         # Build system prompt
         system_prompt = get_system_prompt(
             language=settings.report_language,
             cluster_name=settings.cluster_name,
+            privacy_mode=settings.privacy_mode,
+            cluster_context=_read_cluster_context(),
+            namespace_thresholds=format_thresholds_for_prompt(parse_thresholds(settings.namespace_thresholds)),
+            offline_audit=bool(settings.offline_manifests_path),
         )
 
         # Build user prompt
-        user_prompt = f"""Generate a weekly health report for cluster {settings.cluster_name}.
-
-Investigate the current cluster state using the available tools:
+        if namespace:
+            user_prompt = get_namespace_deep_dive_prompt(
+                settings.cluster_name, namespace, since_hours or 168
+            )
+        else:
+            scope = f"Excluded namespaces (names, globs or re: regexes): {', '.join(settings.excluded_namespaces)}"
+            if settings.included_namespaces:
+                scope += f"\nOnly these namespaces are in scope: {', '.join(settings.included_namespaces)}"
+            if replay:
+                investigation = """REPLAY: no tools are available in this run. Write the report only from the
+verified cluster statistics below, which were collected for an earlier report; do not
+describe anything they do not cover and do not present them as the current state."""
+            else:
+                investigation = """Investigate the current cluster state using the available tools:
 1. Check pod and node status
 2. Identify problems (restarts, errors, OOMKilled)
 3. Analyze Prometheus metrics for resource issues
 4. Compare actual usage vs requests/limits
-5. Generate prioritized recommendations
+5. Generate prioritized recommendations"""
+            user_prompt = f"""Generate a weekly health report for cluster {settings.cluster_name}.
 
-Excluded namespaces: {', '.join(settings.excluded_namespaces)}
+{investigation}
+
+{scope}
 
 CRITICAL - RESPONSE FORMAT:
 - Return ONLY the HTML code of the report
@@ -102,133 +351,283 @@ CRITICAL - RESPONSE FORMAT:
 - If any tool is unavailable, simply omit that section from the report without mentioning it in the HTML
 """
 
-        # Write temp files for MCP config and system prompt
-        mcp_config = self._build_mcp_config()
+        # A replay has no tools, so the data source does not matter
+        if settings.workload_data_source == "ksm" and not replay:
+            user_prompt += """
+WORKLOAD DATA SOURCE: kube-state-metrics through Prometheus.
+The Kubernetes API tools are not available; use the ksm_* tools for pod and deployment state.
+"""
+        elif settings.workload_data_source == "both" and not replay:
+            user_prompt += """
+WORKLOAD DATA SOURCE: Kubernetes API and kube-state-metrics.
+Prefer the Kubernetes API tools; use the ksm_* tools to fill gaps where the API returns permission errors.
+"""
+
+        if cluster_stats:
+            stats_source = "kube-state-metrics" if settings.workload_data_source == "ksm" else "the Kubernetes API"
+            user_prompt += f"""
+VERIFIED CLUSTER STATISTICS (computed directly from {stats_source}):
+{format_stats_for_prompt(cluster_stats)}
+
+Use these exact figures for totals and percentages in the report; do not recompute or contradict them.
+"""
+
+        # Stored recommendations carry real names, so they are not sent in privacy mode
+        if open_recommendations and not settings.privacy_mode:
+            previous = "\n".join(
+                f"- [id={r['id']}] ({r['category'] or 'general'}) "
+                f"{'/'.join(p for p in (r['namespace'], r['target']) if p) or 'cluster'}: "
+                f"{r['action']} (issued {str(r['first_seen'])[:10]})"
+                for r in open_recommendations
+            )
+            user_prompt += f"""
+PREVIOUS RECOMMENDATIONS STILL OPEN:
+{previous}
+
+For each one, check with the tools whether it was acted on (e.g., requests adjusted, crashloop fixed)
+and add an entry to "recommendation_followup" with its id, "resolved" or "open", and a short evidence.
+Do not repeat resolved recommendations in "recommendations".
+"""
+
+        return system_prompt, user_prompt
+
+    async def generate_rollup_narrative(
+        self, period: str, summary_text: str, model: Optional[str] = None
+    ) -> tuple[str, dict]:
+        """Write the short narrative of a monthly/quarterly rollup.
+
+        The figures come from stored reports, so no MCP server is attached.
+
+        Args:
+            period: "month" or "quarter"
+            summary_text: Output of format_rollup_for_prompt()
+            model: Claude model to use (defaults to ANTHROPIC_MODEL)
+
+        Returns:
+            Tuple of (narrative text, metadata dict)
+        """
+        model = model or settings.anthropic_model
+        prompt = get_rollup_prompt(settings.cluster_name, period, summary_text, settings.report_language)
 
         with tempfile.NamedTemporaryFile(
             mode="w", suffix=".json", delete=False, prefix="mcp_config_"
         ) as mcp_file:
-            json.dump(mcp_config, mcp_file)
+            json.dump({"mcpServers": {}}, mcp_file)
             mcp_config_path = mcp_file.name
 
         with tempfile.NamedTemporaryFile(
             mode="w", suffix=".txt", delete=False, prefix="system_prompt_"
         ) as prompt_file:
-            prompt_file.write(system_prompt)
+            prompt_file.write("You summarize Kubernetes cluster health trends for engineering managers.")
             prompt_path = prompt_file.name
 
         try:
-            # Build claude command
-            cmd = [
-                "claude",
-                "-p", user_prompt,
-                "--output-format", "json",
-                "--model", settings.anthropic_model,
-                "--max-turns", str(settings.claude_max_turns),
-                "--mcp-config", mcp_config_path,
-                "--append-system-prompt-file", prompt_path,
-                "--dangerously-skip-permissions",
-                "--no-session-persistence",
-            ]
-
-            # Set environment with OAuth token
-            env = {**os.environ}
-            env["CLAUDE_CODE_OAUTH_TOKEN"] = settings.claude_code_oauth_token
+            output = await self._complete(prompt, model, mcp_config_path, prompt_path)
+        finally:
+            for path in [mcp_config_path, prompt_path]:
+                try:
+                    os.unlink(path)
+                except OSError:
+                    pass
 
-            logger.info(
-                "calling_claude_code_headless",
-                model=settings.anthropic_model,
-                max_turns=settings.claude_max_turns,
-                timeout=settings.claude_timeout,
-            )
+        narrative = output.get("result", "").strip()
 
-            # Run claude CLI
-            process = await asyncio.create_subprocess_exec(
-                *cmd,
-                stdout=asyncio.subprocess.PIPE,
-                stderr=asyncio.subprocess.PIPE,
-                env=env,
-            )
+        logger.info(
+            "rollup_narrative_generated",
+            period=period,
+            length=len(narrative),
+            cost_usd=output.get("cost_usd", 0.0),
+        )
 
-            try:
-                stdout, stderr = await asyncio.wait_for(
-                    process.communicate(),
-                    timeout=settings.claude_timeout,
-                )
-            except asyncio.TimeoutError:
-                process.kill()
-                await process.communicate()
-                raise RuntimeError(
-                    f"Claude Code timed out after {settings.claude_timeout}s"
-                )
+        return narrative, {
+            "model": model,
+            "total_cost_usd": output.get("cost_usd", 0.0),
+            "input_tokens": output.get("usage", {}).get("input_tokens", 0),
+            "output_tokens": output.get("usage", {}).get("output_tokens", 0),
+        }
 
-            stdout_str = stdout.decode("utf-8", errors="replace")
-            stderr_str = stderr.decode("utf-8", errors="replace")
+    async def generate_weekly_report(
+        self,
+        cluster_stats: Optional[dict] = None,
+        model: Optional[str] = None,
+        open_recommendations: Optional[list[dict]] = None,
+        namespace: Optional[str] = None,
+        since_hours: Optional[int] = None,
+        system_prompt: Optional[str] = None,
+        replay: bool = False,
+    ) -> tuple[str, dict]:
+        """Generate a weekly cluster health report using Claude Code headless mode.
 
-            if stderr_str:
-                logger.debug("claude_code_stderr", stderr=stderr_str[:500])
+        The agent will:
+        1. Write system prompt and MCP config to temp files
+        2. Invoke claude -p with MCP servers for K8s and Prometheus
+        3. Parse JSON output to extract HTML report
+        4. Validate the report and re-prompt with corrections if malformed;
+           after REPORT_MAX_ATTEMPTS, fall back to a statistics-only report
+           followed by the model's unvalidated text
+        5. Return report and metadata
+
+        Args:
+            cluster_stats: Deterministic cluster statistics to anchor key figures
+            model: Claude model to use (defaults to ANTHROPIC_MODEL)
+            open_recommendations: Recommendations from previous reports to follow up on
+            namespace: Produce a focused deep-dive on this namespace instead of the
+                cluster-wide weekly report
+            since_hours: Time window of the deep-dive (defaults to 7 days)
+            system_prompt: Replaces the built-in system prompt (prompt development)
+            replay: Regenerate from stored cluster_stats without MCP servers, so
+                the live cluster is never queried
 
-            if process.returncode != 0:
-                logger.error(
-                    "claude_code_failed",
-                    returncode=process.returncode,
-                    stderr=stderr_str[:1000],
-                )
-                raise RuntimeError(
-                    f"Claude Code exited with code {process.returncode}: {stderr_str[:500]}"
-                )
+        Returns:
+            Tuple of (HTML report as string, metadata dict)
+        """
+        model = model or settings.anthropic_model
+        logger.info(
+            "starting_weekly_report_generation", cluster=settings.cluster_name, model=model, replay=replay
+        )
 
-            # Parse JSON output
-            try:
-                output = json.loads(stdout_str)
-            except json.JSONDecodeError as e:
-                logger.error(
-                    "claude_code_invalid_json",
-                    error=str(e),
-                    stdout_preview=stdout_str[:500],
-                )
-                raise RuntimeError(f"Failed to parse Claude Code output: {str(e)}")
+        with span("analyzer.prompt"):
+            generated_system_prompt, user_prompt = self.build_prompts(
+                cluster_stats=cluster_stats,
+                open_recommendations=open_recommendations,
+                namespace=namespace,
+                since_hours=since_hours,
+                replay=replay,
+            )
+        system_prompt = system_prompt or generated_system_prompt
+
+        # Privacy mode: MCP servers write the token mapping to a local file
+        # that never leaves this process
+        anonymizer_env = {}
+        map_path = ""
+        if settings.privacy_mode:
+            with tempfile.NamedTemporaryFile(
+                mode="w", suffix=".jsonl", delete=False, prefix="anonymizer_map_"
+            ) as map_file:
+                map_path = map_file.name
+            anonymizer_env = {
+                "ANONYMIZER_MAP_PATH": map_path,
+                "ANONYMIZER_SALT": secrets.token_hex(16),
+            }
 
-            # Extract HTML from result
-            report_html = output.get("result", "")
+        # Write temp files for MCP config and system prompt
+        mcp_config = {"mcpServers": {}} if replay else self._build_mcp_config(anonymizer_env)
 
-            # Clean up any accidental text before HTML
-            if "<!DOCTYPE" in report_html:
-                report_html = report_html[report_html.index("<!DOCTYPE"):]
-            elif "<html" in report_html.lower():
-                html_start = report_html.lower().index("<html")
-                report_html = report_html[html_start:]
+        with tempfile.NamedTemporaryFile(
+            mode="w", suffix=".json", delete=False, prefix="mcp_config_"
+        ) as mcp_file:
+            json.dump(mcp_config, mcp_file)
+            mcp_config_path = mcp_file.name
 
-            if not report_html.strip():
-                raise RuntimeError("Claude Code returned empty result")
+        with tempfile.NamedTemporaryFile(
+            mode="w", suffix=".txt", delete=False, prefix="system_prompt_"
+        ) as prompt_file:
+            prompt_file.write(system_prompt)
+            prompt_path = prompt_file.name
+
+        try:
+            prompt = user_prompt
+            fallback_notice = None
+            usage = {"num_turns": 0, "cost_usd": 0.0, "input_tokens": 0, "output_tokens": 0}
+            session_id = ""
+
+            for attempt in range(1, max(settings.report_max_attempts, 1) + 1):
+                with span("analyzer.claude", model=model, attempt=attempt) as claude_span:
+                    output = await self._complete(prompt, model, mcp_config_path, prompt_path)
+                    if claude_span:
+                        claude_span.set_attribute("claude.num_turns", output.get("num_turns", 0))
+                        claude_span.set_attribute("claude.cost_usd", output.get("cost_usd", 0.0))
+
+                usage["num_turns"] += output.get("num_turns", 0)
+                usage["cost_usd"] += output.get("cost_usd", 0.0)
+                usage["input_tokens"] += output.get("usage", {}).get("input_tokens", 0)
+                usage["output_tokens"] += output.get("usage", {}).get("output_tokens", 0)
+                session_id = output.get("session_id", session_id)
+
+                raw_result = output.get("result", "")
+                output_format = detect_output_format(raw_result)
+                if output_format == "markdown":
+                    # Some models are reliable at Markdown but not at full HTML documents
+                    report_markdown, report_data = extract_report_data(raw_result)
+                    report_html = render_markdown_report(report_markdown)
+                    logger.info("markdown_report_rendered", attempt=attempt, model=model)
+                else:
+                    report_html, report_data = extract_report_data(_strip_preamble(raw_result))
+
+                html_problems = validate_report_html(report_html)
+                data_problems = validate_report_data(report_data)
+                if not html_problems and not data_problems:
+                    break
+
+                logger.warning(
+                    "report_validation_failed",
+                    attempt=attempt,
+                    max_attempts=settings.report_max_attempts,
+                    problems=html_problems + data_problems,
+                )
+                prompt = build_correction_prompt(raw_result, html_problems + data_problems)
+            else:
+                if html_problems:
+                    # Raising would make the job queue retry and repeat every attempt
+                    logger.error(
+                        "report_validation_exhausted", attempts=attempt, model=model, problems=html_problems
+                    )
+                    report_html, fallback = build_deterministic_report(
+                        cluster_stats,
+                        f"The AI report failed validation after {attempt} attempts "
+                        f"({'; '.join(html_problems)}): this report was built from the collected "
+                        "statistics, followed by the model's unvalidated text.",
+                        "validation",
+                        model_text=html_to_slack_text(report_html),
+                    )
+                    report_data = fallback["report_data"]
+                    fallback_notice = fallback["notice"]
+                else:
+                    # The visible report is fine; continue without structured data
+                    report_data = {}
+
+            if settings.privacy_mode:
+                mapping = load_mapping(map_path)
+                report_html = deanonymize(report_html, mapping)
+                report_data = json.loads(deanonymize(json.dumps(report_data), mapping))
+                logger.info("report_names_restored", tokens=len(mapping))
 
             # Build metadata
             metadata = {
-                "model": settings.anthropic_model,
-                "num_turns": output.get("num_turns", 0),
-                "session_id": output.get("session_id", ""),
-                "total_cost_usd": output.get("cost_usd", 0.0),
-                "input_tokens": output.get("usage", {}).get("input_tokens", 0),
-                "output_tokens": output.get("usage", {}).get("output_tokens", 0),
-                "mcp_servers_used": ["kubernetes", "prometheus"],
+                "model": model,
+                "num_turns": usage["num_turns"],
+                "session_id": session_id,
+                "total_cost_usd": usage["cost_usd"],
+                "input_tokens": usage["input_tokens"],
+                "output_tokens": usage["output_tokens"],
+                "attempts": attempt,
+                "output_format": output_format,
+                "mcp_servers_used": sorted(mcp_config["mcpServers"]),
                 # Legacy fields for backward compatibility
                 "tools_used": [],
                 "tools_failed": [],
                 "prometheus_available": None,  # Cannot be determined with Claude Code headless
+                "report_data": report_data,
             }
+            if fallback_notice:
+                # Findings must not close or replace the open ones, as with a budget fallback
+                metadata.update(deterministic=True, notice=fallback_notice, fallback_reason="validation")
 
             logger.info(
                 "weekly_report_generated",
                 report_length=len(report_html),
                 num_turns=metadata["num_turns"],
                 cost_usd=metadata["total_cost_usd"],
+                attempts=attempt,
             )
 
             return report_html, metadata
 
         finally:
             # Clean up temp files
-            for path in [mcp_config_path, prompt_path]:
+            for path in [mcp_config_path, prompt_path, map_path]:
+                if not path:
+                    continue
                 try:
                     os.unlink(path)
                 except OSError:
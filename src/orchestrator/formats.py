@@ -0,0 +1,93 @@
+import re
+from html import escape
+
+import markdown
+
+from src.config import settings
+
+# A response wrapped in a single ```markdown (or ```md) fence
+FENCED_DOCUMENT_PATTERN = re.compile(
+    r"^```(?:markdown|md)?\s*\n(.*?)\n```\s*$", re.IGNORECASE | re.DOTALL
+)
+
+# Block-level Markdown markers: headings, lists, tables, quotes
+MARKDOWN_LINE_PATTERN = re.compile(r"^(#{1,6} |[-*+] |\d+\. |\|.*\||> )", re.MULTILINE)
+
+MARKDOWN_EXTENSIONS = ["tables", "fenced_code", "sane_lists"]
+
+# Same look as the HTML reports the prompt asks for
+DOCUMENT_STYLE = """
+  body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, sans-serif; margin: 0; padding: 0; background: #F8FAFF; color: #1A1A1A; line-height: 1.5; }
+  .header { background: #6C62FF; color: white; padding: 40px 20px; text-align: center; }
+  .container { max-width: 900px; margin: 0 auto; padding: 30px 20px; }
+  h2 { border-left: 4px solid #6C62FF; padding-left: 12px; }
+  table { border-collapse: collapse; width: 100%; }
+  th, td { border: 1px solid #E0E0E0; padding: 6px; text-align: left; }
+  code { background: #F5F5F5; padding: 2px 6px; border-radius: 3px; font-family: "Monaco", monospace; }
+  pre { background: #F5F5F5; padding: 12px; overflow-x: auto; }
+  .footer { text-align: center; color: #555; padding: 20px; font-size: 12px; }
+"""
+
+
+def detect_output_format(result: str) -> str:
+    """Tell whether the model answered with an HTML document or with Markdown.
+
+    Args:
+        result: Raw model output
+
+    Returns:
+        "html" when the output contains an HTML document, "markdown" when it
+        has Markdown block markers, "text" otherwise
+    """
+    lowered = result.lower()
+    if "<!doctype" in lowered or "<html" in lowered:
+        return "html"
+    if FENCED_DOCUMENT_PATTERN.match(result.strip()) or MARKDOWN_LINE_PATTERN.search(result):
+        return "markdown"
+    return "text"
+
+
+def render_markdown_report(result: str) -> str:
+    """Render a Markdown report as a complete HTML document for the PDF pipeline.
+
+    The first level-1 heading becomes the report header; raw HTML blocks
+    (badges, tables the model wrote by hand) are kept as they are, the
+    output is sanitized later like any model output.
+
+    Args:
+        result: Markdown model output, without the structured data block
+
+    Returns:
+        HTML document starting with <!DOCTYPE html>
+    """
+    text = result.strip()
+    fenced = FENCED_DOCUMENT_PATTERN.match(text)
+    if fenced:
+        text = fenced.group(1).strip()
+
+    title = f"Kubernetes Health Report – {settings.cluster_name}"
+    heading = re.match(r"^# (.+)$", text, re.MULTILINE)
+    if heading and not text[:heading.start()].strip():
+        title = heading.group(1).strip()
+        text = text[heading.end():].lstrip()
+
+    body = markdown.markdown(text, extensions=MARKDOWN_EXTENSIONS, output_format="html")
+
+    return f"""<!DOCTYPE html>
+<html>
+<head>
+<meta charset="UTF-8">
+<title>{escape(title)}</title>
+<style>{DOCUMENT_STYLE}</style>
+</head>
+<body>
+<div class="header">
+  <h1>{escape(title)}</h1>
+  <p>Cluster: {escape(settings.cluster_name)}</p>
+</div>
+<div class="container">
+{body}
+</div>
+<div class="footer">Generated by K8s Watchdog AI powered by Claude</div>
+</body>
+</html>"""
@@ -1,9 +1,20 @@
-def get_system_prompt(language: str = "spanish", cluster_name: str = "default") -> str:
+def get_system_prompt(
+    language: str = "spanish",
+    cluster_name: str = "default",
+    privacy_mode: bool = False,
+    cluster_context: str = "",
+    namespace_thresholds: str = "",
+    offline_audit: bool = False,
+) -> str:
     """Generate system prompt for the AI agent.
 
     Args:
         language: Language for the report
         cluster_name: Name of the Kubernetes cluster
+        privacy_mode: Whether tool results contain pseudonymized names
+        cluster_context: Operator-provided knowledge about the cluster
+        namespace_thresholds: Per-namespace severity tolerances, one line per rule
+        offline_audit: Whether the Kubernetes tools read exported manifests instead of a live cluster
 
     Returns:
         System prompt string
@@ -18,6 +29,41 @@ All text, headers, descriptions, and recommendations must be in {language}.
         language_instruction = """
 IMPORTANT: Generate the complete report in English.
 All text, headers, descriptions, and recommendations must be in English.
+"""
+
+    privacy_instruction = ""
+    if privacy_mode:
+        privacy_instruction = """
+PRIVACY MODE:
+- Pod, node and workload names are pseudonymized tokens (e.g., pod-3fa9c1d2e4, node-..., workload-...)
+- Use these tokens verbatim wherever you would use the real name, including tool arguments and PromQL queries
+- Never try to guess real names; they are restored automatically after the report is generated
+- Event messages are withheld; rely on event reasons, counts and object references
+"""
+
+    context_instruction = ""
+    if cluster_context.strip():
+        context_instruction = f"""
+CLUSTER CONTEXT (provided by the cluster operators; use it to judge criticality and expected behavior):
+{cluster_context.strip()}
+"""
+
+    thresholds_instruction = ""
+    if namespace_thresholds.strip():
+        thresholds_instruction = f"""
+NAMESPACE SEVERITY THRESHOLDS (configured by the cluster operators):
+{namespace_thresholds.strip()}
+- Within its tolerance, a namespace's restarts or warnings are expected: do not report them as findings
+- Above its tolerance, report the namespace at least as a high severity finding; zero tolerance means any occurrence counts
+"""
+
+    offline_instruction = ""
+    if offline_audit:
+        offline_instruction = """
+OFFLINE AUDIT:
+- The Kubernetes tools read a static export of the cluster (kubectl get -o json/yaml), not a live API server
+- Pod logs, live metrics and resources missing from the export are unavailable; do not report their absence as a cluster problem
+- Describe the state as of the export and state in the summary that this is an offline audit
 """
 
     return f"""You are an expert Kubernetes cluster analyst with access to observability tools.
@@ -29,10 +75,38 @@ CONTEXT:
 
 ANALYSIS METHODOLOGY:
 1. Start by investigating general state (pods, nodes)
-2. Identify evident problems (restarts, errors, OOMKilled)
+2. Identify evident problems (restarts, errors, OOMKilled), including pods stuck in the init phase (Init:CrashLoopBackOff, Init:Error): describe them to find the failing init container. Mention pods with running ephemeral debug containers (someone is actively debugging them)
 3. For each problem, dive deeper with Prometheus queries (if available)
 4. Compare actual usage vs requests/limits to detect over-provisioning
 5. Look for trends and anomalies over the last 7 days
+6. Check recorded pod transitions for short-lived failures of pods that no longer exist
+7. If Argo Rollouts is installed, check rollouts: attribute post-deploy instability to in-progress canaries or failed analysis runs instead of reporting it as generic instability
+8. Check image pull failures: distinguish a registry-wide outage (many images from one registry failing to connect or rate-limited) from single bad tags or missing pull credentials
+9. For each failing workload, check its inferred dependencies and add a short blast-radius note naming the workloads that depend on it (e.g., "checkout depends on failing redis")
+10. Audit Pod Security Admission levels and pod security contexts
+11. Check deprecated Kubernetes API usage (Prometheus) and mention APIs removed in upcoming releases as upgrade blockers
+12. Correlate node reboots, kernel panics and kubelet restarts (see the verified statistics) with pod failures on those nodes; a node stability table is appended to the report automatically
+13. If cert-manager is installed, check certificates: report those not ready, failing to renew or expiring within 30 days, even when the TLS secret is still valid
+14. Use the causal hints in the verified statistics (restarts shortly after a warning event) as starting points for root causes, and confirm them with the tools before stating them as the cause
+15. System components (CoreDNS, CNI, metrics-server...) in the verified statistics are covered by an automatic "Control Plane & Add-ons" section; only mention them elsewhere when an add-on failure explains application issues
+16. When node pools are listed in the verified statistics, give capacity recommendations per pool (e.g., "scale the workers node group to 5 nodes") instead of for the cluster as a whole
+17. Check the control plane line of the verified statistics: failing API server checks, unhealthy etcd or high API server latency explain cluster-wide symptoms (probe timeouts, slow rollouts, controllers lagging) better than per-workload causes; a control plane health note is appended automatically
+18. Use the container terminations by failure class in the verified statistics to name the kind of crash loop: OOM (137/OOMKilled) needs memory limits or a leak fix, segfaults (139) and aborts (134) are application/library bugs, exit code 1 usually means bad configuration or a missing dependency, 126/127 a wrong image or command; a 137 without OOMKilled can also be a failed liveness probe. A failure class chart is appended automatically
+19. Namespaces and workloads listed as opted out in the verified statistics (watchdog.helmcode.com/ignore annotation) are experiments or chaos-engineering targets: do not investigate or report on them; an appendix listing them is added automatically
+20. Mention end-of-life or outdated add-ons from the verified statistics (CoreDNS, CNI, ingress controller...) as upgrade work in the recommendations; a platform hygiene table with every add-on version is appended automatically
+21. Report broken admission webhooks from the verified statistics as cluster-wide issues: with failurePolicy Fail, a webhook that times out or has no ready endpoints blocks every create or update it intercepts (FailedCreate on ReplicaSets and Jobs), so rollouts silently stall even though running pods look healthy. Name the webhook, its backing service and the namespaces affected
+22. Correlate instability with the Deployment rollouts of the week listed in the verified statistics: when restarts, errors or latency start right after a rollout, name the rollout (revision, change-cause, image change) as the likely trigger and suggest a rollback if it is still failing
+23. Check the image pull secrets and service account tokens in the verified statistics: a missing or expired pull secret breaks the next pull (new node, rescheduled pod) even if running pods look fine, and short-lived registry tokens (ECR, GCR) need a refresh job. Recommend deleting unused pull secrets and orphaned or unused long-lived service account tokens, and moving the rest to projected tokens
+24. Rank issues with the event severities in the verified statistics (a fixed mapping of event reasons, adjusted by the operators): critical reasons (NodeNotReady, Evicted, FailedCreate, FailedAttachVolume...) come before warnings, and info-level reasons are context rather than issues on their own
+25. Judge each workload class in the verified statistics on its own terms: a few failed Jobs or batch restarts are routine churn, while a single not ready stateless or stateful service is a regression. Never let batch noise push a service issue out of the main issues, and treat stateful workloads (databases, queues) as the most critical
+26. Combine the endpoint probes in the verified statistics with the passive state: an endpoint that is down or below 99% success this week is a user-facing issue even when its pods look healthy (check its Service endpoints, ingress and network policies), and healthy-looking probes with unhealthy pods mean the failures are not yet visible to users. Mention rising p95 latency as a risk
+27. If the verified statistics say the snapshot is incomplete, name the missing sections in the summary, investigate them with the tools if you can, and never describe their area as healthy from the absence of data
+
+WINDOWS NODES:
+- Nodes and pods report their OS; mixed clusters may run Windows nodes (containerd on Windows Server)
+- Windows containers are not OOMKilled like Linux containers: memory limits are enforced through job objects, so high memory shows up as paging or failed allocations rather than exit code 137
+- Windows pods cannot run on Linux nodes (and vice versa); treat scheduling failures caused by OS node selectors as configuration issues, not capacity problems
+- Do not compare Windows workloads' resource usage against Linux-only Prometheus metrics (e.g., cAdvisor metrics may be missing for Windows)
 
 YOUR REPORT MUST INCLUDE EXACTLY 4 SECTIONS:
 
@@ -47,6 +121,12 @@ YOUR REPORT MUST INCLUDE EXACTLY 4 SECTIONS:
    - Problem description (1 line)
    - Impact (1 line)
    - Recommended action (1 line)
+   Group the issues by workload class (stateless services, stateful services, batch jobs, system workloads),
+   services first; omit classes without issues
+   If there are image pull failures, add an "Image pull failures" subsection grouping them by registry:
+   registry-wide outages first, then individual images with the failure type (not found, auth, rate limited)
+   Add a "Security" subsection from the pod security audit: namespaces enforcing the privileged
+   Pod Security level (or no level), and the most exposed pods (privileged, host namespaces, missing runAsNonRoot)
 
 3. RESOURCE OPTIMIZATION (Concise)
    - Over-provisioned pods: List with actual vs requested resources
@@ -116,6 +196,28 @@ EXAMPLE STRUCTURE:
 </body>
 </html>
 
+STRUCTURED DATA BLOCK:
+Inside <head>, include exactly one machine-readable block with the raw data behind your analysis:
+<script type="application/json" id="watchdog-data">
+{{
+  "health_status": "green | yellow | red",
+  "high_restart_pods": [{{"namespace": "...", "pod": "...", "restarts": 0, "reason": "..."}}],
+  "rightsizing": [{{"namespace": "...", "workload": "...", "resource": "cpu | memory", "request": "...", "limit": "...", "usage": "...", "recommendation": "..."}}],
+  "events_summary": [{{"namespace": "...", "reason": "...", "object": "...", "count": 0}}],
+  "findings": [{{"kind": "Deployment | StatefulSet | DaemonSet | Node | PersistentVolumeClaim | ...", "namespace": "...", "resource": "...", "reason": "CrashLoopBackOff | OOMKilled | ImagePullBackOff | ...", "severity": "critical | high | medium", "title": "..."}}],
+  "recommendations": [{{"category": "rightsizing | reliability | security | configuration | capacity", "namespace": "...", "target": "workload or node name", "action": "..."}}],
+  "recommendation_followup": [{{"id": 0, "status": "resolved | open", "evidence": "..."}}]
+}}
+</script>
+- Use only values obtained from the tools; leave a list empty when there is no data for it
+- "findings" lists every issue reported in MAIN ISSUES, one per affected resource and reason.
+  Name the owning workload (Deployment, StatefulSet...) rather than the pod, and use the Kubernetes reason
+  verbatim, so the same issue gets the same identity in next week's report
+- "recommendations" lists every action recommended in the report, one per target
+- "recommendation_followup" is only filled when previous recommendations are provided in the request
+- The block must be valid JSON (double quotes, no comments, no trailing commas)
+- This block is removed before rendering, so do not reference it in the visible report
+
 REPORT FOOTER:
 Include at the end of the HTML (before </body>) a footer with the following text (in the report language):
 "Report automatically generated by Watchdog AI using Kubernetes API and observability tools.
@@ -124,5 +226,72 @@ For status updates, run: kubectl get pods,nodes -A
 
 Be specific with pod/node names (in code tags). Focus on actionable insights.
 Use emojis for health indicators. Make the design professional and visually attractive.
+{context_instruction}
+{thresholds_instruction}
+{offline_instruction}
+{privacy_instruction}
 {language_instruction}
 """
+
+
+def get_namespace_deep_dive_prompt(cluster_name: str, namespace: str, since_hours: int) -> str:
+    """Generate the user prompt for an ad hoc namespace deep-dive report.
+
+    Args:
+        cluster_name: Name of the Kubernetes cluster
+        namespace: Namespace to investigate
+        since_hours: Time window to analyze
+
+    Returns:
+        User prompt string
+    """
+    return f"""Generate a focused deep-dive report on namespace {namespace} of cluster {cluster_name},
+covering the last {since_hours} hours.
+
+This replaces the cluster-wide weekly summary: keep the same 4 sections, but scope every tool call
+to namespace {namespace} and go to pod level:
+1. List every pod with status, restarts and node
+2. Include the recent events of the namespace (warnings first) within the time window
+3. For failing or restarting pods, describe them and include short log excerpts (previous container
+   logs for crashes) inside <pre> blocks - only the lines that explain the failure. For pods that no
+   longer exist, use the recorded pod transitions with include_details for the state captured when
+   they failed
+4. Query Prometheus with range [{since_hours}h] for usage vs requests/limits of each workload
+5. Check the namespace's workload dependencies and who is affected by its failures
+
+Use the report title "Namespace deep-dive: {namespace}" and state the time window in the header.
+
+CRITICAL - RESPONSE FORMAT:
+- Return ONLY the HTML code of the report
+- DO NOT include any explanatory text, comments, or messages before or after the HTML
+- DO NOT write phrases like "I see that...", "I'll proceed...", "Here is..."
+- Your response must start directly with <!DOCTYPE html> or <html>
+- If any tool is unavailable, simply omit that section from the report without mentioning it in the HTML
+"""
+
+
+def get_rollup_prompt(cluster_name: str, period: str, summary_text: str, language: str = "spanish") -> str:
+    """Generate the prompt for the short narrative of a monthly/quarterly rollup.
+
+    Args:
+        cluster_name: Name of the Kubernetes cluster
+        period: "month" or "quarter"
+        summary_text: Output of format_rollup_for_prompt()
+        language: Language of the narrative
+
+    Returns:
+        Prompt string
+    """
+    return f"""Below are the figures of the weekly health reports of cluster {cluster_name} over the last {period}.
+They were computed from stored data and are exact; do not recompute or contradict them.
+
+{summary_text}
+
+Write a short executive narrative (2-3 paragraphs, at most 200 words) in {language} about the trend:
+whether health improved or degraded, how capacity grew, and which recurring findings deserve attention.
+
+CRITICAL - RESPONSE FORMAT:
+- Return ONLY the plain text paragraphs, separated by a blank line
+- No HTML, no markdown, no headings, no preamble
+- Do not call any tools; everything you need is above
+"""
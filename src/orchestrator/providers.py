@@ -0,0 +1,123 @@
+"""Recorded-response providers to run the analyzer without calling Claude Code.
+
+A provider returns the same JSON object `claude -p --output-format json`
+prints, so prompt building, response parsing and validation run unchanged.
+Outputs are recorded as JSON lines with LLM_RECORD_PATH and replayed with
+LLM_FIXTURE_PATH, or built in code with fake_output() for downstream tests.
+"""
+
+import json
+from typing import Optional, Protocol
+
+import structlog
+
+logger = structlog.get_logger()
+
+
+class LLMProvider(Protocol):
+    """Produces the analyzer's model output for a prompt."""
+
+    async def run(self, prompt: str, model: str, mcp_config_path: str, prompt_path: str) -> dict:
+        """Return a Claude Code JSON output for the prompt."""
+        ...
+
+
+def fake_output(
+    report_html: str,
+    report_data: Optional[dict] = None,
+    num_turns: int = 1,
+    cost_usd: float = 0.0,
+) -> dict:
+    """Build a Claude Code JSON output around a report.
+
+    Args:
+        report_html: HTML document the fake model returns
+        report_data: Structured data block to embed in <head> (omitted when None)
+        num_turns: Reported number of turns
+        cost_usd: Reported cost
+
+    Returns:
+        Output dict as printed by `claude -p --output-format json`
+    """
+    if report_data is not None:
+        block = (
+            '<script type="application/json" id="watchdog-data">'
+            f"{json.dumps(report_data)}</script>"
+        )
+        report_html = report_html.replace("</head>", f"{block}</head>", 1)
+
+    return {
+        "type": "result",
+        "subtype": "success",
+        "result": report_html,
+        "num_turns": num_turns,
+        "cost_usd": cost_usd,
+        "session_id": "fixture",
+        "usage": {"input_tokens": 0, "output_tokens": 0},
+    }
+
+
+class FixtureProvider:
+    """Replay recorded outputs in order, keeping the prompts it was called with."""
+
+    def __init__(self, outputs: list[dict]) -> None:
+        """Initialize the provider.
+
+        Args:
+            outputs: Claude Code outputs, one per expected call
+        """
+        self.outputs = list(outputs)
+        self.calls: list[dict] = []
+
+    @classmethod
+    def from_file(cls, path: str) -> "FixtureProvider":
+        """Load outputs recorded with record_output().
+
+        Args:
+            path: JSON lines file
+
+        Returns:
+            FixtureProvider replaying the file
+        """
+        outputs = []
+        with open(path, encoding="utf-8") as fixture_file:
+            for line in fixture_file:
+                if line.strip():
+                    entry = json.loads(line)
+                    outputs.append(entry.get("output", entry))
+
+        logger.info("llm_fixture_loaded", path=path, outputs=len(outputs))
+
+        return cls(outputs)
+
+    async def run(self, prompt: str, model: str, mcp_config_path: str, prompt_path: str) -> dict:
+        """Return the next recorded output.
+
+        Raises:
+            RuntimeError: If more calls are made than outputs were recorded
+        """
+        with open(prompt_path, encoding="utf-8") as prompt_file:
+            system_prompt = prompt_file.read()
+        self.calls.append({"prompt": prompt, "system_prompt": system_prompt, "model": model})
+
+        if len(self.calls) > len(self.outputs):
+            raise RuntimeError(
+                f"LLM fixture exhausted: call {len(self.calls)} but only {len(self.outputs)} outputs recorded"
+            )
+
+        return self.outputs[len(self.calls) - 1]
+
+
+def record_output(path: str, prompt: str, model: str, output: dict) -> None:
+    """Append a Claude Code output to a fixture file.
+
+    Args:
+        path: JSON lines file
+        prompt: User prompt of the call
+        model: Model of the call
+        output: Claude Code JSON output
+    """
+    with open(path, "a", encoding="utf-8") as fixture_file:
+        fixture_file.write(json.dumps({"prompt": prompt, "model": model, "output": output}) + "\n")
+
+    logger.info("llm_output_recorded", path=path)
@@ -0,0 +1,52 @@
+from typing import Optional
+
+import structlog
+
+from src.config import settings
+
+logger = structlog.get_logger()
+
+
+def select_model(cluster_stats: Optional[dict], month_spend_usd: float) -> tuple[str, str]:
+    """Pick the Claude model for this report based on cluster size and activity.
+
+    Small, quiet clusters use the small model; large clusters or incident-heavy
+    weeks upgrade to the large model as long as this month's spend stays under
+    the upgrade budget. Everything else uses ANTHROPIC_MODEL.
+
+    Args:
+        cluster_stats: Deterministic cluster statistics (None if unavailable)
+        month_spend_usd: LLM spend of reports generated this month
+
+    Returns:
+        Tuple of (model name, human-readable reason)
+    """
+    if not settings.model_routing_enabled:
+        return settings.anthropic_model, "routing disabled"
+
+    if not cluster_stats:
+        return settings.anthropic_model, "no cluster statistics available"
+
+    total_pods = cluster_stats["total_pods"]
+    restarts = cluster_stats.get("restarts_this_week")
+    if restarts is None:
+        restarts = cluster_stats["total_restarts"]
+
+    is_large = total_pods >= settings.model_routing_large_min_pods
+    is_incident_heavy = restarts >= settings.model_routing_incident_min_restarts
+
+    if is_large or is_incident_heavy:
+        trigger = f"{total_pods} pods" if is_large else f"{restarts} restarts"
+        budget = settings.model_routing_upgrade_budget_usd
+        if budget and month_spend_usd >= budget:
+            return (
+                settings.anthropic_model,
+                f"{trigger}, but monthly upgrade budget reached "
+                f"(${month_spend_usd:.2f} of ${budget:.2f})",
+            )
+        return settings.model_routing_large_model, f"upgraded: {trigger}"
+
+    if total_pods <= settings.model_routing_small_max_pods:
+        return settings.model_routing_small_model, f"small cluster: {total_pods} pods"
+
+    return settings.anthropic_model, f"default: {total_pods} pods"
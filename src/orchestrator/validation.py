@@ -0,0 +1,124 @@
+from html.parser import HTMLParser
+
+HEALTH_STATUSES = {"green", "yellow", "red"}
+REPORT_DATA_LISTS = [
+    "high_restart_pods", "rightsizing", "events_summary",
+    "findings", "recommendations", "recommendation_followup",
+]
+
+# Elements that must be properly closed for the PDF renderer to lay out sections
+STRUCTURAL_TAGS = {"html", "head", "body", "div", "table", "ul", "ol"}
+VOID_TAGS = {
+    "area", "base", "br", "col", "embed", "hr", "img", "input",
+    "link", "meta", "source", "track", "wbr",
+}
+
+
+class _StructureChecker(HTMLParser):
+    """Track unclosed structural elements in an HTML document."""
+
+    def __init__(self) -> None:
+        super().__init__()
+        self.open_tags: list[str] = []
+        self.seen: set[str] = set()
+
+    def handle_starttag(self, tag: str, attrs: list) -> None:
+        self.seen.add(tag)
+        if tag in VOID_TAGS:
+            return
+        self.open_tags.append(tag)
+
+    def handle_endtag(self, tag: str) -> None:
+        # Pop up to the matching tag, tolerating implicitly closed elements (p, li, td...)
+        if tag in self.open_tags:
+            while self.open_tags and self.open_tags.pop() != tag:
+                pass
+
+
+def validate_report_html(report_html: str) -> list[str]:
+    """Check that the generated report is a complete, renderable HTML document.
+
+    Args:
+        report_html: HTML report (after preamble and data block removal)
+
+    Returns:
+        List of human-readable problems (empty when valid)
+    """
+    problems = []
+    stripped = report_html.strip()
+
+    if not stripped:
+        return ["the response is empty"]
+
+    if "```" in stripped:
+        problems.append("the HTML is wrapped in or contains markdown code fences")
+
+    if not stripped.lower().endswith("</html>"):
+        problems.append("the document does not end with </html> (it looks truncated)")
+
+    checker = _StructureChecker()
+    checker.feed(stripped)
+    checker.close()
+
+    for tag in ("html", "head", "body"):
+        if tag not in checker.seen:
+            problems.append(f"the <{tag}> element is missing")
+
+    unclosed = [tag for tag in checker.open_tags if tag in STRUCTURAL_TAGS]
+    if unclosed:
+        problems.append(f"unclosed elements: {', '.join(f'<{t}>' for t in unclosed)}")
+
+    return problems
+
+
+def validate_report_data(report_data: dict) -> list[str]:
+    """Check the structured data block against the expected schema.
+
+    Args:
+        report_data: Parsed data block ({} when missing or not valid JSON)
+
+    Returns:
+        List of human-readable problems (empty when valid)
+    """
+    if not report_data:
+        return ['the <script type="application/json" id="watchdog-data"> block is missing or is not valid JSON']
+
+    problems = []
+
+    if report_data.get("health_status") not in HEALTH_STATUSES:
+        problems.append('"health_status" must be one of "green", "yellow" or "red"')
+
+    for key in REPORT_DATA_LISTS:
+        value = report_data.get(key, [])
+        if not isinstance(value, list) or not all(isinstance(row, dict) for row in value):
+            problems.append(f'"{key}" must be a list of objects')
+
+    return problems
+
+
+def build_correction_prompt(previous_response: str, problems: list[str]) -> str:
+    """Build a re-prompt asking Claude to fix a malformed report.
+
+    Args:
+        previous_response: Raw result of the rejected attempt
+        problems: Problems found by validation
+
+    Returns:
+        Corrective user prompt
+    """
+    problem_list = "\n".join(f"- {problem}" for problem in problems)
+
+    return f"""Your previous report was rejected by the automated validator for these reasons:
+{problem_list}
+
+Fix these problems and return the corrected complete report. Keep the same findings and data;
+do not investigate the cluster again.
+
+CRITICAL - RESPONSE FORMAT:
+- Return ONLY the HTML code of the report, starting with <!DOCTYPE html> and ending with </html>
+- Include the <script type="application/json" id="watchdog-data"> block inside <head>
+- DO NOT include any explanatory text before or after the HTML
+
+PREVIOUS RESPONSE:
+{previous_response}
+"""
@@ -0,0 +1,52 @@
+from html import escape
+
+STATUS_STYLES = {
+    "eol": ("End of life", "color:#C00000;"),
+    "outdated": ("Outdated", "color:#A15C00;"),
+    "unknown": ("Unknown version", "color:#555;"),
+    "ok": ("Supported", "color:#1E7B3C;"),
+}
+
+
+def build_platform_hygiene_section(inventory: list[dict]) -> str:
+    """Render the add-on version inventory (CoreDNS, CNI, ingress controller...).
+
+    Args:
+        inventory: Output of collect_addon_inventory(), problems first
+
+    Returns:
+        HTML section, or an empty string when no known add-on was found
+    """
+    if not inventory:
+        return ""
+
+    stale = [a for a in inventory if a["status"] in ("eol", "outdated")]
+    if stale:
+        summary = f"{len(stale)} of {len(inventory)} add-ons are end of life or older than the supported releases."
+    else:
+        summary = f"All {len(inventory)} detected add-ons run supported versions."
+
+    rows = []
+    for addon in inventory:
+        label, style = STATUS_STYLES[addon["status"]]
+        rows.append(
+            "<tr>"
+            f'<td style="padding:6px;">{escape(addon["addon"])}</td>'
+            f'<td style="padding:6px;"><code>{escape(addon["namespace"])}/{escape(addon["workload"])}</code></td>'
+            f'<td style="padding:6px;">{escape(addon["version"] or "-")}</td>'
+            f'<td style="padding:6px;">{escape(addon["minimum_version"] or "-")}</td>'
+            f'<td style="padding:6px;font-weight:600;{style}">{label}</td>'
+            f'<td style="padding:6px;font-size:12px;color:#555;">{escape(addon["note"] or "")}</td>'
+            "</tr>"
+        )
+
+    return f"""<div class="section watchdog-platform-hygiene">
+  <h2>Platform Hygiene</h2>
+  <p>{escape(summary)}</p>
+  <table style="border-collapse:collapse;width:100%;">
+    <thead><tr><th style="padding:6px;text-align:left;">Add-on</th><th style="padding:6px;text-align:left;">Workload</th><th style="padding:6px;text-align:left;">Version</th><th style="padding:6px;text-align:left;">Oldest supported</th><th style="padding:6px;text-align:left;">Status</th><th style="padding:6px;text-align:left;">Note</th></tr></thead>
+    <tbody>
+      {"".join(rows)}
+    </tbody>
+  </table>
+</div>"""
@@ -0,0 +1,147 @@
+from collections import defaultdict
+from typing import Optional
+from urllib.parse import parse_qs
+
+# Slack section text is limited to 3000 characters
+MAX_ROWS = 15
+
+USAGE = (
+    "Usage:\n"
+    "• `/k8s top-restarts` – pods with the most container restarts in the last 7 days\n"
+    "• `/k8s nodes` – node stability and usage from the latest report\n"
+    "• `/k8s events <namespace>` – warning events of the latest report"
+)
+
+
+def parse_slash_command(body: bytes) -> dict:
+    """Parse a slash command request.
+
+    Args:
+        body: Raw form-encoded request body
+
+    Returns:
+        Dict with subcommand, args and user_id
+    """
+    form = parse_qs(body.decode("utf-8"))
+    words = (form.get("text", [""])[0]).split()
+
+    return {
+        "subcommand": words[0].lower() if words else "",
+        "args": words[1:],
+        "user_id": form.get("user_id", [None])[0],
+    }
+
+
+def _format_table(headers: list[str], rows: list[list]) -> str:
+    """Render rows as a fixed-width text table inside a code block."""
+    cells = [[str(value) if value is not None else "-" for value in row] for row in rows[:MAX_ROWS]]
+    widths = [max(len(h), *(len(row[i]) for row in cells)) if cells else len(h) for i, h in enumerate(headers)]
+    lines = ["  ".join(h.ljust(w) for h, w in zip(headers, widths)).rstrip()]
+    lines += ["  ".join(value.ljust(w) for value, w in zip(row, widths)).rstrip() for row in cells]
+    return "```\n" + "\n".join(lines) + "\n```"
+
+
+def _response(title: str, body: str) -> dict:
+    """Build an ephemeral Block Kit response."""
+    return {
+        "response_type": "ephemeral",
+        "blocks": [
+            {"type": "header", "text": {"type": "plain_text", "text": title}},
+            {"type": "section", "text": {"type": "mrkdwn", "text": body}},
+        ],
+    }
+
+
+def top_restarts_response(restart_rows: list[dict], cluster_stats: Optional[dict]) -> dict:
+    """Answer `/k8s top-restarts`.
+
+    Args:
+        restart_rows: Output of ReportStorage.get_restarts_by_day()
+        cluster_stats: Statistics of the latest report (fallback without pod watcher data)
+
+    Returns:
+        Slack response payload
+    """
+    if restart_rows:
+        totals: dict[tuple[str, str], int] = defaultdict(int)
+        for row in restart_rows:
+            totals[(row["namespace"], row["pod"])] += row["restarts"]
+        top = sorted(totals.items(), key=lambda item: item[1], reverse=True)
+        table = _format_table(
+            ["NAMESPACE", "POD", "RESTARTS (7d)"],
+            [[namespace, pod, restarts] for (namespace, pod), restarts in top],
+        )
+        return _response("Top restarting pods", table)
+
+    # Collected from the cluster, not from the block the model writes
+    pods = ((cluster_stats or {}).get("appendix") or {}).get("restarting_pods") or []
+    if not pods:
+        return _response("Top restarting pods", "No restart data recorded yet.")
+
+    table = _format_table(
+        ["NAMESPACE", "POD", "RESTARTS", "REASON"],
+        [[p.get("namespace"), p.get("pod"), p.get("restarts"), p.get("reason")] for p in pods],
+    )
+    return _response("Top restarting pods (latest report)", table)
+
+
+def nodes_response(cluster_stats: Optional[dict]) -> dict:
+    """Answer `/k8s nodes`.
+
+    Args:
+        cluster_stats: Statistics of the latest report
+
+    Returns:
+        Slack response payload
+    """
+    stability = (cluster_stats or {}).get("node_stability") or []
+    if not stability:
+        return _response("Nodes", "No node data in the latest report.")
+
+    usage = {n["node"]: n for n in (cluster_stats.get("node_usage") or [])}
+    table = _format_table(
+        ["NODE", "SCORE", "READY", "REBOOTS", "CPU %", "MEM %"],
+        [
+            [
+                n["node"],
+                n["stability_score"],
+                "yes" if n["ready"] else "no",
+                n["reboots"],
+                usage.get(n["node"], {}).get("cpu_pct"),
+                usage.get(n["node"], {}).get("memory_pct"),
+            ]
+            for n in stability
+        ],
+    )
+    return _response(f"Nodes ({cluster_stats['ready_nodes']}/{cluster_stats['total_nodes']} ready)", table)
+
+
+def events_response(cluster_stats: Optional[dict], namespace: Optional[str]) -> dict:
+    """Answer `/k8s events [namespace]`.
+
+    Args:
+        cluster_stats: Statistics of the latest report
+        namespace: Only show events of this namespace
+
+    Returns:
+        Slack response payload
+    """
+    events = [
+        e for e in ((cluster_stats or {}).get("appendix") or {}).get("warning_events") or []
+        if not namespace or e.get("namespace") == namespace
+    ]
+    title = f"Events in {namespace}" if namespace else "Events"
+    if not events:
+        return _response(title, "No warning events in the latest report.")
+
+    events.sort(key=lambda e: e.get("count") or 0, reverse=True)
+    table = _format_table(
+        ["NAMESPACE", "REASON", "OBJECT", "COUNT"],
+        [[e.get("namespace"), e.get("reason"), e.get("object"), e.get("count")] for e in events],
+    )
+    return _response(title, table)
+
+
+def usage_response() -> dict:
+    """Answer unknown subcommands with the list of supported ones."""
+    return _response("K8s Watchdog commands", USAGE)
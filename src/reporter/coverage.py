@@ -0,0 +1,68 @@
+from html import escape
+from typing import Optional
+
+
+def _delta_style(current: Optional[float], previous: Optional[float]) -> str:
+    """Color a percentage green when it improved (dropped) and red when it got worse."""
+    if current is None or previous is None or current == previous:
+        return "color:#1A1A1A;"
+    return "color:#1E7B3C;" if current < previous else "color:#C00000;"
+
+
+def build_coverage_section(
+    coverage: list[dict],
+    history: list[list[dict]],
+    max_namespaces: int = 15,
+) -> str:
+    """Render the request/limit coverage compliance table with its weekly trend.
+
+    Args:
+        coverage: Current per-namespace coverage (stats["resource_coverage"])
+        history: Coverage of previous weekly reports, newest first
+        max_namespaces: Maximum number of rows, worst first
+
+    Returns:
+        HTML section, or an empty string when there is no coverage data
+    """
+    if not coverage:
+        return ""
+
+    # namespace -> missing requests % per previous report, oldest first
+    trends: dict[str, list[Optional[float]]] = {}
+    for previous in reversed(history[:4]):
+        by_namespace = {c["namespace"]: c["missing_requests_pct"] for c in previous}
+        for item in coverage:
+            trends.setdefault(item["namespace"], []).append(by_namespace.get(item["namespace"]))
+
+    total = sum(c["containers"] for c in coverage)
+    missing_requests = sum(c["missing_requests"] for c in coverage)
+    missing_limits = sum(c["missing_limits"] for c in coverage)
+
+    rows = []
+    for item in coverage[:max_namespaces]:
+        previous_values = trends.get(item["namespace"], [])
+        last = previous_values[-1] if previous_values else None
+        trend = " → ".join(
+            "–" if value is None else f"{value}%" for value in previous_values + [item["missing_requests_pct"]]
+        )
+        rows.append(
+            "<tr>"
+            f'<td style="padding:6px;"><code>{escape(item["namespace"])}</code></td>'
+            f'<td style="padding:6px;text-align:right;">{item["containers"]}</td>'
+            f'<td style="padding:6px;text-align:right;font-weight:600;{_delta_style(item["missing_requests_pct"], last)}">'
+            f'{item["missing_requests_pct"]}%</td>'
+            f'<td style="padding:6px;text-align:right;">{item["missing_limits_pct"]}%</td>'
+            f'<td style="padding:6px;font-size:12px;color:#555;">{escape(trend)}</td>'
+            "</tr>"
+        )
+
+    return f"""<div class="section watchdog-coverage">
+  <h2>Resource Requests &amp; Limits Coverage</h2>
+  <p>{missing_requests} of {total} running containers have no CPU/memory requests and {missing_limits} have no limits.</p>
+  <table style="border-collapse:collapse;width:100%;">
+    <thead><tr><th style="padding:6px;text-align:left;">Namespace</th><th style="padding:6px;text-align:right;">Containers</th><th style="padding:6px;text-align:right;">Missing requests</th><th style="padding:6px;text-align:right;">Missing limits</th><th style="padding:6px;text-align:left;">Missing requests trend</th></tr></thead>
+    <tbody>
+      {"".join(rows)}
+    </tbody>
+  </table>
+</div>"""
@@ -0,0 +1,97 @@
+import csv
+from io import StringIO
+from typing import Any, Optional
+
+import structlog
+
+logger = structlog.get_logger()
+
+# Appendix table (cluster_stats["appendix"]) -> (CSV filename suffix, column order)
+CSV_TABLES = {
+    "restarting_pods": (
+        "high-restart-pods",
+        ["namespace", "pod", "restarts", "reason"],
+    ),
+    "rightsizing": (
+        "rightsizing",
+        [
+            "namespace", "workload", "resource", "pods",
+            "request", "limit", "usage", "usage_pct_of_request",
+        ],
+    ),
+    "warning_events": (
+        "events-summary",
+        ["namespace", "reason", "object", "count", "last_seen"],
+    ),
+}
+
+# Leading characters that make spreadsheets evaluate a cell as a formula
+FORMULA_PREFIXES = ("=", "+", "-", "@", "\t", "\r")
+
+
+def _escape_cell(value: Any) -> Any:
+    """Neutralize text cells that a spreadsheet would run as a formula.
+
+    Pod names, event reasons and object names come from the cluster, so a
+    value such as =HYPERLINK(...) is quoted instead of evaluated.
+    """
+    if isinstance(value, str) and value.startswith(FORMULA_PREFIXES):
+        return f"'{value}"
+    return value
+
+
+def appendix_tables(
+    cluster_stats: Optional[dict], namespace: Optional[str] = None
+) -> dict[str, list[dict]]:
+    """Select the appendix tables of a report from the collected statistics.
+
+    Args:
+        cluster_stats: Output of collect_cluster_stats()
+        namespace: Keep only this namespace's rows (namespace deep-dives)
+
+    Returns:
+        Table name -> rows
+    """
+    tables = (cluster_stats or {}).get("appendix") or {}
+    if namespace:
+        tables = {
+            key: [row for row in rows if row.get("namespace") == namespace]
+            for key, rows in tables.items()
+        }
+    return tables
+
+
+def build_csv_attachments(tables: dict, basename: str) -> list[tuple[str, bytes]]:
+    """Build CSV appendices from the collected appendix tables.
+
+    Args:
+        tables: Output of appendix_tables()
+        basename: Filename prefix shared with the PDF (without extension)
+
+    Returns:
+        List of (filename, CSV bytes) tuples, one per non-empty table
+    """
+    attachments = []
+
+    for key, (suffix, columns) in CSV_TABLES.items():
+        rows = tables.get(key) or []
+        if not isinstance(rows, list):
+            logger.warning("csv_table_invalid", table=key)
+            continue
+
+        rows = [row for row in rows if isinstance(row, dict)]
+        if not rows:
+            continue
+
+        buffer = StringIO()
+        writer = csv.DictWriter(buffer, fieldnames=columns, extrasaction="ignore")
+        writer.writeheader()
+        writer.writerows(
+            {column: _escape_cell(value) for column, value in row.items()} for row in rows
+        )
+
+        attachments.append((f"{basename}-{suffix}.csv", buffer.getvalue().encode("utf-8")))
+
+    logger.info("csv_attachments_built", count=len(attachments))
+
+    return attachments
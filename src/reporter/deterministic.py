@@ -0,0 +1,200 @@
+from html import escape
+from typing import Optional
+
+from src.config import settings
+
+# Invalid model reports can be long; the statistics are the reliable part
+MAX_MODEL_TEXT_LENGTH = 20000
+
+HEALTH_LABELS = {"green": "🟢 Healthy", "yellow": "🟡 Needs attention", "red": "🔴 Critical"}
+PULL_SECRET_PROBLEMS = {"missing": "missing", "expired": "expired", "auth_failures": "rejected by the registry"}
+
+
+def _issues(stats: dict) -> list[tuple[str, str]]:
+    """List (severity, text) issues that follow from the statistics alone."""
+    issues = []
+    not_running = stats["total_pods"] - stats["running_pods"]
+    if not_running:
+        phases = ", ".join(
+            f"{phase}={count}" for phase, count in stats["pods_by_phase"].items() if phase != "Running"
+        )
+        issues.append(("high", f"{not_running} of {stats['total_pods']} pods are not running ({phases})"))
+    if stats["ready_nodes"] < stats["total_nodes"]:
+        not_ready = stats["total_nodes"] - stats["ready_nodes"]
+        issues.append(("critical", f"{not_ready} of {stats['total_nodes']} nodes are not ready"))
+    for reason in (stats.get("event_severities") or {}).get("reasons", []):
+        if reason["severity"] == "critical":
+            issues.append(("critical", f"{reason['events']} {reason['reason']} events"))
+    if stats.get("restarts_this_week"):
+        issues.append(("medium", f"{stats['restarts_this_week']} container restarts in the last 7 days"))
+    for failure in (stats.get("exit_codes") or {}).get("classes", []):
+        workloads = ", ".join(w["workload"] for w in failure["workloads"])
+        issues.append(("high", f"{failure['label']}: {failure['count']} terminations ({workloads})"))
+    for incident in stats.get("node_incidents") or []:
+        # Past reboots are covered by the stability score; current conditions hit workloads now
+        if incident["conditions"] and incident["workloads"]:
+            workloads = ", ".join(w["workload"] for w in incident["workloads"][:5])
+            issues.append((
+                "critical" if not incident["ready"] else "high",
+                f"Node {incident['node']} ({', '.join(incident['conditions'])}) affects "
+                f"{incident['pods']} pods: {workloads}",
+            ))
+    for node in stats.get("node_stability") or []:
+        if node["stability_score"] < 100:
+            issues.append(("medium", f"Node {node['node']} stability score {node['stability_score']}"))
+    control_plane = stats.get("control_plane") or {}
+    for endpoint in ("readyz", "livez"):
+        checks = control_plane.get(endpoint)
+        if checks and not checks["ok"]:
+            issues.append(("critical", f"API server /{endpoint} failing: {', '.join(checks['failed'])}"))
+    for webhook in stats.get("webhook_failures") or []:
+        issues.append((
+            "critical" if webhook["failure_policy"] == "Fail" else "medium",
+            f"Admission webhook {webhook['webhook']} is failing ({webhook['cause']}, "
+            f"{webhook['failed_calls']} failed calls)",
+        ))
+    for secret in (stats.get("credential_risks") or {}).get("pull_secrets") or []:
+        if secret["status"] in PULL_SECRET_PROBLEMS and secret["pods"]:
+            issues.append((
+                "high",
+                f"Image pull secret {secret['namespace']}/{secret['secret']} is {PULL_SECRET_PROBLEMS[secret['status']]} "
+                f"({secret['pods']} pods reference it)",
+            ))
+    for component in stats.get("system_components") or []:
+        if not component["healthy"]:
+            issues.append((
+                "high",
+                f"System component {component['namespace']}/{component['name']} is "
+                f"{component['ready']}/{component['desired']} ready",
+            ))
+    for workload in stats.get("watched_workloads") or []:
+        if workload["ready"] < workload["desired"]:
+            issues.append((
+                "high",
+                f"Watched workload {workload['namespace']}/{workload['name']} is "
+                f"{workload['ready']}/{workload['desired']} ready",
+            ))
+    for missing in stats.get("missing_sections") or []:
+        issues.append((
+            "medium",
+            f"Statistics section {missing['section']} is missing: its collector {missing['reason']}",
+        ))
+    for probe in stats.get("endpoint_probes") or []:
+        if not probe["up"]:
+            issues.append(("critical", f"Endpoint {probe['name']} is down: {probe['error']}"))
+        elif probe["success_pct"] is not None and probe["success_pct"] < 99:
+            issues.append((
+                "medium",
+                f"Endpoint {probe['name']} answered {probe['success_pct']}% of probes this week",
+            ))
+    for threshold in stats.get("namespace_thresholds") or []:
+        if threshold["breached"]:
+            issues.append((
+                "high",
+                f"Namespace {threshold['namespace']}: {threshold['value']} {threshold['metric']}, "
+                f"above the configured threshold of {threshold['threshold']}",
+            ))
+    for addon in stats.get("addon_inventory") or []:
+        if addon["status"] in ("eol", "outdated"):
+            issues.append(("medium", f"{addon['addon']} {addon['version'] or ''} is {addon['status']}: {addon['note']}"))
+    return issues
+
+
+def _health_status(issues: list[tuple[str, str]]) -> str:
+    """Derive the traffic-light status from the most severe issue."""
+    severities = {severity for severity, _ in issues}
+    if "critical" in severities:
+        return "red"
+    if severities & {"high", "medium"}:
+        return "yellow"
+    return "green"
+
+
+def build_deterministic_report(
+    cluster_stats: Optional[dict],
+    notice: str,
+    fallback_reason: str,
+    model_text: Optional[str] = None,
+) -> tuple[str, dict]:
+    """Build a report from the collected statistics only, without calling the LLM.
+
+    Used when the monthly LLM budget is exhausted, or when the model's report
+    keeps failing validation; the computed sections (stats header, heatmap,
+    node pools...) are added afterwards as usual.
+
+    Args:
+        cluster_stats: Output of collect_cluster_stats(), or None when collection failed
+        notice: Why no AI analysis was performed
+        fallback_reason: "budget" (monthly LLM budget exhausted) or "validation"
+            (the model's report kept failing validation)
+        model_text: Plain text of an invalid model report, appended as unvalidated
+
+    Returns:
+        Tuple of (HTML document, metadata shaped like generate_weekly_report()'s)
+    """
+    issues = _issues(cluster_stats) if cluster_stats else []
+    health_status = _health_status(issues) if cluster_stats else "yellow"
+
+    if not cluster_stats:
+        issue_items = "<li>Cluster statistics could not be collected.</li>"
+    elif issues:
+        issue_items = "".join(
+            f"<li><strong>{escape(severity.upper())}</strong> {escape(text)}</li>" for severity, text in issues[:25]
+        )
+    else:
+        issue_items = "<li>No issue detected from the collected statistics.</li>"
+
+    class_rows = "".join(
+        f"<tr><td>{escape(c['label'])}</td><td>{c['workloads']}</td><td>{c['pods']}</td>"
+        f"<td>{c['not_ready']}</td><td>{c['failed']}</td><td>{c['restarts']}</td><td>{c['warnings']}</td></tr>"
+        for c in (cluster_stats or {}).get("workload_classes") or []
+    )
+    class_section = f"""<div class="section">
+  <h2>Health by Workload Class</h2>
+  <table>
+    <tr><th>Class</th><th>Workloads</th><th>Pods</th><th>Not ready</th><th>Failed</th><th>Restarts</th><th>Warnings</th></tr>
+    {class_rows}
+  </table>
+</div>
+""" if class_rows else ""
+
+    model_section = f"""<div class="section">
+  <h2>AI Analysis (Unvalidated)</h2>
+  <pre style="white-space:pre-wrap;">{escape(model_text[:MAX_MODEL_TEXT_LENGTH])}</pre>
+</div>
+""" if model_text else ""
+
+    report_html = f"""<!DOCTYPE html>
+<html>
+<head>
+<meta charset="UTF-8">
+<title>Kubernetes health report – {escape(settings.cluster_name)}</title>
+</head>
+<body>
+<div class="header"><h1>Kubernetes Health Report – {escape(settings.cluster_name)}</h1></div>
+<div class="section watchdog-fallback-notice" style="border:1px solid #A15C00;padding:8px;color:#A15C00;">
+  <p>{escape(notice)}</p>
+</div>
+<div class="section">
+  <h2>Executive Summary</h2>
+  <p>Health status: <strong>{HEALTH_LABELS[health_status]}</strong></p>
+</div>
+<div class="section">
+  <h2>Issues Detected From Statistics</h2>
+  <ul>{issue_items}</ul>
+</div>
+{class_section}{model_section}<div class="footer"><p>Generated by Watchdog AI - Helmcode</p></div>
+</body>
+</html>"""
+
+    metadata = {
+        "model": None,
+        "total_cost_usd": 0.0,
+        "deterministic": True,
+        "notice": notice,
+        "fallback_reason": fallback_reason,
+        "mcp_servers_used": [],
+        "report_data": {"health_status": health_status},
+    }
+
+    return report_html, metadata
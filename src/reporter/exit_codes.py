@@ -0,0 +1,63 @@
+from html import escape
+
+CLASS_COLORS = {
+    "oom": "#C00000",
+    "segfault": "#7A1FA2",
+    "abort": "#AD1457",
+    "config_error": "#A15C00",
+    "command_error": "#E67E22",
+    "terminated": "#1565C0",
+    "other": "#757575",
+}
+
+
+def build_exit_code_section(exit_codes: dict, max_codes: int = 10) -> str:
+    """Render the distribution of container terminations by failure class.
+
+    Args:
+        exit_codes: Output of summarize_exit_codes()
+        max_codes: Maximum number of exit codes listed under the chart
+
+    Returns:
+        HTML section, or an empty string when no container terminated
+    """
+    if not exit_codes.get("total"):
+        return ""
+
+    total = exit_codes["total"]
+    largest = max(c["count"] for c in exit_codes["classes"])
+    bars = []
+    for c in exit_codes["classes"]:
+        workloads = ", ".join(f'{w["workload"]} ({w["count"]})' for w in c["workloads"])
+        bars.append(
+            "<tr>"
+            f'<td style="padding:6px;white-space:nowrap;">{escape(c["label"])}</td>'
+            f'<td style="padding:6px;width:45%;">'
+            f'<div style="background:{CLASS_COLORS.get(c["class"], "#757575")};height:14px;'
+            f'width:{max(round(c["count"] / largest * 100), 2)}%;"></div></td>'
+            f'<td style="padding:6px;text-align:right;font-weight:600;">{c["count"]}</td>'
+            f'<td style="padding:6px;text-align:right;">{round(c["count"] / total * 100)}%</td>'
+            f'<td style="padding:6px;font-size:12px;color:#555;"><code>{escape(workloads)}</code></td>'
+            "</tr>"
+        )
+
+    codes = ", ".join(
+        f'{code["exit_code"]}' + (f' ({escape(str(code["signal"]))})' if code["signal"] else "") + f' &times;{code["count"]}'
+        for code in exit_codes["exit_codes"][:max_codes]
+    )
+    scope = (
+        "Terminations recorded by the pod watcher over the last 7 days"
+        if exit_codes["source"] == "watcher"
+        else "Last termination of each restarted container"
+    )
+
+    return f"""<div class="section watchdog-exit-codes">
+  <h2>Container Failures by Exit Code</h2>
+  <p>{scope}: {total}. Exit codes: {codes}.</p>
+  <table style="border-collapse:collapse;width:100%;">
+    <thead><tr><th style="padding:6px;text-align:left;">Failure class</th><th style="padding:6px;"></th><th style="padding:6px;text-align:right;">Count</th><th style="padding:6px;text-align:right;">Share</th><th style="padding:6px;text-align:left;">Most affected workloads</th></tr></thead>
+    <tbody>
+      {"".join(bars)}
+    </tbody>
+  </table>
+</div>"""
@@ -0,0 +1,117 @@
+from datetime import datetime
+from html import escape
+from typing import Callable, Optional
+
+SEVERITY_ORDER = {"critical": 0, "high": 1, "medium": 2}
+
+
+def _age_label(first_seen: Optional[str], now: datetime) -> str:
+    """Describe how long a finding has been open ('new', 'open for 3 weeks')."""
+    if not first_seen:
+        return "New this week"
+
+    days = (now - datetime.fromisoformat(str(first_seen))).days
+    if days < 7:
+        return "New this week" if days < 1 else f"Open for {days} days"
+    weeks = days // 7
+    return f"Open for {weeks} week{'s' if weeks > 1 else ''}"
+
+
+def build_findings_section(
+    findings: list[dict],
+    open_findings: dict[str, dict],
+    fingerprint: Callable[[dict], str],
+    now: Optional[datetime] = None,
+) -> str:
+    """Render the findings of this report as a diff against the previous one.
+
+    New findings are highlighted, ongoing ones carry an age badge and
+    findings of the previous report that are gone are listed as resolved
+    (green strikethrough).
+
+    Args:
+        findings: Findings from the report data block
+        open_findings: Findings still open after the previous report, by fingerprint
+            (output of ReportStorage.get_open_findings)
+        fingerprint: Function computing a finding's fingerprint
+        now: Reference time (defaults to now)
+
+    Returns:
+        HTML section, or an empty string when there is nothing to compare
+    """
+    now = now or datetime.now()
+
+    rows = []
+    seen = set()
+    for finding in findings:
+        if not finding.get("resource") or not finding.get("reason"):
+            continue
+        key = fingerprint(finding)
+        if key in seen:
+            continue
+        seen.add(key)
+        previous = open_findings.get(key) or {}
+        rows.append({
+            **finding,
+            "first_seen": previous.get("first_seen"),
+            "reports": (previous.get("occurrences") or 0) + 1,
+        })
+
+    resolved = [row for key, row in open_findings.items() if key not in seen]
+
+    if not rows and not resolved:
+        return ""
+
+    # Oldest and most severe first: chronic issues should not blend into the background
+    rows.sort(key=lambda r: (r["first_seen"] or now.isoformat(), SEVERITY_ORDER.get(r.get("severity"), 3)))
+    resolved.sort(key=lambda r: SEVERITY_ORDER.get(r.get("severity"), 3))
+
+    new = sum(1 for r in rows if r["reports"] == 1)
+    summary = (
+        f"{new} new, {len(rows) - new} ongoing and {len(resolved)} resolved "
+        "since the previous report."
+    )
+
+    html_rows = []
+    for row in rows:
+        resource = "/".join(part for part in (row.get("namespace"), row["resource"]) if part)
+        if row["reports"] == 1:
+            row_style = "background:#FFF4CC;"
+            badge = '<span style="background:#A15C00;color:white;padding:2px 8px;border-radius:10px;">NEW</span>'
+        else:
+            row_style = ""
+            badge = (
+                '<span style="background:#FEE;color:#C00000;padding:2px 8px;border-radius:10px;font-weight:600;">'
+                f"{escape(_age_label(row['first_seen'], now))}</span>"
+            )
+        html_rows.append(
+            f'<tr style="{row_style}">'
+            f'<td style="padding:6px;">{escape(row.get("title") or row["reason"])}</td>'
+            f'<td style="padding:6px;">{escape(row.get("kind") or "")} <code>{escape(resource)}</code></td>'
+            f'<td style="padding:6px;">{escape(row["reason"])}</td>'
+            f'<td style="padding:6px;white-space:nowrap;">{badge}</td>'
+            f'<td style="padding:6px;text-align:right;">{row["reports"]}</td>'
+            "</tr>"
+        )
+    for row in resolved:
+        resource = "/".join(part for part in (row.get("namespace"), row["resource"]) if part)
+        html_rows.append(
+            '<tr style="color:#1E7B3C;text-decoration:line-through;">'
+            f'<td style="padding:6px;">{escape(row.get("title") or row["reason"])}</td>'
+            f'<td style="padding:6px;">{escape(row.get("kind") or "")} <code>{escape(resource)}</code></td>'
+            f'<td style="padding:6px;">{escape(row["reason"])}</td>'
+            '<td style="padding:6px;white-space:nowrap;text-decoration:none;font-weight:600;">Resolved</td>'
+            f'<td style="padding:6px;text-align:right;">{row.get("occurrences") or 1}</td>'
+            "</tr>"
+        )
+
+    return f"""<div class="section watchdog-findings">
+  <h2>Findings Since Last Report</h2>
+  <p>{escape(summary)}</p>
+  <table style="border-collapse:collapse;width:100%;">
+    <thead><tr><th style="padding:6px;text-align:left;">Finding</th><th style="padding:6px;text-align:left;">Resource</th><th style="padding:6px;text-align:left;">Reason</th><th style="padding:6px;text-align:left;">Status</th><th style="padding:6px;text-align:right;">Reports</th></tr></thead>
+    <tbody>
+      {"".join(html_rows)}
+    </tbody>
+  </table>
+</div>"""
@@ -0,0 +1,56 @@
+from html import escape
+
+STATUS_BADGES = {
+    "resolved": ("✅ Resolved", "#E6F7EC", "#1E7B3C"),
+    "open": ("⏳ Still open", "#FFF4E0", "#A15C00"),
+}
+
+
+def build_followup_section(followups: list[dict], stats: dict) -> str:
+    """Render the recommendation follow-up section.
+
+    Args:
+        followups: Previously issued recommendations checked in this report
+            (output of ReportStorage.apply_recommendation_followup)
+        stats: Output of ReportStorage.get_recommendation_stats()
+
+    Returns:
+        HTML section, or an empty string when nothing was tracked yet
+    """
+    if not followups and not stats.get("issued"):
+        return ""
+
+    summary = (
+        f"{stats['resolved']} of {stats['issued']} recommendations issued so far have been acted on "
+        f"(closure rate {stats['closure_rate_pct']}%)."
+        if stats.get("issued") else "No recommendations tracked yet."
+    )
+
+    rows = []
+    for followup in followups:
+        label, background, color = STATUS_BADGES.get(followup["status"], STATUS_BADGES["open"])
+        target = "/".join(part for part in (followup.get("namespace"), followup.get("target")) if part)
+        rows.append(
+            "<tr>"
+            f'<td style="padding:6px;">{escape(followup["action"])}'
+            f'{f" <code>{escape(target)}</code>" if target else ""}</td>'
+            f'<td style="padding:6px;white-space:nowrap;">{escape(str(followup["first_seen"])[:10])}</td>'
+            f'<td style="padding:6px;"><span class="badge" style="background:{background};color:{color};">{label}</span></td>'
+            f'<td style="padding:6px;font-size:12px;color:#555;">{escape(followup.get("evidence") or "")}</td>'
+            "</tr>"
+        )
+
+    table = ""
+    if rows:
+        table = f"""
+  <table style="border-collapse:collapse;width:100%;">
+    <thead><tr><th style="padding:6px;text-align:left;">Recommendation</th><th style="padding:6px;text-align:left;">Issued</th><th style="padding:6px;text-align:left;">Status</th><th style="padding:6px;text-align:left;">Evidence</th></tr></thead>
+    <tbody>
+      {"".join(rows)}
+    </tbody>
+  </table>"""
+
+    return f"""<div class="section watchdog-followup">
+  <h2>Recommendation Follow-up</h2>
+  <p>{escape(summary)}</p>{table}
+</div>"""
@@ -0,0 +1,101 @@
+import re
+from datetime import date, timedelta
+from html import escape
+
+# Kubernetes generates name suffixes from an alphabet without vowels
+SUFFIX_CHARS = "[bcdfghjklmnpqrstvwxz2456789]"
+
+# Pod name suffixes added by Deployments (<rs-hash>-<pod-hash>) and
+# StatefulSets/DaemonSets/Jobs (<ordinal> or <hash>)
+POD_SUFFIX_PATTERNS = [
+    re.compile(rf"-{SUFFIX_CHARS}{{6,10}}-{SUFFIX_CHARS}{{5}}$"),
+    re.compile(rf"-{SUFFIX_CHARS}{{5}}$"),
+    re.compile(r"-\d+$"),
+]
+
+# Upper bound (exclusive) of restarts per cell -> background color
+HEATMAP_COLORS = [
+    (1, "#F5F5F5"),
+    (3, "#FFE8B3"),
+    (10, "#FFB366"),
+    (30, "#FF6B5B"),
+]
+HEATMAP_MAX_COLOR = "#C00000"
+
+
+def workload_name(pod_name: str) -> str:
+    """Derive the owning workload name from a pod name.
+
+    Args:
+        pod_name: Pod name (e.g., checkout-7d9f8b6c4-x2x5k)
+
+    Returns:
+        Best-effort workload name (e.g., checkout)
+    """
+    for pattern in POD_SUFFIX_PATTERNS:
+        stripped = pattern.sub("", pod_name)
+        if stripped != pod_name:
+            return stripped
+    return pod_name
+
+
+def _cell_color(count: int) -> str:
+    """Return the background color for a restart count."""
+    for upper_bound, color in HEATMAP_COLORS:
+        if count < upper_bound:
+            return color
+    return HEATMAP_MAX_COLOR
+
+
+def build_restart_heatmap(rows: list[dict], days: int = 7, max_workloads: int = 15) -> str:
+    """Render restarts per day per workload as a color-coded HTML table.
+
+    Args:
+        rows: Dicts with namespace, pod, day (YYYY-MM-DD) and restarts
+        days: Number of days (columns) ending today
+        max_workloads: Maximum number of rows, worst offenders first
+
+    Returns:
+        HTML section, or an empty string when there were no restarts
+    """
+    if not rows:
+        return ""
+
+    day_columns = [
+        (date.today() - timedelta(days=offset)).isoformat()
+        for offset in range(days - 1, -1, -1)
+    ]
+
+    matrix: dict[tuple[str, str], dict[str, int]] = {}
+    for row in rows:
+        key = (row["namespace"], workload_name(row["pod"]))
+        matrix.setdefault(key, {})
+        matrix[key][row["day"]] = matrix[key].get(row["day"], 0) + row["restarts"]
+
+    ranked = sorted(matrix.items(), key=lambda item: sum(item[1].values()), reverse=True)
+
+    header_cells = "".join(
+        f'<th style="padding:6px;font-size:12px;">{escape(day[5:])}</th>' for day in day_columns
+    )
+    body_rows = []
+    for (namespace, workload), per_day in ranked[:max_workloads]:
+        cells = "".join(
+            f'<td style="padding:6px;text-align:center;background:{_cell_color(per_day.get(day, 0))};">'
+            f"{per_day.get(day, 0) or ''}</td>"
+            for day in day_columns
+        )
+        body_rows.append(
+            f'<tr><td style="padding:6px;"><code>{escape(namespace)}/{escape(workload)}</code></td>'
+            f'{cells}<td style="padding:6px;text-align:center;font-weight:600;">'
+            f"{sum(per_day.values())}</td></tr>"
+        )
+
+    return f"""<div class="section watchdog-heatmap">
+  <h2>Restart Heatmap (last {days} days)</h2>
+  <table style="border-collapse:collapse;width:100%;">
+    <thead><tr><th style="padding:6px;text-align:left;">Workload</th>{header_cells}<th style="padding:6px;">Total</th></tr></thead>
+    <tbody>
+      {"".join(body_rows)}
+    </tbody>
+  </table>
+</div>"""
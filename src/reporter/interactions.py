@@ -0,0 +1,73 @@
+import hashlib
+import hmac
+import json
+import time
+from typing import Optional
+from urllib.parse import parse_qs
+
+# Slack rejects replays older than five minutes; do the same
+MAX_REQUEST_AGE_SECONDS = 300
+
+REVIEW_ACTIONS = {"approve_report", "discard_report"}
+
+
+def verify_slack_signature(
+    signing_secret: str,
+    timestamp: str,
+    body: bytes,
+    signature: str,
+    now: Optional[float] = None,
+) -> bool:
+    """Verify the X-Slack-Signature header of an interactivity request.
+
+    Args:
+        signing_secret: App signing secret
+        timestamp: X-Slack-Request-Timestamp header
+        body: Raw request body
+        signature: X-Slack-Signature header (v0=...)
+        now: Current time (defaults to time.time())
+
+    Returns:
+        True if the request was signed by Slack and is recent
+    """
+    try:
+        request_time = int(timestamp)
+    except (TypeError, ValueError):
+        return False
+
+    if abs((now or time.time()) - request_time) > MAX_REQUEST_AGE_SECONDS:
+        return False
+
+    base = f"v0:{timestamp}:".encode("utf-8") + body
+    expected = "v0=" + hmac.new(
+        signing_secret.encode("utf-8"), base, hashlib.sha256
+    ).hexdigest()
+
+    return hmac.compare_digest(expected, signature or "")
+
+
+def parse_review_action(body: bytes) -> Optional[dict]:
+    """Extract a review button click from an interactivity payload.
+
+    Args:
+        body: Raw form-encoded request body
+
+    Returns:
+        Dict with action, report_id, user_id and response_url, or None if the
+        payload is not a review button click
+    """
+    form = parse_qs(body.decode("utf-8"))
+    try:
+        payload = json.loads(form["payload"][0])
+        action = payload["actions"][0]
+        if action["action_id"] not in REVIEW_ACTIONS:
+            return None
+
+        return {
+            "action": action["action_id"],
+            "report_id": int(action["value"]),
+            "user_id": payload.get("user", {}).get("id"),
+            "response_url": payload.get("response_url"),
+        }
+    except (KeyError, IndexError, ValueError, json.JSONDecodeError):
+        return None
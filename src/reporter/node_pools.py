@@ -0,0 +1,49 @@
+from html import escape
+from typing import Optional
+
+
+def _pct(value: Optional[float]) -> str:
+    """Format a percentage, or a dash when unknown."""
+    return "-" if value is None else f"{value}%"
+
+
+def build_node_pools_section(node_pools: list[dict]) -> str:
+    """Render per node pool capacity, utilization and instability.
+
+    Args:
+        node_pools: Output of _node_pools() in collect_cluster_stats()
+
+    Returns:
+        HTML section, or an empty string when nodes carry no pool labels
+    """
+    if not node_pools:
+        return ""
+
+    rows = []
+    for pool in node_pools:
+        unstable_style = "color:#C00000;font-weight:600;" if pool["unstable_nodes"] else ""
+        rows.append(
+            "<tr>"
+            f'<td style="padding:6px;"><code>{escape(pool["pool"])}</code></td>'
+            f'<td style="padding:6px;text-align:right;">{pool["ready_nodes"]}/{pool["nodes"]}</td>'
+            f'<td style="padding:6px;text-align:right;">{pool["cpu_allocatable"]} cores / '
+            f'{pool["memory_allocatable_gib"]} GiB</td>'
+            f'<td style="padding:6px;text-align:right;">{_pct(pool["cpu_requested_pct"])} / '
+            f'{_pct(pool["memory_requested_pct"])}</td>'
+            f'<td style="padding:6px;text-align:right;">{_pct(pool["cpu_used_pct"])} / '
+            f'{_pct(pool["memory_used_pct"])}</td>'
+            f'<td style="padding:6px;text-align:right;">{pool["restarts"]}</td>'
+            f'<td style="padding:6px;text-align:right;{unstable_style}">{pool["unstable_nodes"]}</td>'
+            "</tr>"
+        )
+
+    return f"""<div class="section watchdog-node-pools">
+  <h2>Node Pools</h2>
+  <p>Capacity and stability per node pool / node group (CPU / memory).</p>
+  <table style="border-collapse:collapse;width:100%;">
+    <thead><tr><th style="padding:6px;text-align:left;">Pool</th><th style="padding:6px;text-align:right;">Ready</th><th style="padding:6px;text-align:right;">Allocatable</th><th style="padding:6px;text-align:right;">Requested</th><th style="padding:6px;text-align:right;">In use</th><th style="padding:6px;text-align:right;">Restarts</th><th style="padding:6px;text-align:right;">Unstable nodes</th></tr></thead>
+    <tbody>
+      {"".join(rows)}
+    </tbody>
+  </table>
+</div>"""
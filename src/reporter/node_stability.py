@@ -0,0 +1,56 @@
+from html import escape
+
+
+def _score_style(score: int) -> str:
+    """Color a stability score green, amber or red."""
+    if score >= 90:
+        return "color:#1E7B3C;"
+    if score >= 60:
+        return "color:#A15C00;"
+    return "color:#C00000;"
+
+
+def build_node_stability_section(node_stability: list[dict], max_nodes: int = 15) -> str:
+    """Render the per-node stability table (reboots, kernel panics, kubelet restarts).
+
+    Args:
+        node_stability: Output of collect_node_stability(), least stable first
+        max_nodes: Maximum number of rows
+
+    Returns:
+        HTML section, or an empty string when there are no nodes
+    """
+    if not node_stability:
+        return ""
+
+    unstable = [n for n in node_stability if n["stability_score"] < 100]
+    if unstable:
+        summary = f"{len(unstable)} of {len(node_stability)} nodes had lifecycle incidents this week."
+    else:
+        summary = f"No reboots, kernel panics or kubelet restarts on any of the {len(node_stability)} nodes this week."
+
+    rows = []
+    for node in node_stability[:max_nodes]:
+        rows.append(
+            "<tr>"
+            f'<td style="padding:6px;"><code>{escape(node["node"])}</code></td>'
+            f'<td style="padding:6px;text-align:right;font-weight:600;{_score_style(node["stability_score"])}">'
+            f'{node["stability_score"]}</td>'
+            f'<td style="padding:6px;text-align:right;">{node["reboots"]}</td>'
+            f'<td style="padding:6px;text-align:right;">{node["kernel_panics"]}</td>'
+            f'<td style="padding:6px;text-align:right;">{node["kubelet_restarts"]}</td>'
+            f'<td style="padding:6px;text-align:right;">{node["not_ready_transitions"]}</td>'
+            f'<td style="padding:6px;font-size:12px;color:#555;">{escape(", ".join(node["active_problems"]))}</td>'
+            "</tr>"
+        )
+
+    return f"""<div class="section watchdog-node-stability">
+  <h2>Node Stability</h2>
+  <p>{escape(summary)}</p>
+  <table style="border-collapse:collapse;width:100%;">
+    <thead><tr><th style="padding:6px;text-align:left;">Node</th><th style="padding:6px;text-align:right;">Score</th><th style="padding:6px;text-align:right;">Reboots</th><th style="padding:6px;text-align:right;">Kernel panics</th><th style="padding:6px;text-align:right;">Kubelet restarts</th><th style="padding:6px;text-align:right;">NotReady</th><th style="padding:6px;text-align:left;">Active problems</th></tr></thead>
+    <tbody>
+      {"".join(rows)}
+    </tbody>
+  </table>
+</div>"""
@@ -0,0 +1,88 @@
+import re
+
+import structlog
+
+logger = structlog.get_logger()
+
+REDACTED = "[redacted]"
+
+# RFC 1918 and carrier-grade NAT ranges, with an optional CIDR suffix
+PRIVATE_IP_PATTERN = (
+    r"\b(?:10(?:\.\d{1,3}){3}"
+    r"|172\.(?:1[6-9]|2\d|3[01])(?:\.\d{1,3}){2}"
+    r"|192\.168(?:\.\d{1,3}){2}"
+    r"|100\.(?:6[4-9]|[7-9]\d|1[01]\d|12[0-7])(?:\.\d{1,3}){2})(?:/\d{1,2})?\b"
+)
+
+HTML_TAG_PATTERN = re.compile(r"(<[^>]*>)")
+ATTRIBUTE_VALUE_PATTERN = re.compile(r"""(=\s*)(["'])(.*?)\2""", re.DOTALL)
+
+
+def compile_rules(patterns: list[str], private_ips: bool = False) -> list[re.Pattern]:
+    """Compile the configured redaction regexes, skipping invalid ones.
+
+    Args:
+        patterns: Regexes of internal domains, customer names...
+        private_ips: Also redact private IPv4 addresses and ranges
+
+    Returns:
+        Compiled patterns (case-insensitive)
+    """
+    rules = []
+    for pattern in patterns + ([PRIVATE_IP_PATTERN] if private_ips else []):
+        try:
+            rules.append(re.compile(pattern, re.IGNORECASE))
+        except re.error as e:
+            logger.warning("redaction_pattern_invalid", pattern=pattern, error=str(e))
+    return rules
+
+
+def redact_text(text: str, rules: list[re.Pattern]) -> tuple[str, int]:
+    """Replace every match of the rules in plain text.
+
+    Args:
+        text: Text to redact
+        rules: Output of compile_rules()
+
+    Returns:
+        Tuple of (redacted text, number of replacements)
+    """
+    total = 0
+    for rule in rules:
+        text, count = rule.subn(REDACTED, text)
+        total += count
+    return text, total
+
+
+def redact_html(report_html: str, rules: list[re.Pattern]) -> tuple[str, int]:
+    """Redact the text and attribute values of an HTML document.
+
+    Tag and attribute names are never touched, so a broad pattern cannot
+    break the markup.
+
+    Args:
+        report_html: Final report HTML
+        rules: Output of compile_rules()
+
+    Returns:
+        Tuple of (redacted HTML, number of replacements)
+    """
+    if not rules:
+        return report_html, 0
+
+    total = 0
+
+    def redact_attribute(match: re.Match) -> str:
+        nonlocal total
+        value, count = redact_text(match.group(3), rules)
+        total += count
+        return f"{match.group(1)}{match.group(2)}{value}{match.group(2)}"
+
+    parts = HTML_TAG_PATTERN.split(report_html)
+    for index, part in enumerate(parts):
+        if index % 2:
+            parts[index] = ATTRIBUTE_VALUE_PATTERN.sub(redact_attribute, part)
+        else:
+            parts[index], count = redact_text(part, rules)
+            total += count
+    return "".join(parts), total
@@ -0,0 +1,223 @@
+from datetime import datetime
+from html import escape
+from typing import Optional
+
+from src.config import settings
+
+# Lookback of each rollup period, in days
+ROLLUP_PERIODS = {"month": 31, "quarter": 92}
+
+HEALTH_COLORS = {"green": "#1E7B3C", "yellow": "#A15C00", "red": "#C00000"}
+
+
+def _delta(first: Optional[float], last: Optional[float]) -> Optional[float]:
+    """Return last - first, or None when either value is missing."""
+    if first is None or last is None:
+        return None
+    return round(last - first, 1)
+
+
+def summarize_rollup(reports: list[dict], findings: list[dict]) -> dict:
+    """Aggregate stored weekly reports and findings into period trends.
+
+    Args:
+        reports: Output of get_recent_report_metadata(since=...), newest first
+        findings: Output of get_findings_seen_since()
+
+    Returns:
+        Dict with the weekly trajectory, capacity growth and recurring findings
+    """
+    weeks = []
+    for report in reversed(reports):
+        stats = report["metadata"].get("cluster_stats") or {}
+        weeks.append({
+            "report_id": report["id"],
+            "date": report["generated_at"][:10],
+            "health_status": (report["metadata"].get("report_data") or {}).get("health_status"),
+            "running_pct": stats.get("running_pct"),
+            "restarts": stats.get("restarts_this_week"),
+            "total_pods": stats.get("total_pods"),
+            "total_nodes": stats.get("total_nodes"),
+            "cpu_requested_pct": stats.get("cpu_requested_pct"),
+            "memory_requested_pct": stats.get("memory_requested_pct"),
+        })
+
+    first, last = (weeks[0], weeks[-1]) if weeks else ({}, {})
+    growth = {
+        key: {"start": first.get(key), "end": last.get(key), "change": _delta(first.get(key), last.get(key))}
+        for key in ("total_nodes", "total_pods", "cpu_requested_pct", "memory_requested_pct")
+    }
+
+    return {
+        "weeks": weeks,
+        "health_counts": {
+            status: sum(1 for week in weeks if week["health_status"] == status)
+            for status in ("green", "yellow", "red")
+        },
+        "growth": growth,
+        # Seen in more than one weekly report during the period
+        "recurring_findings": [f for f in findings if f["occurrences"] > 1],
+        "resolved_findings": sum(1 for f in findings if f["status"] == "closed"),
+        "open_findings": sum(1 for f in findings if f["status"] == "open"),
+    }
+
+
+def format_rollup_for_prompt(summary: dict) -> str:
+    """Render a rollup summary as plain text for the narrative prompt.
+
+    Names of workloads are left out in privacy mode.
+
+    Args:
+        summary: Output of summarize_rollup()
+
+    Returns:
+        Prompt text
+    """
+    lines = ["Weekly reports (oldest first):"]
+    for week in summary["weeks"]:
+        lines.append(
+            f"- {week['date']}: health={week['health_status']}, pods running={week['running_pct']}%, "
+            f"restarts={week['restarts']}, nodes={week['total_nodes']}, pods={week['total_pods']}, "
+            f"cpu requested={week['cpu_requested_pct']}%, memory requested={week['memory_requested_pct']}%"
+        )
+
+    lines.append("Capacity change over the period:")
+    for key, values in summary["growth"].items():
+        lines.append(f"- {key}: {values['start']} -> {values['end']} (change {values['change']})")
+
+    lines.append(
+        f"Findings: {summary['open_findings']} still open, {summary['resolved_findings']} resolved, "
+        f"{len(summary['recurring_findings'])} recurring"
+    )
+    for finding in summary["recurring_findings"][:15]:
+        if settings.privacy_mode:
+            lines.append(f"- {finding['kind']} {finding['reason']}: {finding['occurrences']} reports ({finding['status']})")
+        else:
+            lines.append(
+                f"- {finding['title']} ({finding['kind']} {finding['namespace']}/{finding['resource']}, "
+                f"{finding['reason']}): {finding['occurrences']} reports, since {finding['first_seen'][:10]} "
+                f"({finding['status']})"
+            )
+
+    return "\n".join(lines)
+
+
+def _value(value, suffix: str = "") -> str:
+    """Format a figure, or a dash when it was not recorded."""
+    return "-" if value is None else f"{value}{suffix}"
+
+
+def _growth_cell(values: dict, suffix: str = "") -> str:
+    """Render start -> end with a signed change."""
+    if values["start"] is None and values["end"] is None:
+        return "-"
+    change = values["change"]
+    sign = "+" if change and change > 0 else ""
+    return (
+        f"{values['start']}{suffix} &rarr; {values['end']}{suffix}"
+        + (f" ({sign}{change}{suffix})" if change is not None else "")
+    )
+
+
+def build_rollup_report(
+    summary: dict,
+    period: str,
+    period_start: datetime,
+    period_end: datetime,
+    narrative: str,
+) -> str:
+    """Render the rollup report as a standalone HTML document.
+
+    Args:
+        summary: Output of summarize_rollup()
+        period: "month" or "quarter"
+        period_start: Start of the period
+        period_end: End of the period
+        narrative: Short LLM-written summary (plain text paragraphs)
+
+    Returns:
+        HTML document
+    """
+    title = f"{'Monthly' if period == 'month' else 'Quarterly'} Cluster Health Rollup"
+    paragraphs = "".join(
+        f"<p>{escape(paragraph.strip())}</p>" for paragraph in narrative.split("\n\n") if paragraph.strip()
+    )
+
+    week_rows = "".join(
+        "<tr>"
+        f'<td style="padding:6px;">{escape(week["date"])}</td>'
+        f'<td style="padding:6px;font-weight:600;color:{HEALTH_COLORS.get(week["health_status"], "#555")};">'
+        f'{escape(str(week["health_status"] or "-"))}</td>'
+        f'<td style="padding:6px;text-align:right;">{_value(week["running_pct"], "%")}</td>'
+        f'<td style="padding:6px;text-align:right;">{_value(week["restarts"])}</td>'
+        f'<td style="padding:6px;text-align:right;">{_value(week["total_nodes"])}</td>'
+        f'<td style="padding:6px;text-align:right;">{_value(week["total_pods"])}</td>'
+        "</tr>"
+        for week in summary["weeks"]
+    )
+
+    growth = summary["growth"]
+    growth_rows = "".join(
+        f'<tr><td style="padding:6px;">{label}</td><td style="padding:6px;text-align:right;">{cell}</td></tr>'
+        for label, cell in (
+            ("Nodes", _growth_cell(growth["total_nodes"])),
+            ("Pods", _growth_cell(growth["total_pods"])),
+            ("CPU requested", _growth_cell(growth["cpu_requested_pct"], "%")),
+            ("Memory requested", _growth_cell(growth["memory_requested_pct"], "%")),
+        )
+    )
+
+    finding_rows = "".join(
+        "<tr>"
+        f'<td style="padding:6px;">{escape(finding["title"] or finding["reason"] or "")}</td>'
+        f'<td style="padding:6px;"><code>{escape(finding["namespace"] or "")}/{escape(finding["resource"] or "")}</code></td>'
+        f'<td style="padding:6px;text-align:right;">{finding["occurrences"]}</td>'
+        f'<td style="padding:6px;">{escape(finding["first_seen"][:10])}</td>'
+        f'<td style="padding:6px;">{escape(finding["status"])}</td>'
+        "</tr>"
+        for finding in summary["recurring_findings"][:25]
+    ) or '<tr><td colspan="5" style="padding:6px;">No finding was reported in more than one week.</td></tr>'
+
+    return f"""<!DOCTYPE html>
+<html>
+<head>
+<meta charset="UTF-8">
+<title>{escape(title)}</title>
+<style>
+  body {{ font-family: sans-serif; color: #222; margin: 32px; }}
+  h1 {{ color: #6C62FF; }}
+  table {{ border-collapse: collapse; width: 100%; margin-bottom: 24px; }}
+  th {{ text-align: left; padding: 6px; border-bottom: 1px solid #E0E0E0; }}
+</style>
+</head>
+<body>
+<h1>{escape(title)}</h1>
+<p>Cluster <strong>{escape(settings.cluster_name)}</strong>, {period_start:%Y-%m-%d} &ndash; {period_end:%Y-%m-%d}
+({len(summary["weeks"])} weekly reports: {summary["health_counts"]["green"]} green,
+{summary["health_counts"]["yellow"]} yellow, {summary["health_counts"]["red"]} red)</p>
+<div class="section">
+  <h2>Summary</h2>
+  {paragraphs}
+</div>
+<div class="section">
+  <h2>Health Trajectory</h2>
+  <table>
+    <thead><tr><th>Week</th><th>Health</th><th style="text-align:right;">Pods running</th><th style="text-align:right;">Restarts (7d)</th><th style="text-align:right;">Nodes</th><th style="text-align:right;">Pods</th></tr></thead>
+    <tbody>{week_rows}</tbody>
+  </table>
+</div>
+<div class="section">
+  <h2>Capacity Growth</h2>
+  <table><tbody>{growth_rows}</tbody></table>
+</div>
+<div class="section">
+  <h2>Recurring Findings</h2>
+  <p>{summary["open_findings"]} findings still open, {summary["resolved_findings"]} resolved during the period.</p>
+  <table>
+    <thead><tr><th>Finding</th><th>Resource</th><th style="text-align:right;">Weeks</th><th>First seen</th><th>Status</th></tr></thead>
+    <tbody>{finding_rows}</tbody>
+  </table>
+</div>
+<footer><p style="font-size:12px;color:#555;">Generated by Watchdog AI - Helmcode</p></footer>
+</body>
+</html>"""
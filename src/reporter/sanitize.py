@@ -0,0 +1,168 @@
+import re
+from html import escape
+from html.parser import HTMLParser
+
+import structlog
+
+logger = structlog.get_logger()
+
+ALLOWED_TAGS = {
+    "html", "head", "body", "title", "meta", "style",
+    "div", "span", "p", "br", "hr", "section", "header", "footer", "article", "main",
+    "h1", "h2", "h3", "h4", "h5", "h6",
+    "strong", "b", "em", "i", "u", "small", "sub", "sup", "mark", "abbr", "time",
+    "code", "pre", "blockquote",
+    "ul", "ol", "li", "dl", "dt", "dd",
+    "table", "caption", "colgroup", "col", "thead", "tbody", "tfoot", "tr", "th", "td",
+    "a", "img", "figure", "figcaption", "details", "summary",
+}
+
+# Dropped together with everything inside them
+DROPPED_WITH_CONTENT = {
+    "script", "iframe", "object", "embed", "applet", "noscript", "template",
+    "svg", "math", "frameset", "frame", "audio", "video",
+}
+
+ALLOWED_ATTRIBUTES = {
+    "class", "id", "style", "title", "lang", "dir",
+    "colspan", "rowspan", "scope", "align", "valign", "width", "height",
+    "href", "src", "alt", "datetime", "charset", "name", "content",
+}
+
+VOID_TAGS = {"br", "hr", "img", "meta", "col"}
+
+CSS_IMPORT_PATTERN = re.compile(r"@import[^;]*;?", re.IGNORECASE)
+CSS_URL_PATTERN = re.compile(r"url\(\s*(['\"]?)(.*?)\1\s*\)", re.IGNORECASE)
+CSS_EXPRESSION_PATTERN = re.compile(r"expression\s*\(", re.IGNORECASE)
+
+
+def _is_inline_url(url: str) -> bool:
+    """Return True for URLs that need no network access (data: URIs and fragments)."""
+    url = url.strip().lower()
+    return url.startswith("data:image/") or url.startswith("#")
+
+
+def _sanitize_css(css: str) -> str:
+    """Strip @import rules, remote url() references and CSS expressions."""
+    css = CSS_IMPORT_PATTERN.sub("", css)
+    css = CSS_URL_PATTERN.sub(lambda m: m.group(0) if _is_inline_url(m.group(2)) else "none", css)
+    return CSS_EXPRESSION_PATTERN.sub("(", css)
+
+
+def _safe_href(url: str) -> bool:
+    """Allow plain web links, mail links and fragments; reject javascript: and friends."""
+    scheme = url.strip().lower().split(":", 1)[0] if ":" in url else ""
+    return scheme in ("", "http", "https", "mailto")
+
+
+class _Sanitizer(HTMLParser):
+    """Rebuild an HTML document keeping only allowlisted tags and attributes."""
+
+    def __init__(self) -> None:
+        super().__init__(convert_charrefs=False)
+        self.output: list[str] = []
+        self.skip_depth = 0
+        self.skipped_tag = ""
+        self.in_style = False
+        self.removed: set[str] = set()
+
+    def _attributes(self, tag: str, attrs: list[tuple[str, str]]) -> str:
+        kept = []
+        for name, value in attrs:
+            value = value or ""
+            if name not in ALLOWED_ATTRIBUTES:
+                self.removed.add(f"@{name}")
+                continue
+            if name == "style":
+                value = _sanitize_css(value)
+            elif name == "src" and not _is_inline_url(value):
+                self.removed.add("@src")
+                continue
+            elif name == "href" and not _safe_href(value):
+                self.removed.add("@href")
+                continue
+            elif name == "content" and tag != "meta":
+                continue
+            kept.append(f' {name}="{escape(value, quote=True)}"')
+        return "".join(kept)
+
+    def handle_starttag(self, tag: str, attrs: list) -> None:
+        if self.skip_depth:
+            if tag == self.skipped_tag:
+                self.skip_depth += 1
+            return
+        if tag in DROPPED_WITH_CONTENT:
+            self.removed.add(tag)
+            self.skipped_tag = tag
+            self.skip_depth = 1
+            return
+        if tag not in ALLOWED_TAGS:
+            self.removed.add(tag)
+            return
+        if tag == "img" and not any(n == "src" and _is_inline_url(v or "") for n, v in attrs):
+            # Remote images would be fetched by the PDF renderer
+            self.removed.add("img")
+            return
+        if tag == "style":
+            self.in_style = True
+        self.output.append(f"<{tag}{self._attributes(tag, attrs)}>")
+
+    def handle_startendtag(self, tag: str, attrs: list) -> None:
+        self.handle_starttag(tag, attrs)
+        if tag not in VOID_TAGS:
+            self.handle_endtag(tag)
+
+    def handle_endtag(self, tag: str) -> None:
+        if self.skip_depth:
+            if tag == self.skipped_tag:
+                self.skip_depth -= 1
+            return
+        if tag not in ALLOWED_TAGS or tag in VOID_TAGS:
+            return
+        if tag == "style":
+            self.in_style = False
+        self.output.append(f"</{tag}>")
+
+    def handle_data(self, data: str) -> None:
+        if self.skip_depth:
+            return
+        if self.in_style:
+            self.output.append(_sanitize_css(data))
+        else:
+            self.output.append(data.replace("<", "&lt;").replace(">", "&gt;"))
+
+    def handle_entityref(self, name: str) -> None:
+        if not self.skip_depth:
+            self.output.append(f"&{name};")
+
+    def handle_charref(self, name: str) -> None:
+        if not self.skip_depth:
+            self.output.append(f"&#{name};")
+
+    def handle_decl(self, decl: str) -> None:
+        if decl.lower().startswith("doctype"):
+            self.output.append(f"<!{decl}>")
+
+
+def sanitize_report_html(report_html: str) -> str:
+    """Remove scripts, event handlers and remote resources from model-generated HTML.
+
+    Only allowlisted tags and attributes are kept; <script>, <iframe>, <svg>
+    and similar elements are dropped with their content, and remote images,
+    stylesheets and CSS url() references are removed so the PDF renderer
+    never reaches the network. Comments are dropped.
+
+    Args:
+        report_html: HTML document returned by the model
+
+    Returns:
+        Sanitized HTML document
+    """
+    sanitizer = _Sanitizer()
+    sanitizer.feed(report_html)
+    sanitizer.close()
+
+    if sanitizer.removed:
+        logger.info("report_html_sanitized", removed=sorted(sanitizer.removed))
+
+    return "".join(sanitizer.output)
@@ -0,0 +1,240 @@
+import re
+from html import escape
+
+FOOTER_PATTERN = re.compile(r'<div[^>]*class="[^"]*footer[^"]*"', re.IGNORECASE)
+HEADER_PATTERN = re.compile(r'<div[^>]*class="[^"]*header[^"]*"[^>]*>', re.IGNORECASE)
+DIV_TAG_PATTERN = re.compile(r"<(/?)div\b[^>]*>", re.IGNORECASE)
+BODY_PATTERN = re.compile(r"<body[^>]*>", re.IGNORECASE)
+HTML_TAG_PATTERN = re.compile(r"<html\b([^>]*)>", re.IGNORECASE)
+TITLE_PATTERN = re.compile(r"<title[^>]*>.*?</title>", re.IGNORECASE | re.DOTALL)
+# Meta names WeasyPrint copies into the PDF document information
+PDF_META_PATTERN = re.compile(
+    r'<meta[^>]*name=["\'](?:author|description|keywords|generator|dcterms\.created)["\'][^>]*>\s*',
+    re.IGNORECASE,
+)
+
+
+def insert_section(report_html: str, section_html: str) -> str:
+    """Insert a generated section into the report, just above the footer.
+
+    Falls back to the end of <body> when the report has no footer div.
+
+    Args:
+        report_html: Full HTML report
+        section_html: Section markup to insert
+
+    Returns:
+        HTML report with the section inserted
+    """
+    match = FOOTER_PATTERN.search(report_html)
+    if match:
+        return report_html[:match.start()] + section_html + "\n" + report_html[match.start():]
+
+    body_end = report_html.lower().rfind("</body>")
+    if body_end != -1:
+        return report_html[:body_end] + section_html + "\n" + report_html[body_end:]
+
+    return report_html + section_html
+
+
+def insert_after_header(report_html: str, section_html: str) -> str:
+    """Insert a generated section right below the report header.
+
+    Falls back to the start of <body> when no header div is found.
+
+    Args:
+        report_html: Full HTML report
+        section_html: Section markup to insert
+
+    Returns:
+        HTML report with the section inserted
+    """
+    header = HEADER_PATTERN.search(report_html)
+    if header:
+        depth = 1
+        for tag in DIV_TAG_PATTERN.finditer(report_html, header.end()):
+            depth += -1 if tag.group(1) else 1
+            if depth == 0:
+                return report_html[:tag.end()] + "\n" + section_html + report_html[tag.end():]
+
+    body = BODY_PATTERN.search(report_html)
+    if body:
+        return report_html[:body.end()] + "\n" + section_html + report_html[body.end():]
+
+    return section_html + report_html
+
+
+def set_document_metadata(
+    report_html: str,
+    title: str,
+    author: str,
+    description: str,
+    keywords: list[str],
+    created: str,
+    lang: str,
+) -> str:
+    """Set the document metadata WeasyPrint embeds in the PDF (title, author...).
+
+    Also sets the document language, which screen readers and tagged
+    PDFs need. Existing title and metadata tags are replaced.
+
+    Args:
+        report_html: Full HTML report
+        title: Document title
+        author: Document author
+        description: Document subject (cluster and period)
+        keywords: Search keywords
+        created: Creation date (ISO 8601)
+        lang: Language code (e.g., 'en', 'es')
+
+    Returns:
+        HTML report with the metadata set
+    """
+    report_html = TITLE_PATTERN.sub("", report_html)
+    report_html = PDF_META_PATTERN.sub("", report_html)
+
+    tags = (
+        f"<title>{escape(title)}</title>\n"
+        f'<meta name="author" content="{escape(author)}">\n'
+        f'<meta name="description" content="{escape(description)}">\n'
+        f'<meta name="keywords" content="{escape(", ".join(keywords))}">\n'
+        '<meta name="generator" content="K8s Watchdog AI">\n'
+        f'<meta name="dcterms.created" content="{escape(created)}">\n'
+    )
+    head_end = report_html.lower().find("</head>")
+    if head_end != -1:
+        report_html = report_html[:head_end] + tags + report_html[head_end:]
+
+    html_tag = HTML_TAG_PATTERN.search(report_html)
+    if html_tag and "lang=" not in html_tag.group(1).lower():
+        report_html = (
+            report_html[:html_tag.start()] + f'<html lang="{lang}"{html_tag.group(1)}>'
+            + report_html[html_tag.end():]
+        )
+
+    return report_html
+
+
+def build_upgrade_readiness_section(api_warnings: list[str]) -> str:
+    """Render the API server warnings seen during collection.
+
+    Args:
+        api_warnings: Warning header texts (deprecations and request problems)
+
+    Returns:
+        HTML section, or an empty string when there were no warnings
+    """
+    if not api_warnings:
+        return ""
+
+    items = "".join(f"<li><code>{escape(warning)}</code></li>" for warning in api_warnings)
+
+    return f"""<div class="section watchdog-upgrade-readiness">
+  <h2>Upgrade Readiness</h2>
+  <p>The API server returned these warnings while the cluster data was collected:</p>
+  <ul>{items}</ul>
+</div>"""
+
+
+def build_control_plane_section(health: dict) -> str:
+    """Render a short control-plane health note (API server checks, etcd, latency).
+
+    Args:
+        health: Output of collect_control_plane_health()
+
+    Returns:
+        HTML section, or an empty string when nothing was observable
+    """
+    items = []
+    for endpoint in ("readyz", "livez"):
+        checks = health.get(endpoint)
+        if checks is None:
+            continue
+        if checks["ok"]:
+            items.append(f"API server /{endpoint}: all checks passing")
+        else:
+            items.append(f"API server /{endpoint}: failing checks {', '.join(checks['failed'])}")
+    for component in health.get("unhealthy_components") or []:
+        items.append(f"{component['component']} unhealthy: {component['message']}")
+    if health.get("apiserver_p99_latency_seconds") is not None:
+        items.append(f"API server p99 request latency (1h): {health['apiserver_p99_latency_seconds']}s")
+    if health.get("apiserver_error_rate_pct") is not None:
+        items.append(f"API server 5xx rate (1h): {health['apiserver_error_rate_pct']}%")
+    if health.get("etcd_p99_request_seconds") is not None:
+        items.append(f"etcd p99 request latency (1h): {health['etcd_p99_request_seconds']}s")
+
+    if not items:
+        return ""
+
+    rows = "".join(f"<li>{escape(item)}</li>" for item in items)
+
+    return f"""<div class="section watchdog-control-plane">
+  <h2>Control Plane Health</h2>
+  <ul>{rows}</ul>
+</div>"""
+
+
+def build_exclusions_section(exclusions: dict) -> str:
+    """Render the appendix listing namespaces and workloads that opted out of the analysis.
+
+    Args:
+        exclusions: Output of collect_exclusions()
+
+    Returns:
+        HTML section, or an empty string when nothing opted out
+    """
+    items = [f"Namespace <code>{escape(ns)}</code>" for ns in exclusions.get("namespaces") or []] + [
+        f"{escape(w['kind'])} <code>{escape(w['namespace'])}/{escape(w['name'])}</code>"
+        for w in exclusions.get("workloads") or []
+    ]
+    if not items:
+        return ""
+
+    rows = "".join(f"<li>{item}</li>" for item in items)
+
+    return f"""<div class="section watchdog-exclusions">
+  <h2>Appendix: Excluded From Analysis</h2>
+  <p>These resources carry the <code>watchdog.helmcode.com/ignore: "true"</code> annotation and were not analyzed:</p>
+  <ul>{rows}</ul>
+</div>"""
+
+
+def build_stats_header(stats: dict) -> str:
+    """Render the deterministic cluster statistics as a compact header strip.
+
+    Args:
+        stats: Output of collect_cluster_stats()
+
+    Returns:
+        HTML section
+    """
+    items = [
+        ("Pods running", f"{stats['running_pods']}/{stats['total_pods']}"),
+        ("Nodes ready", f"{stats['ready_nodes']}/{stats['total_nodes']}"),
+        (
+            "Restarts (7d)" if stats.get("restarts_this_week") is not None else "Restarts",
+            stats["restarts_this_week"] if stats.get("restarts_this_week") is not None
+            else stats["total_restarts"],
+        ),
+    ]
+    if stats.get("cpu_requested_pct") is not None:
+        items.append(("CPU requested", f"{stats['cpu_requested_pct']}%"))
+    if stats.get("memory_requested_pct") is not None:
+        items.append(("Memory requested", f"{stats['memory_requested_pct']}%"))
+    if stats.get("cpu_used_pct") is not None:
+        items.append(("CPU in use", f"{stats['cpu_used_pct']}%"))
+    if stats.get("memory_used_pct") is not None:
+        items.append(("Memory in use", f"{stats['memory_used_pct']}%"))
+
+    cells = "".join(
+        '<td style="padding:10px 16px;text-align:center;">'
+        f'<div style="font-size:20px;font-weight:700;color:#6C62FF;">{escape(str(value))}</div>'
+        f'<div style="font-size:12px;color:#555;">{escape(label)}</div></td>'
+        for label, value in items
+    )
+
+    return (
+        '<div class="watchdog-stats" style="background:#FFFFFF;border-bottom:1px solid #E0E0E0;">'
+        f'<table style="margin:0 auto;border-collapse:collapse;"><tr>{cells}</tr></table>'
+        "</div>"
+    )
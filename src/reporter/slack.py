@@ -1,13 +1,40 @@
+import asyncio
+import json
+import re
+
 import httpx
 import structlog
-from typing import Optional
+from contextlib import asynccontextmanager
+from datetime import datetime
+from typing import AsyncIterator, Awaitable, Callable, Optional
 from io import BytesIO
-from weasyprint import HTML
+from weasyprint import HTML, default_url_fetcher
 
 from src.config import settings
+from src.reporter.text import chunk_text, html_to_slack_text
+from src.tracing import span
 
 logger = structlog.get_logger()
 
+# Beyond this many chunks, the text report is shared as a snippet instead of messages
+MAX_TEXT_MESSAGES = 8
+
+MAX_DELIVERY_ERROR_LENGTH = 300
+
+# Embedded charts and images are the usual cause of rendering failures
+IMAGE_PATTERN = re.compile(r"<img\b[^>]*>|<svg\b.*?</svg>", re.IGNORECASE | re.DOTALL)
+
+
+class PDFRenderError(RuntimeError):
+    """Raised when neither the configured nor the minimal PDF rendering succeeds."""
+
+
+def _offline_url_fetcher(url: str) -> dict:
+    """Resolve inline data: URIs only, so rendering never waits on the network."""
+    if not url.startswith("data:"):
+        raise ValueError(f"remote resources are not fetched: {url}")
+    return default_url_fetcher(url)
+
 
 class SlackReporter:
     """Send reports to Slack via webhooks and bot API."""
@@ -17,11 +44,15 @@ class SlackReporter:
         self.webhook_url = settings.slack_webhook_url
         self.bot_token = settings.slack_bot_token
         self.channel = settings.slack_channel
+        self.dm_user_ids = settings.slack_dm_users
+        # Outcome of every report delivery made by this instance (destination, format, size...)
+        self.deliveries: list[dict] = []
 
         logger.info(
             "slack_reporter_initialized",
             has_webhook=bool(self.webhook_url),
             has_bot_token=bool(self.bot_token),
+            dm_users=len(self.dm_user_ids),
         )
 
     async def send_message(self, text: str) -> None:
@@ -40,11 +71,56 @@ class SlackReporter:
 
         logger.info("slack_message_sent", text_length=len(text))
 
+    @asynccontextmanager
+    async def _track_delivery(self, destination: str, fmt: str, size_bytes: int) -> AsyncIterator[dict]:
+        """Record the outcome of one delivery in self.deliveries; errors are re-raised.
+
+        Args:
+            destination: Where the report goes (channel:<id>, dm:<user>, review:<id>, webhook)
+            fmt: pdf, text, heartbeat or message
+            size_bytes: Size of what is sent
+
+        Yields:
+            The entry, so the format and size can be corrected once known
+        """
+        entry = {
+            "attempted_at": datetime.now().isoformat(),
+            "destination": destination,
+            "format": fmt,
+            "size_bytes": size_bytes,
+            "ok": True,
+            "error": None,
+        }
+        try:
+            yield entry
+        except Exception as e:
+            entry.update(ok=False, error=f"{type(e).__name__}: {e}"[:MAX_DELIVERY_ERROR_LENGTH])
+            raise
+        finally:
+            self.deliveries.append(entry)
+
+    async def send_status_message(self, text: str) -> None:
+        """Post a short status message where the report would have gone.
+
+        Uses the bot channel when configured, the webhook otherwise. DM
+        recipients are not notified.
+
+        Args:
+            text: Message text (mrkdwn)
+        """
+        if self.bot_token and self.channel:
+            async with self._track_delivery(f"channel:{self.channel}", "heartbeat", len(text.encode("utf-8"))):
+                await self.post_message(self.channel, text)
+        else:
+            async with self._track_delivery("webhook", "heartbeat", len(text.encode("utf-8"))):
+                await self.send_message(text)
+
     async def send_html_report(
         self,
         html_content: str,
         filename: str = "cluster-health-report.pdf",
         message: Optional[str] = None,
+        attachments: Optional[list[tuple[str, bytes]]] = None,
     ) -> None:
         """Send HTML report to Slack as PDF.
 
@@ -56,111 +132,472 @@ class SlackReporter:
             html_content: HTML content
             filename: Filename for the attachment (should end in .pdf)
             message: Optional message to accompany the report
+            attachments: Optional extra (filename, bytes) files shared with the PDF
         """
         if self.bot_token and self.channel:
-            # Convert HTML to PDF
-            logger.info("converting_html_to_pdf", html_size=len(html_content))
-            pdf_bytes = self._html_to_pdf(html_content)
-            logger.info("pdf_generated", pdf_size=len(pdf_bytes))
-
-            # Upload PDF file using Slack Bot API
-            await self._upload_file_bytes(pdf_bytes, filename, message, "application/pdf")
+            try:
+                files = self._build_files(html_content, filename, attachments)
+            except PDFRenderError:
+                files = None
+
+            fmt = "text" if files is None else "pdf"
+            size_bytes = len(html_content.encode("utf-8")) if files is None else sum(len(f[1]) for f in files)
+
+            async def deliver(channel: str) -> None:
+                if files is None:
+                    await self._send_text_report(html_content, filename, message, attachments, channel)
+                else:
+                    await self._upload_files(files, message, channel)
+
+            # Upload files using Slack Bot API
+            async with self._track_delivery(f"channel:{self.channel}", fmt, size_bytes):
+                await deliver(self.channel)
+
+            # Also deliver directly to selected users
+            for user_id in self.dm_user_ids:
+                try:
+                    async with self._track_delivery(f"dm:{user_id}", fmt, size_bytes):
+                        await deliver(await self._open_dm(user_id))
+                except (httpx.HTTPError, RuntimeError) as e:
+                    logger.error("slack_dm_delivery_failed", user_id=user_id, error=str(e))
         else:
             # Fallback: send message only
             summary_message = message or "📊 Weekly Cluster Health Report Generated"
-            await self.send_message(
+            text = (
                 f"{summary_message}\n\n"
                 "⚠️ Note: Configure SLACK_BOT_TOKEN and SLACK_CHANNEL to receive the full PDF report."
             )
+            async with self._track_delivery("webhook", "message", len(text.encode("utf-8"))):
+                await self.send_message(text)
+
+    async def send_test_notification(self) -> dict[str, str]:
+        """Send a small test message and PDF through every configured Slack destination.
+
+        Verifies credentials, channel membership and PDF rendering without
+        generating a report. Each destination is tried independently.
+
+        Returns:
+            Destination -> "ok" or the error message
+        """
+        text = f"🧪 K8s Watchdog test notification for `{settings.cluster_name}`"
+        html = (
+            "<!DOCTYPE html><html><head><meta charset=\"UTF-8\"></head><body>"
+            f"<h1>Test report</h1><p>Cluster {settings.cluster_name}: delivery works.</p>"
+            "</body></html>"
+        )
+        # (destination label, coroutine factory) pairs, tried one by one
+        attempts: list[tuple[str, Callable[[], Awaitable[None]]]] = []
+        if self.webhook_url:
+            attempts.append(("webhook", lambda: self.send_message(text)))
+
+        if self.bot_token:
+            files = await asyncio.to_thread(
+                self._build_files, html, f"watchdog-test-{settings.cluster_name}.pdf", None
+            )
+            targets = [self.channel, *self.dm_user_ids, settings.slack_review_channel]
+            for target in filter(None, targets):
+                attempts.append((target, lambda target=target: self._share_test_files(files, text, target)))
+            for status, channel in settings.slack_severity_routes.items():
+                attempts.append(
+                    (f"{channel} ({status})", lambda channel=channel: self.post_message(channel, text))
+                )
+
+        results: dict[str, str] = {}
+        for destination, send in attempts:
+            try:
+                await send()
+                results[destination] = "ok"
+            except (httpx.HTTPError, RuntimeError) as e:
+                results[destination] = str(e)
+                logger.error("slack_test_notification_failed", destination=destination, error=str(e))
+
+        logger.info("slack_test_notification_sent", results=results)
+
+        return results
+
+    async def _share_test_files(
+        self, files: list[tuple[str, bytes, str]], message: str, destination: str
+    ) -> None:
+        """Upload files to a channel, or to a user's DM when given a user ID."""
+        if destination.startswith("U"):
+            destination = await self._open_dm(destination)
+        await self._upload_files(files, message, destination)
+
+    async def send_review_request(
+        self,
+        report_id: int,
+        html_content: str,
+        filename: str = "cluster-health-report.pdf",
+        message: Optional[str] = None,
+        attachments: Optional[list[tuple[str, bytes]]] = None,
+    ) -> None:
+        """Send a report to the review channel with approve/discard buttons.
+
+        Args:
+            report_id: Stored report ID, carried in the button values
+            html_content: HTML content
+            filename: Filename for the attachment (should end in .pdf)
+            message: Optional message to accompany the report
+            attachments: Optional extra (filename, bytes) files shared with the PDF
+        """
+        async with self._track_delivery(f"review:{settings.slack_review_channel}", "pdf", 0) as delivery:
+            await self._send_review_request(report_id, html_content, filename, message, attachments, delivery)
+
+    async def _send_review_request(
+        self,
+        report_id: int,
+        html_content: str,
+        filename: str,
+        message: Optional[str],
+        attachments: Optional[list[tuple[str, bytes]]],
+        delivery: dict,
+    ) -> None:
+        """Share the report with the reviewers and post the approve/discard buttons."""
+        review_channel = settings.slack_review_channel
+        if review_channel.startswith("U"):
+            review_channel = await self._open_dm(review_channel)
+
+        try:
+            files = self._build_files(html_content, filename, attachments)
+            delivery["size_bytes"] = sum(len(f[1]) for f in files)
+            await self._upload_files(files, message, review_channel)
+        except PDFRenderError:
+            delivery.update(format="text", size_bytes=len(html_content.encode("utf-8")))
+            await self._send_text_report(html_content, filename, message, attachments, review_channel)
+
+        blocks = [
+            {
+                "type": "section",
+                "text": {
+                    "type": "mrkdwn",
+                    "text": (
+                        f"Report #{report_id} for `{settings.cluster_name}` is waiting for review. "
+                        f"It will only be posted to <#{self.channel}> once approved."
+                    ),
+                },
+            },
+            {
+                "type": "actions",
+                "block_id": "report_review",
+                "elements": [
+                    {
+                        "type": "button",
+                        "action_id": "approve_report",
+                        "text": {"type": "plain_text", "text": "Approve & Publish"},
+                        "style": "primary",
+                        "value": str(report_id),
+                    },
+                    {
+                        "type": "button",
+                        "action_id": "discard_report",
+                        "text": {"type": "plain_text", "text": "Discard"},
+                        "style": "danger",
+                        "value": str(report_id),
+                    },
+                ],
+            },
+        ]
+
+        async with httpx.AsyncClient(timeout=30.0) as client:
+            response = await client.post(
+                "https://slack.com/api/chat.postMessage",
+                headers={"Authorization": f"Bearer {self.bot_token}"},
+                json={
+                    "channel": review_channel,
+                    "text": f"Report #{report_id} is waiting for review",
+                    "blocks": blocks,
+                },
+            )
+            response.raise_for_status()
+            result = response.json()
+
+        if not result.get("ok"):
+            error_msg = result.get('error', 'Unknown error')
+            logger.error("slack_review_request_failed", report_id=report_id, error=error_msg)
+            raise RuntimeError(f"Slack API error (chat.postMessage): {error_msg}")
+
+        logger.info("slack_review_requested", report_id=report_id, channel=review_channel)
+
+    async def post_message(self, channel: str, text: str) -> None:
+        """Post a text message to a channel through the bot API.
+
+        Args:
+            channel: Channel ID
+            text: Message text (mrkdwn)
+        """
+        async with httpx.AsyncClient(timeout=30.0) as client:
+            response = await client.post(
+                "https://slack.com/api/chat.postMessage",
+                headers={"Authorization": f"Bearer {self.bot_token}"},
+                json={"channel": channel, "text": text},
+            )
+            response.raise_for_status()
+            result = response.json()
+
+        if not result.get("ok"):
+            error_msg = result.get('error', 'Unknown error')
+            logger.error("slack_post_message_failed", channel=channel, error=error_msg)
+            raise RuntimeError(f"Slack API error (chat.postMessage): {error_msg}")
+
+        logger.info("slack_message_posted", channel=channel, text_length=len(text))
+
+    async def set_channel_topic(self, channel: str, topic: str) -> None:
+        """Replace a channel topic through the bot API.
 
-    def _html_to_pdf(self, html_content: str) -> bytes:
+        Args:
+            channel: Channel ID
+            topic: New topic (Slack keeps the first 250 characters)
+        """
+        async with httpx.AsyncClient(timeout=30.0) as client:
+            response = await client.post(
+                "https://slack.com/api/conversations.setTopic",
+                headers={"Authorization": f"Bearer {self.bot_token}"},
+                json={"channel": channel, "topic": topic[:250]},
+            )
+            response.raise_for_status()
+            result = response.json()
+
+        if not result.get("ok"):
+            error_msg = result.get('error', 'Unknown error')
+            logger.error("slack_set_topic_failed", channel=channel, error=error_msg)
+            raise RuntimeError(f"Slack API error (conversations.setTopic): {error_msg}")
+
+        logger.info("slack_topic_updated", channel=channel)
+
+    async def update_review_message(self, response_url: str, text: str) -> None:
+        """Replace the review message (and its buttons) with a status line.
+
+        Args:
+            response_url: response_url from the Slack interaction payload
+            text: New message text
+        """
+        async with httpx.AsyncClient(timeout=30.0) as client:
+            response = await client.post(
+                response_url,
+                json={"replace_original": True, "text": text},
+            )
+            response.raise_for_status()
+
+    def _build_files(
+        self,
+        html_content: str,
+        filename: str,
+        attachments: Optional[list[tuple[str, bytes]]],
+    ) -> list[tuple[str, bytes, str]]:
+        """Render the PDF and bundle it with the CSV attachments.
+
+        Args:
+            html_content: HTML content
+            filename: PDF filename
+            attachments: Optional extra (filename, bytes) CSV files
+
+        Returns:
+            List of (filename, content, content_type) tuples
+
+        Raises:
+            PDFRenderError: If both the configured and the minimal rendering fail
+        """
+        logger.info("converting_html_to_pdf", html_size=len(html_content))
+        with span("pdfgen.render", html_size=len(html_content)):
+            try:
+                pdf_bytes = self._html_to_pdf(html_content)
+            except Exception as e:
+                # Second path: default options, without images and charts
+                logger.error("pdf_render_failed", error=str(e), error_type=type(e).__name__, retry="minimal")
+                try:
+                    pdf_bytes = self._html_to_pdf(IMAGE_PATTERN.sub("", html_content), minimal=True)
+                except Exception as e:
+                    logger.error("pdf_render_failed", error=str(e), error_type=type(e).__name__, retry="text")
+                    raise PDFRenderError(str(e)) from e
+        logger.info("pdf_generated", pdf_size=len(pdf_bytes))
+
+        files = [(filename, pdf_bytes, "application/pdf")]
+        for attachment_name, attachment_bytes in attachments or []:
+            files.append((attachment_name, attachment_bytes, "text/csv"))
+
+        return files
+
+    def _html_to_pdf(self, html_content: str, minimal: bool = False) -> bytes:
         """Convert HTML to PDF using WeasyPrint.
 
         Args:
             html_content: HTML content string
+            minimal: Ignore the PDF variant, zoom and image settings
 
         Returns:
             PDF as bytes
         """
+        if minimal:
+            pdf_buffer = BytesIO()
+            HTML(string=html_content, url_fetcher=_offline_url_fetcher).write_pdf(pdf_buffer)
+            return pdf_buffer.getvalue()
+
+        options = {"zoom": settings.pdf_zoom, "optimize_images": True}
+        if settings.pdf_variant:
+            options["pdf_variant"] = settings.pdf_variant
+        if settings.pdf_jpeg_quality:
+            options["jpeg_quality"] = settings.pdf_jpeg_quality
+        if settings.pdf_dpi:
+            options["dpi"] = settings.pdf_dpi
+
         # Create PDF in memory
         pdf_buffer = BytesIO()
-        HTML(string=html_content).write_pdf(pdf_buffer)
+        HTML(string=html_content, url_fetcher=_offline_url_fetcher).write_pdf(pdf_buffer, **options)
         return pdf_buffer.getvalue()
 
-    async def _upload_file_bytes(
+    async def _send_text_report(
         self,
-        content: bytes,
+        html_content: str,
         filename: str,
         message: Optional[str],
-        content_type: str = "application/octet-stream",
+        attachments: Optional[list[tuple[str, bytes]]],
+        channel: str,
+    ) -> None:
+        """Post the report as text when no PDF could be rendered.
+
+        Short reports are posted as consecutive messages; long ones as a first
+        message plus the full text shared as a snippet. CSV attachments are
+        still shared.
+
+        Args:
+            html_content: HTML content
+            filename: PDF filename, reused for the snippet
+            message: Optional message to accompany the report
+            attachments: Optional extra (filename, bytes) CSV files
+            channel: Channel or DM ID
+        """
+        text = html_to_slack_text(html_content)
+        chunks = chunk_text(text)
+        notice = "⚠️ The PDF could not be rendered, so the report follows as text."
+        header = f"{message}\n\n{notice}" if message else notice
+
+        files = [(name, content, "text/csv") for name, content in attachments or []]
+        if len(chunks) > MAX_TEXT_MESSAGES:
+            snippet = filename.rsplit(".", 1)[0] + ".txt"
+            await self.post_message(channel, f"{header}\n\n{chunks[0]}")
+            files.insert(0, (snippet, text.encode("utf-8"), "text/plain"))
+        else:
+            await self.post_message(channel, header)
+            for index, chunk in enumerate(chunks, start=1):
+                await self.post_message(channel, f"({index}/{len(chunks)})\n{chunk}")
+        if files:
+            await self._upload_files(files, None, channel)
+
+        logger.warning("slack_text_report_sent", channel=channel, chunks=len(chunks), text_length=len(text))
+
+    async def _open_dm(self, user_id: str) -> str:
+        """Open (or reuse) a direct message conversation with a user.
+
+        Args:
+            user_id: Slack user ID (e.g., U012AB3CDE4)
+
+        Returns:
+            DM channel ID
+        """
+        async with httpx.AsyncClient(timeout=30.0) as client:
+            response = await client.post(
+                "https://slack.com/api/conversations.open",
+                headers={"Authorization": f"Bearer {self.bot_token}"},
+                data={"users": user_id},
+            )
+            response.raise_for_status()
+            result = response.json()
+
+        if not result.get("ok"):
+            error_msg = result.get('error', 'Unknown error')
+            logger.error("slack_conversations_open_failed", user_id=user_id, error=error_msg)
+            raise RuntimeError(f"Slack API error (conversations.open): {error_msg}")
+
+        dm_channel = result["channel"]["id"]
+        logger.info("slack_dm_opened", user_id=user_id, channel=dm_channel)
+
+        return dm_channel
+
+    async def _upload_files(
+        self,
+        files: list[tuple[str, bytes, str]],
+        message: Optional[str],
+        channel: str,
     ) -> None:
-        """Upload file bytes to Slack using new files v2 API.
+        """Upload files to Slack using new files v2 API.
 
         Uses the 3-step process:
-        1. files.getUploadURLExternal
-        2. POST to external URL
-        3. files.completeUploadExternal
+        1. files.getUploadURLExternal (once per file)
+        2. POST to external URL (once per file)
+        3. files.completeUploadExternal (shares all files in one message)
 
         Args:
-            content: File content
-            filename: Filename
+            files: List of (filename, content, content_type) tuples
             message: Optional initial comment
+            channel: Channel or DM ID to share the files to
         """
+        with span("reporter.slack_upload", channel=channel, files=len(files)):
+            await self._upload_files_v2(files, message, channel)
+
+    async def _upload_files_v2(
+        self,
+        files: list[tuple[str, bytes, str]],
+        message: Optional[str],
+        channel: str,
+    ) -> None:
+        """Run the three-step files v2 upload (see _upload_files)."""
         auth_headers = {
             "Authorization": f"Bearer {self.bot_token}",
         }
 
-        file_size = len(content)
+        uploaded = []
 
         async with httpx.AsyncClient(timeout=60.0) as client:
-            # Step 1: Get upload URL (form-urlencoded)
-            step1_data = {
-                "filename": filename,
-                "length": str(file_size),
-            }
+            for filename, content, content_type in files:
+                file_size = len(content)
 
-            logger.info("requesting_upload_url", filename=filename, size=file_size)
+                # Step 1: Get upload URL (form-urlencoded)
+                step1_data = {
+                    "filename": filename,
+                    "length": str(file_size),
+                }
 
-            step1_response = await client.post(
-                "https://slack.com/api/files.getUploadURLExternal",
-                headers=auth_headers,
-                data=step1_data,
-            )
-            step1_response.raise_for_status()
-            step1_result = step1_response.json()
+                logger.info("requesting_upload_url", filename=filename, size=file_size)
 
-            logger.info("step1_response", result=step1_result)
+                step1_response = await client.post(
+                    "https://slack.com/api/files.getUploadURLExternal",
+                    headers=auth_headers,
+                    data=step1_data,
+                )
+                step1_response.raise_for_status()
+                step1_result = step1_response.json()
 
-            if not step1_result.get("ok"):
-                error_msg = step1_result.get('error', 'Unknown error')
-                logger.error("slack_api_step1_failed", error=error_msg, response=step1_result)
-                raise RuntimeError(f"Slack API error (step 1): {error_msg}")
+                logger.info("step1_response", result=step1_result)
 
-            upload_url = step1_result["upload_url"]
-            file_id = step1_result["file_id"]
+                if not step1_result.get("ok"):
+                    error_msg = step1_result.get('error', 'Unknown error')
+                    logger.error("slack_api_step1_failed", error=error_msg, response=step1_result)
+                    raise RuntimeError(f"Slack API error (step 1): {error_msg}")
 
-            logger.info("slack_upload_url_obtained", file_id=file_id)
+                upload_url = step1_result["upload_url"]
+                file_id = step1_result["file_id"]
 
-            # Step 2: Upload to external URL
-            step2_response = await client.post(
-                upload_url,
-                content=content,
-                headers={"Content-Type": content_type},
-            )
-            step2_response.raise_for_status()
+                logger.info("slack_upload_url_obtained", file_id=file_id)
+
+                # Step 2: Upload to external URL
+                step2_response = await client.post(
+                    upload_url,
+                    content=content,
+                    headers={"Content-Type": content_type},
+                )
+                step2_response.raise_for_status()
 
-            logger.info("slack_file_uploaded_to_external", file_id=file_id, status=step2_response.status_code)
+                logger.info("slack_file_uploaded_to_external", file_id=file_id, status=step2_response.status_code)
+
+                uploaded.append({
+                    "id": file_id,
+                    "title": "Weekly Cluster Health Report" if content_type == "application/pdf" else filename,
+                })
 
             # Step 3: Complete upload and share to channel (form-urlencoded with JSON string)
-            import json
             step3_data = {
-                "files": json.dumps([
-                    {
-                        "id": file_id,
-                        "title": "Weekly Cluster Health Report",
-                    }
-                ]),
-                "channel_id": self.channel,
+                "files": json.dumps(uploaded),
+                "channel_id": channel,
             }
 
             if message:
@@ -181,4 +618,9 @@ class SlackReporter:
                 logger.error("slack_api_step3_failed", error=error_msg, response=step3_result)
                 raise RuntimeError(f"Slack API error (step 3): {error_msg}")
 
-        logger.info("slack_file_shared", filename=filename, channel=self.channel, file_id=file_id)
+        logger.info(
+            "slack_files_shared",
+            filenames=[f[0] for f in files],
+            channel=channel,
+            file_ids=[f["id"] for f in uploaded],
+        )
@@ -0,0 +1,46 @@
+from html import escape
+
+
+def build_system_components_section(components: list[dict], max_components: int = 25) -> str:
+    """Render the control plane & add-ons health table (CoreDNS, CNI, metrics-server...).
+
+    Args:
+        components: Output of collect_system_components(), unhealthy first
+        max_components: Maximum number of rows
+
+    Returns:
+        HTML section, or an empty string when no system components were found
+    """
+    if not components:
+        return ""
+
+    unhealthy = [c for c in components if not c["healthy"]]
+    if unhealthy:
+        summary = f"{len(unhealthy)} of {len(components)} system components are not fully ready."
+    else:
+        summary = f"All {len(components)} system components are ready."
+
+    rows = []
+    for component in components[:max_components]:
+        status_style = "color:#1E7B3C;" if component["healthy"] else "color:#C00000;"
+        rows.append(
+            "<tr>"
+            f'<td style="padding:6px;"><code>{escape(component["namespace"])}/{escape(component["name"])}</code></td>'
+            f'<td style="padding:6px;">{escape(component["kind"])}</td>'
+            f'<td style="padding:6px;">{escape(component["category"])}</td>'
+            f'<td style="padding:6px;text-align:right;font-weight:600;{status_style}">'
+            f'{component["ready"]}/{component["desired"]}</td>'
+            f'<td style="padding:6px;text-align:right;">{component["restarts"]}</td>'
+            "</tr>"
+        )
+
+    return f"""<div class="section watchdog-system-components">
+  <h2>Control Plane &amp; Add-ons</h2>
+  <p>{escape(summary)}</p>
+  <table style="border-collapse:collapse;width:100%;">
+    <thead><tr><th style="padding:6px;text-align:left;">Component</th><th style="padding:6px;text-align:left;">Kind</th><th style="padding:6px;text-align:left;">Category</th><th style="padding:6px;text-align:right;">Ready</th><th style="padding:6px;text-align:right;">Restarts</th></tr></thead>
+    <tbody>
+      {"".join(rows)}
+    </tbody>
+  </table>
+</div>"""
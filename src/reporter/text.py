@@ -0,0 +1,121 @@
+import re
+from html.parser import HTMLParser
+
+# Slack truncates chat.postMessage text at 40,000 characters and folds long
+# messages; chunks of this size stay readable and well under the limit
+SLACK_CHUNK_SIZE = 3500
+
+BLOCK_TAGS = {"p", "div", "section", "table", "thead", "tbody", "ul", "ol", "pre", "blockquote"}
+HEADING_TAGS = {"h1", "h2", "h3", "h4"}
+SKIPPED_TAGS = {"style", "script", "svg", "head", "title"}
+
+
+class _SlackTextParser(HTMLParser):
+    """Flatten report HTML into Slack mrkdwn text."""
+
+    def __init__(self) -> None:
+        super().__init__(convert_charrefs=True)
+        self.parts: list[str] = []
+        self._skip_depth = 0
+        self._row: list[str] = []
+        self._cell: list[str] = []
+        self._in_cell = False
+
+    def _write(self, text: str) -> None:
+        if self._in_cell:
+            self._cell.append(text)
+        else:
+            self.parts.append(text)
+
+    def handle_starttag(self, tag: str, attrs: list) -> None:
+        if tag in SKIPPED_TAGS:
+            self._skip_depth += 1
+        elif self._skip_depth:
+            return
+        elif tag in HEADING_TAGS:
+            self.parts.append("\n\n*")
+        elif tag == "li":
+            self.parts.append("\n• ")
+        elif tag == "br":
+            self._write("\n")
+        elif tag in ("strong", "b"):
+            self._write("*")
+        elif tag == "code":
+            self._write("`")
+        elif tag == "tr":
+            self._row = []
+        elif tag in ("td", "th"):
+            self._in_cell, self._cell = True, []
+        elif tag in BLOCK_TAGS:
+            self.parts.append("\n")
+
+    def handle_endtag(self, tag: str) -> None:
+        if tag in SKIPPED_TAGS:
+            self._skip_depth = max(0, self._skip_depth - 1)
+        elif self._skip_depth:
+            return
+        elif tag in HEADING_TAGS:
+            self.parts.append("*\n")
+        elif tag in ("strong", "b"):
+            self._write("*")
+        elif tag == "code":
+            self._write("`")
+        elif tag in ("td", "th"):
+            self._row.append(" ".join("".join(self._cell).split()))
+            self._in_cell = False
+        elif tag == "tr":
+            self.parts.append("\n" + " | ".join(cell for cell in self._row))
+        elif tag in BLOCK_TAGS:
+            self.parts.append("\n")
+
+    def handle_data(self, data: str) -> None:
+        if self._skip_depth:
+            return
+        self._write(re.sub(r"\s+", " ", data))
+
+
+def html_to_slack_text(report_html: str) -> str:
+    """Convert a report to plain Slack mrkdwn (headings, lists and table rows).
+
+    Args:
+        report_html: Report HTML
+
+    Returns:
+        Text with at most one blank line between blocks
+    """
+    parser = _SlackTextParser()
+    parser.feed(report_html)
+    parser.close()
+    lines = [line.strip() for line in "".join(parser.parts).splitlines()]
+    text = "\n".join(line for line in lines if line not in ("**", "``"))
+    return re.sub(r"\n{3,}", "\n\n", text).strip()
+
+
+def chunk_text(text: str, size: int = SLACK_CHUNK_SIZE) -> list[str]:
+    """Split text into chunks of at most `size` characters on line boundaries.
+
+    Lines longer than a chunk are split on their own.
+
+    Args:
+        text: Text to split
+        size: Maximum chunk length
+
+    Returns:
+        List of chunks
+    """
+    chunks, current = [], ""
+    for line in text.splitlines():
+        while len(line) > size:
+            if current:
+                chunks.append(current)
+                current = ""
+            chunks.append(line[:size])
+            line = line[size:]
+        if current and len(current) + 1 + len(line) > size:
+            chunks.append(current)
+            current = line
+        else:
+            current = f"{current}\n{line}" if current else line
+    if current:
+        chunks.append(current)
+    return chunks
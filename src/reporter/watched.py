@@ -0,0 +1,68 @@
+from datetime import date, timedelta
+from html import escape
+
+
+def _availability_color(ready_pct: float) -> str:
+    """Background color of a day cell by its lowest ready percentage."""
+    if ready_pct >= 100:
+        return "#E3F4E8"
+    if ready_pct >= 50:
+        return "#FFE8B3"
+    return "#FF6B5B"
+
+
+def build_watched_workloads_section(watched: list[dict], days: int = 7) -> str:
+    """Render the daily availability and restart trend of each watched workload.
+
+    Watched workloads are always listed, healthy or not.
+
+    Args:
+        watched: Output of summarize_workload_samples()
+        days: Number of days (columns) ending today
+
+    Returns:
+        HTML section, or an empty string when nothing was sampled
+    """
+    if not watched:
+        return ""
+
+    day_columns = [
+        (date.today() - timedelta(days=offset)).isoformat()
+        for offset in range(days - 1, -1, -1)
+    ]
+    header_cells = "".join(
+        f'<th style="padding:6px;font-size:12px;">{escape(day[5:])}</th>' for day in day_columns
+    )
+
+    rows = []
+    for workload in watched:
+        cells = []
+        for day in day_columns:
+            values = workload["days"].get(day)
+            if values is None:
+                cells.append('<td style="padding:6px;text-align:center;color:#999;">-</td>')
+                continue
+            cells.append(
+                f'<td style="padding:6px;text-align:center;background:{_availability_color(values["min_ready_pct"])};"'
+                f' title="lowest ready {values["min_ready_pct"]}%">{values["restarts"]}</td>'
+            )
+        rows.append(
+            "<tr>"
+            f'<td style="padding:6px;"><code>{escape(workload["namespace"])}/{escape(workload["name"])}</code>'
+            f'<div style="font-size:11px;color:#555;">{escape(workload["kind"])}, '
+            f'{workload["ready"]}/{workload["desired"]} ready now</div></td>'
+            f'{"".join(cells)}'
+            f'<td style="padding:6px;text-align:right;font-weight:600;">{workload["restarts"]}</td>'
+            "</tr>"
+        )
+
+    return f"""<div class="section watchdog-watched-workloads">
+  <h2>Watched Workloads</h2>
+  <p>Sampled every few minutes. Cells show restarts per day; the color shows the lowest share of ready replicas that day (green = always fully ready).</p>
+  <table style="border-collapse:collapse;width:100%;">
+    <thead><tr><th style="padding:6px;text-align:left;">Workload</th>{header_cells}<th style="padding:6px;text-align:right;">Restarts</th></tr></thead>
+    <tbody>
+      {"".join(rows)}
+    </tbody>
+  </table>
+</div>"""
@@ -0,0 +1,6 @@
+"""Deterministic cluster statistics computed before the AI analysis."""
+
+from .cluster import collect_cluster_stats, format_stats_for_prompt
+from .dependencies import infer_dependencies
+
+__all__ = ["collect_cluster_stats", "format_stats_for_prompt", "infer_dependencies"]
@@ -0,0 +1,117 @@
+import re
+from typing import Optional
+
+import structlog
+from kubernetes import client
+
+from src.kube import get_api_client
+
+logger = structlog.get_logger()
+
+# Well-known add-ons: (name, image repository suffix, oldest version still
+# supported upstream or None when the version follows the cluster, EOL note).
+# Update the minimum versions as upstream support windows move.
+KNOWN_ADDONS = [
+    ("CoreDNS", "coredns", (1, 11), None),
+    ("kube-proxy", "kube-proxy", None, None),
+    (
+        "ingress-nginx", "ingress-nginx/controller", None,
+        "ingress-nginx was retired upstream in March 2026; plan a migration to another controller or Gateway API",
+    ),
+    ("Traefik", "traefik", (3, 0), None),
+    ("Cilium", "cilium/cilium", (1, 16), None),
+    ("Calico", "calico/node", (3, 28), None),
+    ("Flannel", "flannel", (0, 25), None),
+    ("AWS VPC CNI", "amazon-k8s-cni", (1, 18), None),
+    ("metrics-server", "metrics-server", (0, 7), None),
+    ("cluster-autoscaler", "cluster-autoscaler", None, None),
+    ("cert-manager", "cert-manager-controller", (1, 15), None),
+    ("external-dns", "external-dns", (0, 14), None),
+]
+
+VERSION_PATTERN = re.compile(r"v?(\d+)\.(\d+)(?:\.(\d+))?")
+
+
+def _split_image(image: str) -> tuple[str, str]:
+    """Split an image reference into repository and tag (empty when untagged)."""
+    reference = image.split("@", 1)[0]
+    head, _, last = reference.rpartition("/")
+    name, _, tag = last.partition(":")
+    return (f"{head}/{name}" if head else name), tag
+
+
+def _image_version(image: str) -> Optional[str]:
+    """Return the version in an image tag (v1.11.1, 1.9.4-eksbuild.1...), if any."""
+    match = VERSION_PATTERN.match(_split_image(image)[1])
+    return match.group(0).lstrip("v") if match else None
+
+
+def _match_addon(image: str) -> Optional[tuple]:
+    """Find the known add-on an image belongs to."""
+    repository = _split_image(image)[0]
+    return next((addon for addon in KNOWN_ADDONS if repository.endswith(addon[1])), None)
+
+
+def _status(version: Optional[str], minimum: Optional[tuple], eol_note: Optional[str]) -> str:
+    """Classify an add-on version as eol, outdated, ok or unknown."""
+    if eol_note:
+        return "eol"
+    if minimum is None:
+        return "ok"
+    if version is None:
+        return "unknown"
+    major, minor = (int(part) for part in version.split(".")[:2])
+    return "outdated" if (major, minor) < minimum else "ok"
+
+
+def collect_addon_inventory() -> list[dict]:
+    """Detect well-known cluster add-ons and their versions from container images.
+
+    Deployments and DaemonSets of every namespace are inspected, since
+    ingress controllers and cert-manager usually live outside kube-system.
+
+    Returns:
+        One entry per add-on workload (addon, namespace, workload, version,
+        minimum supported version, status, note), problems first
+    """
+    apps_v1 = client.AppsV1Api(get_api_client())
+
+    inventory = []
+    for kind, items in (
+        ("Deployment", apps_v1.list_deployment_for_all_namespaces().items),
+        ("DaemonSet", apps_v1.list_daemon_set_for_all_namespaces().items),
+    ):
+        for item in items:
+            for container in item.spec.template.spec.containers or []:
+                addon = _match_addon(container.image or "")
+                if not addon:
+                    continue
+                name, _, minimum, eol_note = addon
+                version = _image_version(container.image)
+                status = _status(version, minimum, eol_note)
+                inventory.append({
+                    "addon": name,
+                    "namespace": item.metadata.namespace,
+                    "workload": item.metadata.name,
+                    "kind": kind,
+                    "version": version,
+                    "minimum_version": ".".join(str(part) for part in minimum) if minimum else None,
+                    "status": status,
+                    "note": eol_note or (
+                        f"older than {minimum[0]}.{minimum[1]}, the oldest release still supported upstream"
+                        if status == "outdated" else None
+                    ),
+                })
+                break
+
+    order = {"eol": 0, "outdated": 1, "unknown": 2, "ok": 3}
+    inventory.sort(key=lambda a: (order[a["status"]], a["addon"]))
+
+    logger.info(
+        "addon_inventory_collected",
+        addons=len(inventory),
+        outdated=sum(1 for a in inventory if a["status"] in ("eol", "outdated")),
+        source="stats",
+    )
+
+    return inventory
@@ -0,0 +1,164 @@
+from collections import Counter
+from typing import Optional
+
+import structlog
+from kubernetes import client
+from kubernetes.utils import parse_quantity
+
+from src.kube import get_api_client
+from src.reporter.heatmap import workload_name
+
+logger = structlog.get_logger()
+
+# Rows kept per table, so the appendix does not bloat every stored snapshot
+MAX_APPENDIX_ROWS = 200
+
+MIB = 1024 ** 2
+
+
+def _pod_usage() -> Optional[dict[tuple[str, str], tuple[float, float]]]:
+    """Read current pod CPU (cores) and memory (bytes) usage from metrics-server.
+
+    Returns:
+        (namespace, pod) -> (CPU, memory) summed across containers, or None when
+        metrics.k8s.io is not available
+    """
+    try:
+        metrics = client.CustomObjectsApi(get_api_client()).list_cluster_custom_object(
+            "metrics.k8s.io", "v1beta1", "pods"
+        )
+    except client.ApiException as e:
+        logger.warning("pod_metrics_unavailable", status=e.status, source="stats")
+        return None
+
+    usage = {}
+    for item in metrics.get("items", []):
+        containers = item.get("containers", [])
+        cpu = sum(float(parse_quantity(c["usage"].get("cpu", "0"))) for c in containers)
+        memory = sum(float(parse_quantity(c["usage"].get("memory", "0"))) for c in containers)
+        usage[(item["metadata"]["namespace"], item["metadata"]["name"])] = (cpu, memory)
+    return usage
+
+
+def _restarting_pods(pods: list[client.V1Pod], terminations: Optional[list[dict]]) -> list[dict]:
+    """Pods by restarts, from the pod watcher's week when recorded, lifetime counters otherwise."""
+    restarts: Counter = Counter()
+    reasons: dict[tuple[str, str], str] = {}
+    if terminations is not None:
+        for termination in terminations:
+            key = (termination["namespace"], termination["pod"])
+            restarts[key] += 1
+            reasons[key] = termination.get("reason") or ""
+    else:
+        for pod in pods:
+            key = (pod.metadata.namespace, pod.metadata.name)
+            for cs in pod.status.container_statuses or []:
+                restarts[key] += cs.restart_count
+                terminated = cs.last_state.terminated if cs.last_state else None
+                if cs.restart_count and terminated:
+                    reasons[key] = terminated.reason or ""
+
+    return [
+        {
+            "namespace": namespace,
+            "pod": pod,
+            "restarts": count,
+            "reason": reasons.get((namespace, pod), ""),
+        }
+        for (namespace, pod), count in restarts.most_common(MAX_APPENDIX_ROWS)
+        if count
+    ]
+
+
+def _rightsizing(pods: list[client.V1Pod], usage: Optional[dict]) -> list[dict]:
+    """Requests and limits of each workload next to its current usage."""
+    totals: dict[tuple[str, str], dict] = {}
+    for pod in pods:
+        if pod.status.phase != "Running":
+            continue
+        key = (pod.metadata.namespace, workload_name(pod.metadata.name))
+        entry = totals.setdefault(key, {
+            "pods": 0,
+            "cpu_request": 0.0, "cpu_limit": 0.0, "memory_request": 0.0, "memory_limit": 0.0,
+            "cpu_usage": 0.0, "memory_usage": 0.0, "measured": False,
+        })
+        entry["pods"] += 1
+        for container in pod.spec.containers or []:
+            resources = container.resources
+            for kind, values in (("request", resources.requests if resources else None),
+                                 ("limit", resources.limits if resources else None)):
+                for resource in ("cpu", "memory"):
+                    if resource in (values or {}):
+                        entry[f"{resource}_{kind}"] += float(parse_quantity(values[resource]))
+        if usage and (pod.metadata.namespace, pod.metadata.name) in usage:
+            cpu, memory = usage[(pod.metadata.namespace, pod.metadata.name)]
+            entry["cpu_usage"] += cpu
+            entry["memory_usage"] += memory
+            entry["measured"] = True
+
+    rows = []
+    for (namespace, workload), entry in totals.items():
+        for resource, unit, scale in (("cpu", "cores", 1), ("memory", "MiB", MIB)):
+            request = entry[f"{resource}_request"]
+            limit = entry[f"{resource}_limit"]
+            used = entry[f"{resource}_usage"] if entry["measured"] else None
+            rows.append({
+                "namespace": namespace,
+                "workload": workload,
+                "resource": f"{resource} ({unit})",
+                "pods": entry["pods"],
+                "request": round(request / scale, 3) if request else None,
+                "limit": round(limit / scale, 3) if limit else None,
+                "usage": round(used / scale, 3) if used is not None else None,
+                "usage_pct_of_request": (
+                    round(used / request * 100, 1) if used is not None and request else None
+                ),
+            })
+
+    # Most over-provisioned first; workloads without requests or usage last
+    rows.sort(key=lambda r: (r["usage_pct_of_request"] is None, r["usage_pct_of_request"] or 0))
+    return rows[:MAX_APPENDIX_ROWS]
+
+
+def _warning_events(events: list) -> list[dict]:
+    """Warning events grouped by namespace, reason and involved object."""
+    counts: Counter = Counter()
+    last_seen: dict[tuple, str] = {}
+    for event in events:
+        obj = event.involved_object
+        key = (event.metadata.namespace, event.reason or "", f"{obj.kind}/{obj.name}")
+        counts[key] += event.count or 1
+        seen = event.last_timestamp or event.event_time or event.metadata.creation_timestamp
+        if seen:
+            last_seen[key] = max(last_seen.get(key, ""), seen.isoformat())
+
+    rows = []
+    for (namespace, reason, obj), count in counts.most_common(MAX_APPENDIX_ROWS):
+        rows.append({
+            "namespace": namespace,
+            "reason": reason,
+            "object": obj,
+            "count": count,
+            "last_seen": last_seen.get((namespace, reason, obj), ""),
+        })
+    return rows
+
+
+def collect_appendix_tables(
+    pods: list[client.V1Pod], events: list, terminations: Optional[list[dict]] = None
+) -> dict[str, list[dict]]:
+    """Collect the tables of the CSV appendices from the cluster, not from the model.
+
+    Args:
+        pods: In-scope pods of the cluster
+        events: In-scope warning events
+        terminations: Container terminations recorded by the pod watcher
+
+    Returns:
+        Dict with restarting_pods, rightsizing and warning_events rows
+    """
+    return {
+        "restarting_pods": _restarting_pods(pods, terminations),
+        "rightsizing": _rightsizing(pods, _pod_usage()),
+        "warning_events": _warning_events(events),
+    }
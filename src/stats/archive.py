@@ -0,0 +1,104 @@
+from typing import Optional
+
+from kubernetes import client
+
+from src.config import settings
+from src.kube import get_api_client
+from src.stats.exclusions import collect_exclusions, filter_excluded_pods
+from src.stats.placement import PRESSURE_CONDITIONS
+
+# Waiting reasons that mean a container cannot start (ContainerCreating is normal)
+FAILING_WAITING_REASONS = {
+    "CrashLoopBackOff",
+    "ImagePullBackOff",
+    "ErrImagePull",
+    "InvalidImageName",
+    "CreateContainerConfigError",
+    "CreateContainerError",
+    "RunContainerError",
+}
+
+REDACTED = "<redacted>"
+
+
+def _pod_problem(pod: client.V1Pod) -> Optional[str]:
+    """Return why a pod is unhealthy, or None when it is fine."""
+    phase = pod.status.phase
+    if phase in ("Failed", "Unknown"):
+        return pod.status.reason or phase
+    for cs in (pod.status.init_container_statuses or []) + (pod.status.container_statuses or []):
+        waiting = cs.state.waiting if cs.state else None
+        if waiting and waiting.reason in FAILING_WAITING_REASONS:
+            return waiting.reason
+    if phase == "Pending":
+        return "Pending"
+    if phase == "Running":
+        ready = next((c for c in pod.status.conditions or [] if c.type == "Ready"), None)
+        if not (ready and ready.status == "True"):
+            return "NotReady"
+    return None
+
+
+def _node_problem(node: client.V1Node) -> Optional[str]:
+    """Return why a node is unhealthy, or None when it is fine."""
+    conditions = {c.type: c.status for c in node.status.conditions or []}
+    if conditions.get("Ready") != "True":
+        return "NotReady"
+    return next((c for c in PRESSURE_CONDITIONS if conditions.get(c) == "True"), None)
+
+
+def _serialize(obj) -> dict:
+    """Convert an API object to its JSON form, without managed fields or literal env values.
+
+    Literal environment values often hold credentials; the variable names and
+    their valueFrom references are kept.
+    """
+    data = client.ApiClient().sanitize_for_serialization(obj)
+    data.get("metadata", {}).pop("managedFields", None)
+    spec = data.get("spec") or {}
+    for container in (spec.get("initContainers") or []) + (spec.get("containers") or []):
+        for env in container.get("env") or []:
+            if "value" in env:
+                env["value"] = REDACTED
+    return data
+
+
+def collect_unhealthy_objects(max_objects: int) -> list[dict]:
+    """Capture the full objects of unhealthy pods and nodes for postmortems.
+
+    Out-of-scope and opted-out pods are skipped. Nodes come first, then pods
+    in namespace/name order, up to max_objects in total.
+
+    Args:
+        max_objects: Maximum number of objects to capture
+
+    Returns:
+        List of dicts with kind, namespace, name, reason and object (JSON form)
+    """
+    core_v1 = client.CoreV1Api(get_api_client())
+
+    objects = [
+        {"kind": "Node", "namespace": None, "name": node.metadata.name, "reason": reason, "object": node}
+        for node in core_v1.list_node().items
+        if (reason := _node_problem(node))
+    ]
+
+    pods = filter_excluded_pods(
+        [
+            pod for pod in core_v1.list_pod_for_all_namespaces().items
+            if settings.namespace_in_scope(pod.metadata.namespace)
+        ],
+        collect_exclusions(),
+    )
+    for pod in sorted(pods, key=lambda p: (p.metadata.namespace, p.metadata.name)):
+        reason = _pod_problem(pod)
+        if reason:
+            objects.append({
+                "kind": "Pod",
+                "namespace": pod.metadata.namespace,
+                "name": pod.metadata.name,
+                "reason": reason,
+                "object": pod,
+            })
+
+    return [{**entry, "object": _serialize(entry["object"])} for entry in objects[:max_objects]]
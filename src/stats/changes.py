@@ -0,0 +1,88 @@
+from datetime import datetime, timedelta, timezone
+from typing import Optional
+
+import structlog
+from kubernetes import client
+
+from src.config import settings
+from src.kube import get_api_client
+
+logger = structlog.get_logger()
+
+REVISION_ANNOTATION = "deployment.kubernetes.io/revision"
+CHANGE_CAUSE_ANNOTATION = "kubernetes.io/change-cause"
+
+
+def _revision(replica_set) -> int:
+    """Return the Deployment revision a ReplicaSet was created for (0 when unknown)."""
+    value = (replica_set.metadata.annotations or {}).get(REVISION_ANNOTATION, "0")
+    return int(value) if value.isdigit() else 0
+
+
+def _images(replica_set) -> list[str]:
+    """List the container images of a ReplicaSet's pod template."""
+    return [container.image for container in replica_set.spec.template.spec.containers or []]
+
+
+def collect_recent_rollouts(days: int = 7, exclusions: Optional[dict] = None) -> list[dict]:
+    """List Deployment rollouts of the last days from the ReplicaSet revision history.
+
+    Every rollout creates a ReplicaSet annotated with the Deployment revision;
+    kubernetes.io/change-cause is copied from the Deployment when the operator
+    sets it. Rollbacks reactivate an older ReplicaSet and are not dated, so
+    they are not listed.
+
+    Args:
+        days: Look-back window
+        exclusions: Output of collect_exclusions(), to skip opted-out workloads
+
+    Returns:
+        One entry per rollout (namespace, deployment, revision, rolled out at,
+        change cause, images and previous images when they changed), newest first
+    """
+    since = datetime.now(timezone.utc) - timedelta(days=days)
+    apps_v1 = client.AppsV1Api(get_api_client())
+
+    excluded_workloads = {
+        (w["namespace"], w["name"]) for w in (exclusions or {}).get("workloads", []) if w["kind"] == "Deployment"
+    }
+
+    history: dict[tuple[str, str], list] = {}
+    for replica_set in apps_v1.list_replica_set_for_all_namespaces().items:
+        owner = next(
+            (ref for ref in replica_set.metadata.owner_references or [] if ref.kind == "Deployment"), None
+        )
+        namespace = replica_set.metadata.namespace
+        if (
+            not owner
+            or not settings.namespace_in_scope(namespace)
+            or namespace in (exclusions or {}).get("namespaces", [])
+            or (namespace, owner.name) in excluded_workloads
+        ):
+            continue
+        history.setdefault((namespace, owner.name), []).append(replica_set)
+
+    rollouts = []
+    for (namespace, deployment), replica_sets in history.items():
+        replica_sets.sort(key=_revision)
+        for previous, current in zip([None] + replica_sets, replica_sets):
+            created = current.metadata.creation_timestamp
+            if not created or created < since:
+                continue
+            previous_images = _images(previous) if previous else []
+            images = _images(current)
+            rollouts.append({
+                "namespace": namespace,
+                "deployment": deployment,
+                "revision": _revision(current),
+                "rolled_out_at": created.isoformat(),
+                "change_cause": (current.metadata.annotations or {}).get(CHANGE_CAUSE_ANNOTATION),
+                "images": images,
+                "previous_images": previous_images if previous_images != images else [],
+            })
+
+    rollouts.sort(key=lambda r: r["rolled_out_at"], reverse=True)
+
+    logger.info("recent_rollouts_collected", rollouts=len(rollouts), days=days, source="stats")
+
+    return rollouts
@@ -0,0 +1,826 @@
+import os
+import re
+from collections import Counter
+from typing import Optional
+
+import structlog
+from kubernetes import client
+from kubernetes.utils import parse_quantity
+
+from src.config import settings
+from src.kube import get_api_client
+from src.stats.addons import collect_addon_inventory
+from src.stats.appendix import collect_appendix_tables
+from src.stats.changes import collect_recent_rollouts
+from src.stats.control_plane import collect_control_plane_health
+from src.stats.correlation import correlate_restarts_with_events
+from src.stats.credentials import collect_credential_risks
+from src.stats.deadline import CollectorDeadlines, parse_collector_timeouts
+from src.stats.event_severity import parse_severity_overrides, summarize_event_severities
+from src.stats.exclusions import collect_exclusions, filter_excluded_pods, is_excluded
+from src.stats.exit_codes import summarize_exit_codes
+from src.stats.nodes import collect_node_stability, list_node_events
+from src.stats.placement import collect_pod_placement, correlate_node_incidents
+from src.stats.system import collect_system_components
+from src.stats.thresholds import evaluate_thresholds, parse_thresholds
+from src.stats.webhooks import collect_webhook_failures
+from src.stats.workload_classes import summarize_workload_classes
+
+logger = structlog.get_logger()
+
+# providerID prefix -> cloud provider
+PROVIDER_PREFIXES = {
+    "aws": "aws",
+    "gce": "gcp",
+    "azure": "azure",
+    "digitalocean": "digitalocean",
+    "hcloud": "hetzner",
+    "openstack": "openstack",
+    "vsphere": "vsphere",
+}
+
+# Node labels that carry the managed cluster name, per provider
+CLUSTER_NAME_LABELS = [
+    "eks.amazonaws.com/cluster-name",
+    "alpha.eksctl.io/cluster-name",
+    "kubernetes.azure.com/cluster",
+    "doks.digitalocean.com/cluster-id",
+]
+
+# Node labels that carry the node pool / node group, per provider
+NODE_POOL_LABELS = [
+    "eks.amazonaws.com/nodegroup",
+    "cloud.google.com/gke-nodepool",
+    "kubernetes.azure.com/agentpool",
+    "doks.digitalocean.com/node-pool",
+    "karpenter.sh/nodepool",
+    "karpenter.sh/provisioner-name",
+]
+
+IAM_ROLE_ARN_PATTERN = re.compile(r"^arn:aws[\w-]*:iam::(\d{12}):")
+GCE_PROVIDER_ID_PATTERN = re.compile(r"^gce://([^/]+)/")
+AZURE_PROVIDER_ID_PATTERN = re.compile(r"/subscriptions/([^/]+)/", re.IGNORECASE)
+
+
+def _pod_requests(pod: client.V1Pod) -> tuple[float, float]:
+    """Sum CPU (cores) and memory (bytes) requests across a pod's containers."""
+    cpu = 0.0
+    memory = 0.0
+    for container in pod.spec.containers or []:
+        requests = (container.resources.requests or {}) if container.resources else {}
+        if "cpu" in requests:
+            cpu += float(parse_quantity(requests["cpu"]))
+        if "memory" in requests:
+            memory += float(parse_quantity(requests["memory"]))
+    return cpu, memory
+
+
+def _resource_coverage(pods: list[client.V1Pod]) -> list[dict]:
+    """Count containers missing CPU/memory requests or limits per namespace.
+
+    Args:
+        pods: Pods to inspect (completed pods are ignored)
+
+    Returns:
+        Per-namespace coverage, worst request coverage first
+    """
+    counts: dict[str, dict[str, int]] = {}
+    for pod in pods:
+        if pod.status.phase not in ("Running", "Pending"):
+            continue
+        entry = counts.setdefault(
+            pod.metadata.namespace, {"containers": 0, "missing_requests": 0, "missing_limits": 0}
+        )
+        for container in pod.spec.containers or []:
+            resources = container.resources
+            requests = (resources.requests or {}) if resources else {}
+            limits = (resources.limits or {}) if resources else {}
+            entry["containers"] += 1
+            if "cpu" not in requests or "memory" not in requests:
+                entry["missing_requests"] += 1
+            if "cpu" not in limits or "memory" not in limits:
+                entry["missing_limits"] += 1
+
+    coverage = [
+        {
+            "namespace": namespace,
+            **entry,
+            "missing_requests_pct": _percent(entry["missing_requests"], entry["containers"]),
+            "missing_limits_pct": _percent(entry["missing_limits"], entry["containers"]),
+        }
+        for namespace, entry in counts.items()
+    ]
+
+    return sorted(coverage, key=lambda c: (c["missing_requests_pct"] or 0, c["containers"]), reverse=True)
+
+
+def _pod_label_groups(pods: list[client.V1Pod], keys: list[str]) -> list[dict]:
+    """Group pods by their allowlisted labels/annotations.
+
+    Only distinct combinations are stored (with a pod count per namespace),
+    so snapshot size grows with the number of teams/apps, not pods.
+
+    Args:
+        pods: Pods to inspect
+        keys: Allowlisted label/annotation keys (labels win over annotations)
+
+    Returns:
+        List of {namespace, labels, pods}, largest groups first
+    """
+    if not keys:
+        return []
+
+    groups: Counter = Counter()
+    for pod in pods:
+        annotations = pod.metadata.annotations or {}
+        labels = pod.metadata.labels or {}
+        captured = tuple(
+            (key, labels.get(key, annotations.get(key)))
+            for key in keys
+            if labels.get(key, annotations.get(key)) is not None
+        )
+        if captured:
+            groups[(pod.metadata.namespace, captured)] += 1
+
+    return [
+        {"namespace": namespace, "labels": dict(captured), "pods": count}
+        for (namespace, captured), count in groups.most_common()
+    ]
+
+
+def _cluster_identity(nodes: list[client.V1Node]) -> dict:
+    """Detect cloud provider, region, account/project and managed cluster ID.
+
+    Uses node providerIDs and well-known labels, plus the IRSA role ARN
+    injected into this pod on EKS for the AWS account ID.
+
+    Args:
+        nodes: Cluster nodes
+
+    Returns:
+        Dict with provider, region, account and cluster_id (None when unknown)
+    """
+    identity: dict[str, Optional[str]] = {
+        "provider": None, "region": None, "account": None, "cluster_id": None,
+    }
+
+    for node in nodes:
+        provider_id = node.spec.provider_id or ""
+        labels = node.metadata.labels or {}
+
+        prefix = provider_id.split(":", 1)[0]
+        identity["provider"] = identity["provider"] or PROVIDER_PREFIXES.get(prefix, prefix or None)
+        identity["region"] = identity["region"] or labels.get(
+            "topology.kubernetes.io/region", labels.get("failure-domain.beta.kubernetes.io/region")
+        )
+
+        gce = GCE_PROVIDER_ID_PATTERN.match(provider_id)
+        azure = AZURE_PROVIDER_ID_PATTERN.search(provider_id)
+        identity["account"] = identity["account"] or (
+            gce.group(1) if gce else azure.group(1) if azure else None
+        )
+
+        identity["cluster_id"] = identity["cluster_id"] or next(
+            (labels[label] for label in CLUSTER_NAME_LABELS if labels.get(label)), None
+        )
+
+    if not identity["account"]:
+        # IRSA / EKS Pod Identity expose the role ARN, which embeds the account ID
+        arn = IAM_ROLE_ARN_PATTERN.match(os.environ.get("AWS_ROLE_ARN", ""))
+        identity["account"] = arn.group(1) if arn else None
+
+    return identity
+
+def _node_pool(node: client.V1Node) -> Optional[str]:
+    """Return the node pool / node group a node belongs to, when labeled."""
+    labels = node.metadata.labels or {}
+    return next((labels[label] for label in NODE_POOL_LABELS if labels.get(label)), None)
+
+
+def _node_pools(
+    nodes: list[client.V1Node],
+    pods: list[client.V1Pod],
+    node_usage: Optional[list[dict]],
+    node_stability: list[dict],
+) -> list[dict]:
+    """Aggregate capacity, utilization and instability per node pool.
+
+    Args:
+        nodes: Cluster nodes
+        pods: Pods to count requests and restarts for
+        node_usage: Output of _node_usage(), when metrics-server is available
+        node_stability: Output of collect_node_stability()
+
+    Returns:
+        One entry per pool, largest first, or an empty list when no node
+        carries a known node pool label
+    """
+    pool_by_node = {node.metadata.name: _node_pool(node) for node in nodes}
+    if not any(pool_by_node.values()):
+        return []
+
+    pools: dict[str, dict] = {}
+    for node in nodes:
+        name = pool_by_node[node.metadata.name] or "(none)"
+        pool = pools.setdefault(name, {
+            "pool": name, "nodes": 0, "ready_nodes": 0,
+            "cpu_allocatable": 0.0, "memory_allocatable": 0.0,
+            "cpu_requested": 0.0, "memory_requested": 0.0,
+            "cpu_used": 0.0, "memory_used": 0.0,
+            "restarts": 0, "unstable_nodes": 0,
+        })
+        pool["nodes"] += 1
+        conditions = {c.type: c.status for c in node.status.conditions or []}
+        if conditions.get("Ready") == "True":
+            pool["ready_nodes"] += 1
+        allocatable = node.status.allocatable or {}
+        pool["cpu_allocatable"] += float(parse_quantity(allocatable.get("cpu", "0")))
+        pool["memory_allocatable"] += float(parse_quantity(allocatable.get("memory", "0")))
+
+    def _pool_of(node_name: Optional[str]) -> Optional[dict]:
+        if node_name not in pool_by_node:
+            return None
+        return pools[pool_by_node[node_name] or "(none)"]
+
+    for pod in pods:
+        pool = _pool_of(pod.spec.node_name)
+        if not pool:
+            continue
+        pool["restarts"] += sum(cs.restart_count for cs in pod.status.container_statuses or [])
+        if pod.status.phase in ("Running", "Pending"):
+            cpu, memory = _pod_requests(pod)
+            pool["cpu_requested"] += cpu
+            pool["memory_requested"] += memory
+
+    for usage in node_usage or []:
+        pool = _pool_of(usage["node"])
+        if pool:
+            pool["cpu_used"] += usage["cpu_cores"]
+            pool["memory_used"] += usage["memory_bytes"]
+
+    for stability in node_stability:
+        pool = _pool_of(stability["node"])
+        if pool and stability["stability_score"] < 100:
+            pool["unstable_nodes"] += 1
+
+    summary = []
+    for pool in sorted(pools.values(), key=lambda p: p["nodes"], reverse=True):
+        summary.append({
+            "pool": pool["pool"],
+            "nodes": pool["nodes"],
+            "ready_nodes": pool["ready_nodes"],
+            "cpu_allocatable": round(pool["cpu_allocatable"], 1),
+            "memory_allocatable_gib": round(pool["memory_allocatable"] / 1024 ** 3, 1),
+            "cpu_requested_pct": _percent(pool["cpu_requested"], pool["cpu_allocatable"]),
+            "memory_requested_pct": _percent(pool["memory_requested"], pool["memory_allocatable"]),
+            "cpu_used_pct": _percent(pool["cpu_used"], pool["cpu_allocatable"]) if node_usage else None,
+            "memory_used_pct": _percent(pool["memory_used"], pool["memory_allocatable"]) if node_usage else None,
+            "restarts": pool["restarts"],
+            "unstable_nodes": pool["unstable_nodes"],
+        })
+
+    return summary
+
+
+def _percent(part: float, total: float) -> Optional[float]:
+    """Return part/total as a rounded percentage, or None when total is zero."""
+    if not total:
+        return None
+    return round(part / total * 100, 1)
+
+
+def _node_usage(allocatable_by_node: dict[str, tuple[float, float]]) -> Optional[list[dict]]:
+    """Read current node CPU/memory usage from metrics-server.
+
+    Args:
+        allocatable_by_node: Node name -> (allocatable CPU cores, allocatable memory bytes)
+
+    Returns:
+        Per-node usage list, or None when metrics.k8s.io is not available
+    """
+    try:
+        metrics = client.CustomObjectsApi(get_api_client()).list_cluster_custom_object(
+            "metrics.k8s.io", "v1beta1", "nodes"
+        )
+    except client.ApiException as e:
+        logger.warning("node_metrics_unavailable", status=e.status, source="stats")
+        return None
+
+    usage = []
+    for item in metrics.get("items", []):
+        name = item["metadata"]["name"]
+        cpu = float(parse_quantity(item["usage"].get("cpu", "0")))
+        memory = float(parse_quantity(item["usage"].get("memory", "0")))
+        allocatable_cpu, allocatable_memory = allocatable_by_node.get(name, (0.0, 0.0))
+        usage.append({
+            "node": name,
+            "cpu_cores": round(cpu, 3),
+            "cpu_pct": _percent(cpu, allocatable_cpu),
+            "memory_bytes": int(memory),
+            "memory_pct": _percent(memory, allocatable_memory),
+        })
+
+    return usage
+
+
+def collect_cluster_stats(
+    restarts_this_week: Optional[int] = None,
+    terminations: Optional[list[dict]] = None,
+) -> dict:
+    """Compute headline cluster figures directly from the Kubernetes API.
+
+    These numbers are injected into the prompt and the report header so the
+    key figures never depend on the model's arithmetic.
+
+    Args:
+        restarts_this_week: Container restarts recorded by the pod watcher
+            over the last 7 days, when available
+        terminations: Container terminations recorded by the pod watcher,
+            correlated with warning events
+
+    Returns:
+        Dict of cluster statistics
+    """
+    core_v1 = client.CoreV1Api(get_api_client())
+
+    # Each collector gets a deadline so a hung API or metrics-server cannot block the
+    # snapshot; optional sections that miss it are listed in "missing_sections"
+    deadlines = CollectorDeadlines(
+        settings.collector_timeout_seconds, parse_collector_timeouts(settings.collector_timeouts)
+    )
+
+    # Namespaces and workloads annotated to opt out of the analysis (required: without
+    # them, opted-out workloads would be reported)
+    exclusions = deadlines.run("exclusions", collect_exclusions, required=True)
+    pods = filter_excluded_pods(
+        [
+            pod for pod in deadlines.run("pods", core_v1.list_pod_for_all_namespaces, required=True).items
+            if settings.namespace_in_scope(pod.metadata.namespace)
+        ],
+        exclusions,
+    )
+    nodes = deadlines.run("nodes", core_v1.list_node, required=True).items
+    warning_events = deadlines.run(
+        "events", core_v1.list_event_for_all_namespaces, field_selector="type=Warning", required=True
+    )
+    events = [
+        event for event in warning_events.items
+        if settings.namespace_in_scope(event.metadata.namespace)
+        and not is_excluded(
+            exclusions,
+            event.metadata.namespace,
+            event.involved_object.name if event.involved_object.kind == "Pod" else None,
+        )
+    ]
+    if terminations is not None:
+        terminations = [t for t in terminations if not is_excluded(exclusions, t["namespace"], t["pod"])]
+    node_events = deadlines.run("node_events", list_node_events, default=[])
+
+    phases = Counter(pod.status.phase or "Unknown" for pod in pods)
+    total_restarts = sum(
+        cs.restart_count
+        for pod in pods
+        for cs in pod.status.container_statuses or []
+    )
+
+    warnings_by_namespace: Counter = Counter()
+    for event in events:
+        warnings_by_namespace[event.metadata.namespace] += event.count or 1
+
+    # Weekly restarts from the pod watcher when recorded, lifetime counters otherwise
+    restarts_by_namespace: Counter = Counter()
+    if terminations is not None:
+        for termination in terminations:
+            restarts_by_namespace[termination["namespace"]] += 1
+    else:
+        for pod in pods:
+            restarts_by_namespace[pod.metadata.namespace] += sum(
+                cs.restart_count for cs in pod.status.container_statuses or []
+            )
+    threshold_rules = parse_thresholds(settings.namespace_thresholds)
+
+    ready_nodes = 0
+    allocatable_cpu = 0.0
+    allocatable_memory = 0.0
+    allocatable_by_node: dict[str, tuple[float, float]] = {}
+    for node in nodes:
+        conditions = {c.type: c.status for c in node.status.conditions or []}
+        if conditions.get("Ready") == "True":
+            ready_nodes += 1
+        allocatable = node.status.allocatable or {}
+        node_cpu = float(parse_quantity(allocatable.get("cpu", "0")))
+        node_memory = float(parse_quantity(allocatable.get("memory", "0")))
+        allocatable_by_node[node.metadata.name] = (node_cpu, node_memory)
+        allocatable_cpu += node_cpu
+        allocatable_memory += node_memory
+
+    # Actual utilization; requests alone say nothing about real node load
+    node_usage = deadlines.run("node_usage", _node_usage, allocatable_by_node)
+
+    requested_cpu = 0.0
+    requested_memory = 0.0
+    for pod in pods:
+        if pod.status.phase in ("Running", "Pending"):
+            cpu, memory = _pod_requests(pod)
+            requested_cpu += cpu
+            requested_memory += memory
+
+    node_stability = collect_node_stability(nodes, node_events)
+    pod_placement = collect_pod_placement(pods)
+
+    stats = {
+        "total_pods": len(pods),
+        "running_pods": phases.get("Running", 0),
+        "running_pct": _percent(phases.get("Running", 0), len(pods)),
+        "pods_by_phase": dict(phases),
+        "total_restarts": total_restarts,
+        "restarts_this_week": restarts_this_week,
+        "total_nodes": len(nodes),
+        "ready_nodes": ready_nodes,
+        "top_warning_namespaces": [
+            {"namespace": namespace, "warnings": count}
+            for namespace, count in warnings_by_namespace.most_common(5)
+        ],
+        # System, batch, stateful and stateless workloads apart, so Job churn does not hide service regressions
+        "workload_classes": summarize_workload_classes(pods, events, terminations),
+        # Deterministic severity per event reason, so prioritization does not rest on the LLM
+        "event_severities": summarize_event_severities(
+            events + [e for e in node_events if e.type != "Warning"],
+            parse_severity_overrides(settings.event_severity_overrides),
+        ),
+        "cpu_requested_pct": _percent(requested_cpu, allocatable_cpu),
+        "memory_requested_pct": _percent(requested_memory, allocatable_memory),
+        "cpu_used_pct": (
+            _percent(sum(n["cpu_cores"] for n in node_usage), allocatable_cpu)
+            if node_usage else None
+        ),
+        "memory_used_pct": (
+            _percent(sum(n["memory_bytes"] for n in node_usage), allocatable_memory)
+            if node_usage else None
+        ),
+        "node_usage": node_usage,
+        "resource_coverage": _resource_coverage(pods),
+        "pod_labels": _pod_label_groups(pods, settings.pod_label_keys),
+        "cluster_identity": _cluster_identity(nodes),
+        "node_stability": node_stability,
+        "node_pools": _node_pools(nodes, pods, node_usage, node_stability),
+        # Node each workload runs on at snapshot time, to connect node incidents to workloads
+        "pod_placement": pod_placement,
+        "node_incidents": correlate_node_incidents(nodes, node_stability, pod_placement),
+        # Derived facts: restarts shortly after a warning event on the pod or its node
+        "restart_correlations": correlate_restarts_with_events(
+            pods,
+            # NodeNotReady is a Normal event, so it is not among the warnings
+            events + [e for e in node_events if e.reason == "NodeNotReady"],
+            terminations,
+        ),
+        # OOM vs segfault vs application errors, from container exit codes
+        "exit_codes": summarize_exit_codes(pods, terminations),
+        "control_plane": deadlines.run("control_plane", collect_control_plane_health),
+        # Admission webhooks that silently block creates and updates
+        "webhook_failures": deadlines.run("webhook_failures", collect_webhook_failures, events),
+        # Image pull secrets and service account tokens likely to break pulls or leak
+        "credential_risks": (
+            deadlines.run("credential_risks", collect_credential_risks, pods, events, exclusions)
+            if settings.credential_checks_enabled else None
+        ),
+        "addon_inventory": deadlines.run("addon_inventory", collect_addon_inventory),
+        "exclusions": exclusions,
+        # What changed this week, to correlate instability with rollouts
+        "recent_rollouts": deadlines.run("recent_rollouts", collect_recent_rollouts, exclusions=exclusions),
+        # Per-namespace tolerances configured by the operators
+        "namespace_thresholds": (
+            evaluate_thresholds(threshold_rules, restarts_by_namespace, warnings_by_namespace)
+            if threshold_rules else None
+        ),
+        # Kept apart from the application figures above
+        "system_components": (
+            deadlines.run("system_components", collect_system_components)
+            if settings.system_components_enabled else None
+        ),
+        # Rows of the CSV appendices (restarts, right-sizing, events); not sent to the model
+        "appendix": deadlines.run("appendix", collect_appendix_tables, pods, events, terminations),
+    }
+    # Sections whose collector missed its deadline: absent data, not healthy data
+    stats["missing_sections"] = deadlines.missing
+
+    if deadlines.missing:
+        logger.warning(
+            "cluster_stats_partial",
+            missing_sections=[m["section"] for m in deadlines.missing],
+            source="stats",
+        )
+
+    logger.info(
+        "cluster_stats_collected",
+        total_pods=stats["total_pods"],
+        total_nodes=stats["total_nodes"],
+        source="stats",
+    )
+
+    return stats
+
+
+def format_stats_for_prompt(stats: dict) -> str:
+    """Render statistics as a plain-text block for the user prompt.
+
+    Args:
+        stats: Output of collect_cluster_stats()
+
+    Returns:
+        Prompt section text
+    """
+    identity = stats.get("cluster_identity") or {}
+    lines = []
+    if identity.get("provider"):
+        lines.append(
+            "- Cloud: "
+            + ", ".join(
+                f"{key}={value}" for key, value in identity.items()
+                if value and not (key == "account" and settings.privacy_mode)
+            )
+        )
+    if stats.get("missing_sections"):
+        lines.append(
+            "- INCOMPLETE SNAPSHOT, these collectors timed out (their data is missing, not healthy): "
+            + ", ".join(f"{m['section']} ({m['reason']})" for m in stats["missing_sections"])
+        )
+    lines += [
+        f"- Pods: {stats['running_pods']}/{stats['total_pods']} running ({stats['running_pct']}%)",
+        f"- Pods by phase: {', '.join(f'{k}={v}' for k, v in stats['pods_by_phase'].items())}",
+        f"- Nodes: {stats['ready_nodes']}/{stats['total_nodes']} ready",
+        f"- Container restarts (lifetime counters): {stats['total_restarts']}",
+    ]
+    if stats.get("restarts_this_week") is not None:
+        lines.append(f"- Container restarts in the last 7 days: {stats['restarts_this_week']}")
+    if stats.get("workload_classes"):
+        lines.append("- Health by workload class (structure the issues by class):")
+        for c in stats["workload_classes"]:
+            top = "" if settings.privacy_mode or not c["top_restarts"] else ", most restarts: " + ", ".join(
+                f"{w['workload']} ({w['restarts']})" for w in c["top_restarts"]
+            )
+            lines.append(
+                f"  - {c['label']}: {c['workloads']} workloads, {c['pods']} pods, {c['not_ready']} not ready, "
+                f"{c['failed']} failed, {c['restarts']} restarts, {c['warnings']} warning events{top}"
+            )
+    if stats.get("cpu_requested_pct") is not None:
+        lines.append(f"- CPU requested vs allocatable: {stats['cpu_requested_pct']}%")
+    if stats.get("memory_requested_pct") is not None:
+        lines.append(f"- Memory requested vs allocatable: {stats['memory_requested_pct']}%")
+    if stats.get("cpu_used_pct") is not None:
+        lines.append(f"- CPU in use vs allocatable (metrics-server): {stats['cpu_used_pct']}%")
+    if stats.get("memory_used_pct") is not None:
+        lines.append(f"- Memory in use vs allocatable (metrics-server): {stats['memory_used_pct']}%")
+    if stats.get("node_usage") and not settings.privacy_mode:
+        busiest = sorted(
+            stats["node_usage"],
+            key=lambda n: max(n["cpu_pct"] or 0, n["memory_pct"] or 0),
+            reverse=True,
+        )[:5]
+        top = ", ".join(
+            f"{n['node']} (cpu {n['cpu_pct']}%, memory {n['memory_pct']}%)" for n in busiest
+        )
+        lines.append(f"- Busiest nodes by current usage: {top}")
+    if stats.get("resource_coverage"):
+        containers = sum(c["containers"] for c in stats["resource_coverage"])
+        missing_requests = sum(c["missing_requests"] for c in stats["resource_coverage"])
+        missing_limits = sum(c["missing_limits"] for c in stats["resource_coverage"])
+        lines.append(
+            f"- Containers missing requests: {_percent(missing_requests, containers)}%, "
+            f"missing limits: {_percent(missing_limits, containers)}% (of {containers})"
+        )
+    if stats.get("node_pools"):
+        lines.append("- Node pools (size recommendations per pool, these are the knobs operators control):")
+        for p in stats["node_pools"]:
+            usage = (
+                f", cpu used {p['cpu_used_pct']}%, memory used {p['memory_used_pct']}%"
+                if p["cpu_used_pct"] is not None else ""
+            )
+            lines.append(
+                f"  - {p['pool']}: {p['ready_nodes']}/{p['nodes']} nodes ready, "
+                f"{p['cpu_allocatable']} cores / {p['memory_allocatable_gib']} GiB allocatable, "
+                f"cpu requested {p['cpu_requested_pct']}%, memory requested {p['memory_requested_pct']}%"
+                f"{usage}, {p['restarts']} restarts, {p['unstable_nodes']} unstable nodes"
+            )
+    node_incidents = stats.get("node_incidents") or []
+    if node_incidents and settings.privacy_mode:
+        lines.append(
+            f"- Nodes with incidents: {len(node_incidents)}, hosting "
+            f"{sum(i['pods'] for i in node_incidents)} pods"
+        )
+    elif node_incidents:
+        lines.append("- Node incidents and the workloads running on those nodes (attribute their symptoms to the node):")
+        for i in node_incidents[:10]:
+            workloads = ", ".join(
+                f"{w['workload']} ({w['pods']} pods" + (f", {w['not_ready']} not ready)" if w["not_ready"] else ")")
+                for w in i["workloads"][:8]
+            )
+            lines.append(
+                f"  - {i['node']}: {', '.join(i['conditions'] + [f'{x} this week' for x in i['this_week']])}; "
+                f"{i['pods']} pods"
+                + (f": {workloads}" if workloads else "")
+            )
+    unstable = [n for n in stats.get("node_stability") or [] if n["stability_score"] < 100]
+    if unstable:
+        if settings.privacy_mode:
+            lines.append(f"- Nodes with reboots, kernel panics or kubelet restarts this week: {len(unstable)}")
+        else:
+            worst = ", ".join(
+                f"{n['node']} (score {n['stability_score']}: {n['reboots']} reboots, "
+                f"{n['kernel_panics']} kernel panics, {n['kubelet_restarts']} kubelet restarts)"
+                for n in unstable[:5]
+            )
+            lines.append(f"- Least stable nodes this week: {worst}")
+    if stats.get("restart_correlations"):
+        correlations = stats["restart_correlations"]
+        if settings.privacy_mode:
+            lines.append(f"- Restarts shortly after a warning event on the pod or its node: {len(correlations)}")
+        else:
+            lines.append("- Causal hints (restarts shortly after a warning event on the pod or its node):")
+            for c in correlations[:10]:
+                lines.append(
+                    f"  - {c['namespace']}/{c['pod']} ({c['container']}) restarted {c['minutes_after']}m "
+                    f"after {c['event_reason']} on {c['event_object']} at {c['restarted_at']}"
+                )
+    if stats.get("pod_labels") and not settings.privacy_mode:
+        by_key: dict[str, Counter] = {}
+        for group in stats["pod_labels"]:
+            for key, value in group["labels"].items():
+                by_key.setdefault(key, Counter())[value] += group["pods"]
+        for key, values in by_key.items():
+            top = ", ".join(f"{value} ({count} pods)" for value, count in values.most_common(10))
+            lines.append(f"- Pods by {key}: {top}")
+    exit_codes = stats.get("exit_codes") or {}
+    if exit_codes.get("total"):
+        lines.append(
+            f"- Container terminations by failure class ({exit_codes['total']}, "
+            + ("recorded by the pod watcher this week" if exit_codes["source"] == "watcher"
+               else "last termination of each restarted container")
+            + "):"
+        )
+        for c in exit_codes["classes"]:
+            workloads = "" if settings.privacy_mode else ": " + ", ".join(
+                f"{w['workload']} ({w['count']})" for w in c["workloads"]
+            )
+            lines.append(f"  - {c['label']}: {c['count']} (likely {c['hint']}){workloads}")
+    control_plane = stats.get("control_plane") or {}
+    failing = [
+        f"/{endpoint} {', '.join(control_plane[endpoint]['failed'])}"
+        for endpoint in ("readyz", "livez")
+        if control_plane.get(endpoint) and not control_plane[endpoint]["ok"]
+    ] + [f"{c['component']} unhealthy" for c in control_plane.get("unhealthy_components") or []]
+    if control_plane:
+        lines.append(
+            "- Control plane: "
+            + (f"FAILING ({'; '.join(failing)})" if failing else "no failing health checks")
+            + (
+                f", API server p99 latency {control_plane['apiserver_p99_latency_seconds']}s"
+                if control_plane.get("apiserver_p99_latency_seconds") is not None else ""
+            )
+            + (
+                f", API server 5xx rate {control_plane['apiserver_error_rate_pct']}%"
+                if control_plane.get("apiserver_error_rate_pct") is not None else ""
+            )
+            + (
+                f", etcd p99 latency {control_plane['etcd_p99_request_seconds']}s"
+                if control_plane.get("etcd_p99_request_seconds") is not None else ""
+            )
+        )
+    rollouts = stats.get("recent_rollouts") or []
+    if rollouts and settings.privacy_mode:
+        lines.append(f"- Deployment rollouts in the last 7 days: {len(rollouts)}")
+    elif rollouts:
+        lines.append(f"- Deployment rollouts in the last 7 days ({len(rollouts)}, newest first):")
+        for r in rollouts[:20]:
+            change = f", change-cause: {r['change_cause']}" if r["change_cause"] else ""
+            images = (
+                f", {', '.join(r['previous_images'])} -> {', '.join(r['images'])}" if r["previous_images"] else ""
+            )
+            lines.append(
+                f"  - {r['namespace']}/{r['deployment']} revision {r['revision']} at {r['rolled_out_at']}"
+                f"{change}{images}"
+            )
+    webhook_failures = stats.get("webhook_failures") or []
+    if webhook_failures and settings.privacy_mode:
+        lines.append(
+            f"- Broken admission webhooks: {len(webhook_failures)} "
+            f"({sum(w['failed_calls'] for w in webhook_failures)} failed calls, "
+            f"{sum(1 for w in webhook_failures if w['ready_endpoints'] == 0)} without ready endpoints, "
+            f"{sum(1 for w in webhook_failures if w['failure_policy'] == 'Fail')} with failurePolicy Fail)"
+        )
+    elif webhook_failures:
+        for w in webhook_failures:
+            lines.append(
+                f"- Broken admission webhook {w['webhook']} ({w['kind'] or 'unknown'} webhook, "
+                f"failurePolicy {w['failure_policy'] or 'unknown'}): {w['failed_calls']} failed calls ({w['cause']})"
+                + (f", no ready endpoints behind {w['service']}" if w["ready_endpoints"] == 0 else "")
+                + (f", affected namespaces: {', '.join(w['namespaces'][:10])}" if w["namespaces"] else "")
+            )
+    credentials = stats.get("credential_risks") or {}
+    pull_secrets, sa_tokens = credentials.get("pull_secrets") or [], credentials.get("service_account_tokens") or []
+    if (pull_secrets or sa_tokens) and settings.privacy_mode:
+        lines.append(
+            f"- Credential risks: {len(pull_secrets)} image pull secrets, "
+            f"{len(sa_tokens)} long-lived service account tokens"
+        )
+    elif pull_secrets or sa_tokens:
+        for p in pull_secrets[:15]:
+            lines.append(
+                f"- Image pull secret {p['namespace']}/{p['secret']}: {p['status']}"
+                + (f", expires {p['expires_at']}" if p["expires_at"] else "")
+                + (f", {p['auth_failures']} auth failures" if p["auth_failures"] else "")
+                + f", used by {p['pods']} pods"
+                + (f" ({', '.join(p['registries'])})" if p["registries"] else "")
+            )
+        if sa_tokens:
+            statuses = Counter(t["status"] for t in sa_tokens)
+            lines.append(
+                f"- Long-lived service account token secrets: {len(sa_tokens)} ("
+                + ", ".join(f"{status} {count}" for status, count in statuses.items())
+                + ")"
+            )
+            for t in [t for t in sa_tokens if t["status"] != "long_lived"][:10]:
+                lines.append(
+                    f"  - {t['namespace']}/{t['secret']} (service account {t['service_account'] or '?'}): "
+                    f"{t['status']}, {t['age_days']} days old, last used {t['last_used'] or 'unknown'}"
+                )
+    stale_addons = [a for a in stats.get("addon_inventory") or [] if a["status"] in ("eol", "outdated")]
+    if stats.get("addon_inventory"):
+        lines.append(
+            "- Add-ons: "
+            + ", ".join(f"{a['addon']} {a['version'] or '?'}" for a in stats["addon_inventory"][:15])
+        )
+    for a in stale_addons[:10]:
+        lines.append(f"  - {a['addon']} {a['version'] or '?'} is {a['status'].upper()}: {a['note']}")
+    if stats.get("watched_workloads") and settings.privacy_mode:
+        lines.append(f"- Watched workloads (cover every one of them in the report): {len(stats['watched_workloads'])}")
+    elif stats.get("watched_workloads"):
+        lines.append(
+            "- Watched workloads (cover EVERY one of them in the report, even when healthy; "
+            "sampled every few minutes over the last 7 days):"
+        )
+        for w in stats["watched_workloads"]:
+            lines.append(
+                f"  - {w['namespace']}/{w['name']} ({w['kind']}): {w['ready']}/{w['desired']} ready now, "
+                f"{w['restarts']} restarts, not fully ready in {w['unavailable_samples']}/{w['samples']} samples"
+            )
+    if stats.get("endpoint_probes"):
+        lines.append("- Endpoint probes (active HTTP checks from the watchdog at each snapshot):")
+        for p in stats["endpoint_probes"]:
+            target = p["name"] if settings.privacy_mode else f"{p['name']} ({p['url']})"
+            now = f"UP, {p['latency_ms']}ms" if p["up"] else f"DOWN ({p['error']})"
+            week = (
+                f"; this week {p['success_pct']}% of {p['probes_this_week']} probes succeeded"
+                + (f", p50 {p['p50_latency_ms']}ms, p95 {p['p95_latency_ms']}ms" if p["p50_latency_ms"] is not None else "")
+            ) if p["probes_this_week"] else ""
+            lines.append(f"  - {target}: {now}{week}")
+    if stats.get("system_components"):
+        unhealthy = [c for c in stats["system_components"] if not c["healthy"]]
+        lines.append(
+            f"- System components (reported in their own section, not application issues): "
+            f"{len(stats['system_components']) - len(unhealthy)}/{len(stats['system_components'])} healthy"
+        )
+        for c in unhealthy[:10]:
+            lines.append(
+                f"  - {c['namespace']}/{c['name']} ({c['kind']}, {c['category']}): "
+                f"{c['ready']}/{c['desired']} ready, {c['restarts']} restarts"
+            )
+    exclusions = stats.get("exclusions") or {}
+    if exclusions.get("namespaces") or exclusions.get("workloads"):
+        if settings.privacy_mode:
+            lines.append(
+                f"- Opted out of the analysis (do not report on them): {len(exclusions['namespaces'])} namespaces, "
+                f"{len(exclusions['workloads'])} workloads"
+            )
+        else:
+            opted_out = [f"namespace {ns}" for ns in exclusions["namespaces"]] + [
+                f"{w['namespace']}/{w['name']} ({w['kind']})" for w in exclusions["workloads"]
+            ]
+            lines.append(f"- Opted out of the analysis (do not report on them): {', '.join(opted_out)}")
+    breached = [t for t in stats.get("namespace_thresholds") or [] if t["breached"]]
+    if stats.get("namespace_thresholds"):
+        lines.append(
+            f"- Namespace severity thresholds: {len(breached)} breached"
+            + (":" if breached else " (namespaces within their tolerance are not findings)")
+        )
+    for t in breached[:10]:
+        lines.append(f"  - {t['namespace']}: {t['value']} {t['metric']} (tolerated: {t['threshold']})")
+    if stats["top_warning_namespaces"]:
+        top = ", ".join(
+            f"{item['namespace']} ({item['warnings']})" for item in stats["top_warning_namespaces"]
+        )
+        lines.append(f"- Top namespaces by warning events: {top}")
+    severities = stats.get("event_severities")
+    if severities and any(severities["counts"].values()):
+        counts = severities["counts"]
+        lines.append(
+            f"- Events by severity (fixed reason mapping, prioritize critical ones): critical {counts['critical']}, "
+            f"warning {counts['warning']}, info {counts['info']}"
+        )
+        for r in [r for r in severities["reasons"] if r["severity"] != "info"][:10]:
+            lines.append(
+                f"  - {r['reason']} ({r['severity']}): {r['events']} events"
+                + (f" in {', '.join(r['namespaces'][:5])}" if r["namespaces"] else "")
+            )
+
+    return "\n".join(lines)
@@ -0,0 +1,115 @@
+from typing import Optional
+
+import httpx
+import structlog
+from kubernetes import client
+
+from src.config import settings
+from src.kube import get_api_client
+
+logger = structlog.get_logger()
+
+# PromQL for API server latency and errors, when Prometheus scrapes the API server
+APISERVER_QUERIES = {
+    "apiserver_p99_latency_seconds": (
+        'histogram_quantile(0.99, sum(rate(apiserver_request_duration_seconds_bucket'
+        '{verb!~"WATCH|CONNECT"}[1h])) by (le))'
+    ),
+    "apiserver_error_rate_pct": (
+        'sum(rate(apiserver_request_total{code=~"5.."}[1h])) '
+        '/ sum(rate(apiserver_request_total[1h])) * 100'
+    ),
+    "etcd_p99_request_seconds": (
+        "histogram_quantile(0.99, sum(rate(etcd_request_duration_seconds_bucket[1h])) by (le))"
+    ),
+}
+
+
+def _health_checks(api_client: client.ApiClient, path: str) -> Optional[dict]:
+    """Read a verbose /readyz or /livez endpoint.
+
+    Returns:
+        {"ok": bool, "failed": [check names]}, or None when the endpoint is not accessible
+    """
+    try:
+        response = api_client.call_api(
+            path, "GET", query_params=[("verbose", "true")],
+            response_type="str", auth_settings=["BearerToken"], _preload_content=False,
+        )[0]
+        body = response.data.decode("utf-8", errors="replace")
+    except client.ApiException as e:
+        # A failing check returns 500 with the same verbose body
+        if e.status != 500 or not e.body:
+            logger.warning("control_plane_endpoint_unavailable", path=path, status=e.status, source="stats")
+            return None
+        body = e.body if isinstance(e.body, str) else e.body.decode("utf-8", errors="replace")
+
+    failed = [line[3:].split(" ")[0] for line in body.splitlines() if line.startswith("[-]")]
+    return {"ok": not failed, "failed": failed}
+
+
+def _component_statuses() -> list[dict]:
+    """Read the deprecated componentstatuses API (scheduler, controller manager, etcd).
+
+    Returns:
+        Unhealthy components only; empty when all are healthy or the API is gone
+    """
+    try:
+        items = client.CoreV1Api(get_api_client()).list_component_status().items
+    except client.ApiException as e:
+        logger.warning("component_statuses_unavailable", status=e.status, source="stats")
+        return []
+
+    unhealthy = []
+    for item in items:
+        for condition in item.conditions or []:
+            if condition.type == "Healthy" and condition.status != "True":
+                unhealthy.append({"component": item.metadata.name, "message": condition.message or condition.error})
+    return unhealthy
+
+
+def _prometheus_value(query: str) -> Optional[float]:
+    """Run an instant PromQL query and return its single value, if any."""
+    response = httpx.get(
+        f"{settings.prometheus_url}/api/v1/query", params={"query": query}, timeout=10.0
+    )
+    response.raise_for_status()
+    result = response.json().get("data", {}).get("result", [])
+    if not result:
+        return None
+    value = float(result[0]["value"][1])
+    return None if value != value else round(value, 3)  # NaN when there was no traffic
+
+
+def collect_control_plane_health() -> dict:
+    """Collect API server and etcd health indicators, where accessible.
+
+    Managed control planes often hide etcd and the componentstatuses API;
+    every indicator is optional and None means "not observable".
+
+    Returns:
+        Dict with readyz/livez checks, unhealthy components and, when
+        Prometheus scrapes the API server, latency and error rate figures
+    """
+    api_client = get_api_client()
+    health = {
+        "readyz": _health_checks(api_client, "/readyz"),
+        "livez": _health_checks(api_client, "/livez"),
+        "unhealthy_components": _component_statuses(),
+    }
+
+    for key, query in APISERVER_QUERIES.items():
+        try:
+            health[key] = _prometheus_value(query)
+        except (httpx.HTTPError, KeyError, ValueError) as e:
+            logger.debug("control_plane_metric_unavailable", metric=key, error=str(e), source="stats")
+            health[key] = None
+
+    logger.info(
+        "control_plane_health_collected",
+        readyz_ok=(health["readyz"] or {}).get("ok"),
+        unhealthy_components=len(health["unhealthy_components"]),
+        source="stats",
+    )
+
+    return health
@@ -0,0 +1,114 @@
+from datetime import datetime, timedelta, timezone
+from typing import Optional
+
+from kubernetes import client
+
+# Warning event reasons that commonly precede a container restart
+CAUSAL_EVENT_REASONS = {
+    "FailedMount", "FailedAttachVolume", "Unhealthy", "BackOff", "NodeNotReady",
+    "Evicted", "OOMKilling", "SystemOOM", "FailedCreatePodSandBox", "NetworkNotReady",
+}
+
+# A restart this long after the event is still attributed to it
+CORRELATION_WINDOW = timedelta(minutes=30)
+
+MAX_CORRELATIONS = 50
+
+
+def _as_utc(value) -> Optional[datetime]:
+    """Normalize API timestamps (aware) and watcher timestamps (naive local ISO) to UTC."""
+    if value is None:
+        return None
+    if isinstance(value, str):
+        value = datetime.fromisoformat(value)
+    if value.tzinfo is None:
+        value = value.astimezone()
+    return value.astimezone(timezone.utc)
+
+
+def _restart_times(
+    pods: list[client.V1Pod], terminations: list[dict]
+) -> dict[tuple[str, str], list[tuple[str, datetime]]]:
+    """Collect (container, time) restart instants per pod from the API and the pod watcher."""
+    restarts: dict[tuple[str, str], set[tuple[str, datetime]]] = {}
+    for pod in pods:
+        for cs in pod.status.container_statuses or []:
+            terminated = cs.last_state.terminated if cs.last_state else None
+            if cs.restart_count and terminated and terminated.finished_at:
+                restarts.setdefault((pod.metadata.namespace, pod.metadata.name), set()).add(
+                    (cs.name, _as_utc(terminated.finished_at).replace(microsecond=0))
+                )
+    for row in terminations:
+        restarts.setdefault((row["namespace"], row["pod"]), set()).add(
+            (row.get("container"), _as_utc(row["observed_at"]).replace(microsecond=0))
+        )
+    return {key: sorted(values, key=lambda v: v[1]) for key, values in restarts.items()}
+
+
+def correlate_restarts_with_events(
+    pods: list[client.V1Pod],
+    events: list[client.CoreV1Event],
+    terminations: Optional[list[dict]] = None,
+) -> list[dict]:
+    """Pair container restarts with warning events that happened shortly before them.
+
+    An event about the pod itself, or about the node it runs on, is paired
+    with the pod's restarts between the event's first occurrence and
+    CORRELATION_WINDOW after its last one.
+
+    Args:
+        pods: Pods of the cluster
+        events: Warning events (pod and node events)
+        terminations: Container terminations recorded by the pod watcher
+
+    Returns:
+        Correlated pairs (namespace, pod, container, restarted_at, event_reason,
+        event_object, minutes_after), most recent first
+    """
+    restarts = _restart_times(pods, terminations or [])
+    pods_by_node: dict[str, list[tuple[str, str]]] = {}
+    for pod in pods:
+        if pod.spec.node_name:
+            pods_by_node.setdefault(pod.spec.node_name, []).append((pod.metadata.namespace, pod.metadata.name))
+
+    correlations = []
+    seen = set()
+    for event in events:
+        if event.reason not in CAUSAL_EVENT_REASONS:
+            continue
+        involved = event.involved_object
+        if involved.kind == "Pod":
+            targets = [(involved.namespace or event.metadata.namespace, involved.name)]
+        elif involved.kind == "Node":
+            targets = pods_by_node.get(involved.name, [])
+        else:
+            continue
+
+        first = _as_utc(event.first_timestamp or event.event_time or event.metadata.creation_timestamp)
+        last = _as_utc(event.last_timestamp) or first
+        if not first:
+            continue
+
+        for namespace, pod in targets:
+            for container, restarted_at in restarts.get((namespace, pod), []):
+                if not first <= restarted_at <= last + CORRELATION_WINDOW:
+                    continue
+                key = (namespace, pod, container, restarted_at, event.reason)
+                if key in seen:
+                    continue
+                seen.add(key)
+                correlations.append({
+                    "namespace": namespace,
+                    "pod": pod,
+                    "container": container,
+                    "restarted_at": restarted_at.isoformat(),
+                    "event_reason": event.reason,
+                    "event_object": f"{involved.kind}/{involved.name}",
+                    "minutes_after": round(
+                        (restarted_at - (last if restarted_at > last else first)).total_seconds() / 60
+                    ),
+                })
+
+    correlations.sort(key=lambda c: c["restarted_at"], reverse=True)
+
+    return correlations[:MAX_CORRELATIONS]
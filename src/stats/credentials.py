@@ -0,0 +1,296 @@
+import base64
+import json
+import re
+from datetime import datetime, timedelta, timezone
+from typing import Optional
+
+import structlog
+from kubernetes import client
+
+from src.config import settings
+from src.kube import get_api_client
+
+logger = structlog.get_logger()
+
+PULL_SECRET_TYPES = {"kubernetes.io/dockerconfigjson", "kubernetes.io/dockercfg"}
+SA_TOKEN_TYPE = "kubernetes.io/service-account-token"
+
+# Set by the API server (1.29+) on legacy service account token secrets
+LEGACY_TOKEN_LAST_USED_LABEL = "kubernetes.io/legacy-token-last-used"
+LEGACY_TOKEN_INVALID_SINCE_LABEL = "kubernetes.io/legacy-token-invalid-since"
+
+# Registries handing out short-lived tokens: a pull secret holding one must be
+# refreshed by a job, otherwise pulls start failing once the token expires
+SHORT_LIVED_REGISTRIES = [
+    (re.compile(r"\.dkr\.ecr\.[a-z0-9-]+\.amazonaws\.com"), timedelta(hours=12)),
+]
+SHORT_LIVED_USERS = {"oauth2accesstoken": timedelta(hours=1)}  # GCR / Artifact Registry
+
+# Image pull failure message fragments that point at credentials
+AUTH_FAILURE_FRAGMENTS = (
+    "unauthorized",
+    "authentication required",
+    "no basic auth credentials",
+    "pull access denied",
+    "401",
+    "403 forbidden",
+)
+
+EXPIRING_WITHIN = timedelta(days=7)
+UNUSED_TOKEN_DAYS = 90
+
+# Display order, most urgent first
+PULL_SECRET_STATUS_ORDER = {"missing": 0, "expired": 1, "auth_failures": 2, "expiring": 3, "unused": 4}
+SA_TOKEN_STATUS_ORDER = {"orphaned": 0, "invalidated": 1, "unused": 2, "long_lived": 3}
+
+
+def _b64_json(segment: str) -> Optional[dict]:
+    """Decode an unpadded base64url JSON segment, None when it is not one."""
+    try:
+        return json.loads(base64.urlsafe_b64decode(segment + "=" * (-len(segment) % 4)))
+    except (ValueError, UnicodeDecodeError):
+        return None
+
+
+def _jwt_expiry(token: str) -> Optional[datetime]:
+    """Return the exp claim of a JWT, None when the value is not a JWT."""
+    parts = token.split(".")
+    if len(parts) != 3 or not isinstance(_b64_json(parts[0]), dict):
+        return None
+    payload = _b64_json(parts[1])
+    if not isinstance(payload, dict) or not isinstance(payload.get("exp"), (int, float)):
+        return None
+    return datetime.fromtimestamp(payload["exp"], tz=timezone.utc)
+
+
+def _last_updated(secret: client.V1Secret) -> datetime:
+    """Return when a secret was last written (managed fields), or created."""
+    times = [entry.time for entry in secret.metadata.managed_fields or [] if entry.time]
+    return max(times + [secret.metadata.creation_timestamp])
+
+
+def _registry_credentials(secret: client.V1Secret) -> dict[str, tuple[str, str]]:
+    """Map each registry of a pull secret to its (username, password).
+
+    Only the expiry of the credentials is derived from them; they are never
+    logged, stored or sent to the LLM.
+    """
+    data = secret.data or {}
+    raw = data.get(".dockerconfigjson") or data.get(".dockercfg")
+    if not raw:
+        return {}
+    try:
+        config = json.loads(base64.b64decode(raw))
+    except (ValueError, UnicodeDecodeError):
+        return {}
+    auths = config.get("auths", config) if isinstance(config, dict) else {}
+
+    credentials = {}
+    for registry, entry in auths.items():
+        if not isinstance(entry, dict):
+            continue
+        username, password = entry.get("username") or "", entry.get("password") or ""
+        if not password and entry.get("auth"):
+            try:
+                username, _, password = base64.b64decode(entry["auth"]).decode().partition(":")
+            except (ValueError, UnicodeDecodeError):
+                continue
+        credentials[registry] = (username, password)
+    return credentials
+
+
+def _credential_expiry(secret: client.V1Secret) -> tuple[Optional[datetime], bool]:
+    """Estimate when the earliest credential of a pull secret expires.
+
+    Uses the exp claim of JWT passwords, and the fixed token lifetime of
+    registries with short-lived tokens (ECR, GCR access tokens) counted from
+    the last write of the secret.
+
+    Returns:
+        Earliest expiry (None when unknown), and whether it comes from a
+        short-lived token that is expected to be refreshed continuously
+    """
+    expiries = []
+    for registry, (username, password) in _registry_credentials(secret).items():
+        expiry = _jwt_expiry(password)
+        if expiry is not None:
+            expiries.append((expiry, False))
+            continue
+        lifetime = SHORT_LIVED_USERS.get(username) or next(
+            (ttl for pattern, ttl in SHORT_LIVED_REGISTRIES if pattern.search(registry)), None
+        )
+        if lifetime:
+            expiries.append((_last_updated(secret) + lifetime, True))
+    return min(expiries) if expiries else (None, False)
+
+
+def _auth_failures_by_pod(events: list) -> dict[tuple[str, str], int]:
+    """Count image pull failures caused by credentials per (namespace, pod)."""
+    failures: dict[tuple[str, str], int] = {}
+    for event in events:
+        if event.involved_object.kind != "Pod" or event.reason not in ("Failed", "ErrImagePull"):
+            continue
+        message = (event.message or "").lower()
+        if "pull" not in message or not any(fragment in message for fragment in AUTH_FAILURE_FRAGMENTS):
+            continue
+        key = (event.metadata.namespace, event.involved_object.name)
+        failures[key] = failures.get(key, 0) + (event.count or 1)
+    return failures
+
+
+def _pull_secret_risks(
+    secrets: list[client.V1Secret],
+    service_accounts: list[client.V1ServiceAccount],
+    pods: list[client.V1Pod],
+    events: list,
+    now: datetime,
+) -> list[dict]:
+    """Flag missing, expired, expiring, failing and unused image pull secrets."""
+    pull_secrets = {
+        (s.metadata.namespace, s.metadata.name): s for s in secrets if s.type in PULL_SECRET_TYPES
+    }
+    auth_failures = _auth_failures_by_pod(events)
+
+    pods_by_secret: dict[tuple[str, str], int] = {}
+    failures_by_secret: dict[tuple[str, str], int] = {}
+    for pod in pods:
+        for ref in pod.spec.image_pull_secrets or []:
+            key = (pod.metadata.namespace, ref.name)
+            pods_by_secret[key] = pods_by_secret.get(key, 0) + 1
+            failures = auth_failures.get((pod.metadata.namespace, pod.metadata.name), 0)
+            if failures:
+                failures_by_secret[key] = failures_by_secret.get(key, 0) + failures
+    referenced_by_service_accounts = {
+        (sa.metadata.namespace, ref.name)
+        for sa in service_accounts
+        for ref in sa.image_pull_secrets or []
+    }
+
+    risks = []
+    for key in sorted(set(pull_secrets) | set(pods_by_secret) | referenced_by_service_accounts):
+        namespace, name = key
+        secret = pull_secrets.get(key)
+        pods_using = pods_by_secret.get(key, 0)
+        expires_at, short_lived = _credential_expiry(secret) if secret else (None, False)
+
+        if secret is None:
+            # Absent, or of another type (e.g. Opaque) that the kubelet ignores for pulls
+            status = "missing"
+        elif expires_at and expires_at <= now:
+            status = "expired"
+        elif failures_by_secret.get(key):
+            status = "auth_failures"
+        elif expires_at and not short_lived and expires_at - now <= EXPIRING_WITHIN:
+            status = "expiring"
+        elif not pods_using and key not in referenced_by_service_accounts:
+            status = "unused"
+        else:
+            continue
+
+        risks.append({
+            "namespace": namespace,
+            "secret": name,
+            "status": status,
+            "registries": sorted(_registry_credentials(secret)) if secret else [],
+            "expires_at": expires_at.isoformat() if expires_at else None,
+            "last_updated": _last_updated(secret).isoformat() if secret else None,
+            "pods": pods_using,
+            "auth_failures": failures_by_secret.get(key, 0),
+        })
+
+    risks.sort(key=lambda r: (PULL_SECRET_STATUS_ORDER[r["status"]], -r["pods"], r["namespace"], r["secret"]))
+    return risks
+
+
+def _service_account_token_risks(
+    secrets: list[client.V1Secret],
+    service_accounts: list[client.V1ServiceAccount],
+    now: datetime,
+) -> list[dict]:
+    """List long-lived (secret-based) service account tokens.
+
+    These tokens never expire, so a leaked one stays valid until the secret is
+    deleted; projected tokens (TokenRequest API) should replace them.
+    """
+    existing_accounts = {(sa.metadata.namespace, sa.metadata.name) for sa in service_accounts}
+
+    tokens = []
+    for secret in secrets:
+        if secret.type != SA_TOKEN_TYPE:
+            continue
+        namespace = secret.metadata.namespace
+        labels = secret.metadata.labels or {}
+        account = (secret.metadata.annotations or {}).get("kubernetes.io/service-account.name")
+        last_used = labels.get(LEGACY_TOKEN_LAST_USED_LABEL)
+        age_days = (now - secret.metadata.creation_timestamp).days
+
+        if account and (namespace, account) not in existing_accounts:
+            status = "orphaned"
+        elif labels.get(LEGACY_TOKEN_INVALID_SINCE_LABEL):
+            status = "invalidated"
+        elif last_used and (now.date() - datetime.fromisoformat(last_used).date()).days > UNUSED_TOKEN_DAYS:
+            status = "unused"
+        else:
+            status = "long_lived"
+
+        tokens.append({
+            "namespace": namespace,
+            "secret": secret.metadata.name,
+            "service_account": account,
+            "status": status,
+            "age_days": age_days,
+            "last_used": last_used,
+        })
+
+    tokens.sort(key=lambda t: (SA_TOKEN_STATUS_ORDER[t["status"]], -t["age_days"], t["namespace"], t["secret"]))
+    return tokens
+
+
+def collect_credential_risks(pods: list[client.V1Pod], events: list, exclusions: Optional[dict] = None) -> Optional[dict]:
+    """Flag image pull secrets and service account tokens likely to cause trouble.
+
+    Catches the classic image pull auth failure before it happens: pull
+    secrets referenced by pods that do not exist, registry tokens that expired
+    or expire within a week because nothing refreshes them, and pull secrets
+    nobody references anymore. Long-lived service account tokens are listed
+    with their last use, when the API server tracks it.
+
+    Args:
+        pods: In-scope pods of the cluster
+        events: Warning events of the cluster
+        exclusions: Output of collect_exclusions(), to skip opted-out namespaces
+
+    Returns:
+        Dict with "pull_secrets" and "service_account_tokens" lists, or None
+        when Secrets cannot be listed
+    """
+    core_v1 = client.CoreV1Api(get_api_client())
+    excluded_namespaces = set((exclusions or {}).get("namespaces", []))
+
+    def in_scope(obj) -> bool:
+        namespace = obj.metadata.namespace
+        return settings.namespace_in_scope(namespace) and namespace not in excluded_namespaces
+
+    try:
+        secrets = [s for s in core_v1.list_secret_for_all_namespaces().items if in_scope(s)]
+        service_accounts = [
+            sa for sa in core_v1.list_service_account_for_all_namespaces().items if in_scope(sa)
+        ]
+    except client.ApiException as e:
+        logger.warning("credential_checks_unavailable", status=e.status, source="stats")
+        return None
+
+    now = datetime.now(timezone.utc)
+    risks = {
+        "pull_secrets": _pull_secret_risks(secrets, service_accounts, pods, events, now),
+        "service_account_tokens": _service_account_token_risks(secrets, service_accounts, now),
+    }
+
+    logger.info(
+        "credential_risks_collected",
+        pull_secrets=len(risks["pull_secrets"]),
+        service_account_tokens=len(risks["service_account_tokens"]),
+        source="stats",
+    )
+
+    return risks
@@ -0,0 +1,97 @@
+import threading
+from typing import Any, Callable, Optional
+
+import structlog
+
+logger = structlog.get_logger()
+
+
+def parse_collector_timeouts(spec: str) -> dict[str, float]:
+    """Parse COLLECTOR_TIMEOUTS ("node_usage=30,control_plane=60").
+
+    Args:
+        spec: Comma-separated section=seconds entries
+
+    Returns:
+        Section -> seconds; malformed entries are logged and skipped
+    """
+    timeouts = {}
+    for entry in filter(None, (e.strip() for e in spec.split(","))):
+        section, _, seconds = entry.partition("=")
+        try:
+            timeouts[section.strip()] = float(seconds)
+        except ValueError:
+            logger.warning("collector_timeout_ignored", entry=entry, source="stats")
+    return timeouts
+
+
+class CollectorTimeout(TimeoutError):
+    """Raised when a collector the snapshot cannot do without misses its deadline."""
+
+
+class CollectorDeadlines:
+    """Run collectors with a deadline each and remember the sections that missed it.
+
+    A collector that misses its deadline keeps running in a daemon thread (a
+    blocked HTTP call cannot be interrupted), but the snapshot goes on
+    without it.
+    """
+
+    def __init__(self, default_timeout: float, overrides: Optional[dict[str, float]] = None) -> None:
+        """Initialize the deadlines.
+
+        Args:
+            default_timeout: Seconds per collector (0 = no deadline)
+            overrides: Per-section seconds, from parse_collector_timeouts()
+        """
+        self.default_timeout = default_timeout
+        self.overrides = overrides or {}
+        self.missing: list[dict] = []
+
+    def run(
+        self,
+        section: str,
+        collector: Callable[..., Any],
+        *args,
+        default: Any = None,
+        required: bool = False,
+        **kwargs,
+    ) -> Any:
+        """Run a collector, returning `default` if it misses its deadline.
+
+        Errors raised by the collector are re-raised as they are.
+
+        Args:
+            section: Statistics section the collector fills
+            collector: Function to call with args and kwargs
+            default: Value used when the deadline is missed
+            required: Raise CollectorTimeout instead of continuing without the section
+
+        Returns:
+            The collector's result, or `default` on timeout
+        """
+        timeout = self.overrides.get(section, self.default_timeout)
+        if not timeout or timeout <= 0:
+            return collector(*args, **kwargs)
+
+        outcome: dict[str, Any] = {}
+
+        def target() -> None:
+            try:
+                outcome["result"] = collector(*args, **kwargs)
+            except BaseException as e:
+                outcome["error"] = e
+
+        thread = threading.Thread(target=target, name=f"collector-{section}", daemon=True)
+        thread.start()
+        thread.join(timeout)
+
+        if thread.is_alive():
+            logger.warning("collector_timed_out", section=section, timeout_seconds=timeout, source="stats")
+            if required:
+                raise CollectorTimeout(f"{section} collector timed out after {timeout:g}s")
+            self.missing.append({"section": section, "reason": f"timed out after {timeout:g}s"})
+            return default
+        if "error" in outcome:
+            raise outcome["error"]
+        return outcome["result"]
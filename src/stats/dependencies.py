@@ -0,0 +1,112 @@
+import re
+from typing import Optional
+
+import structlog
+from kubernetes import client
+
+from src.config import settings
+from src.kube import get_api_client
+
+logger = structlog.get_logger()
+
+# Hostnames in env values: whole value, URL authority, user@host or list items
+HOST_PATTERN = re.compile(r"(?:^|://|@|,)([a-z0-9][a-z0-9.-]*[a-z0-9])(?![a-z0-9.-]|://)", re.IGNORECASE)
+
+# Suffixes that may follow <service>.<namespace> in an in-cluster hostname
+SERVICE_DOMAIN_SUFFIXES = ([], ["svc"], ["svc", "cluster", "local"])
+
+
+def _resolve_host(host: str, namespace: str, services: set[tuple[str, str]]) -> Optional[tuple[str, str]]:
+    """Map a hostname to a (namespace, service) pair if it names an in-cluster Service.
+
+    Args:
+        host: Hostname found in an env value
+        namespace: Namespace of the workload that uses it
+        services: Known (namespace, service) pairs
+
+    Returns:
+        (namespace, service) or None
+    """
+    parts = host.lower().split(".")
+    if len(parts) == 1:
+        return (namespace, parts[0]) if (namespace, parts[0]) in services else None
+
+    if parts[2:] in SERVICE_DOMAIN_SUFFIXES and (parts[1], parts[0]) in services:
+        return parts[1], parts[0]
+
+    return None
+
+
+def infer_dependencies() -> list[dict]:
+    """Infer service-to-service dependencies from Services and env references.
+
+    A workload depends on a Service when one of its containers has an env var
+    whose value points at the Service's in-cluster hostname. The Service is
+    then mapped to the workloads its selector targets.
+
+    Returns:
+        List of edges (namespace, workload, kind, target_namespace,
+        target_service, target_workload, via)
+    """
+    core_v1 = client.CoreV1Api(get_api_client())
+    apps_v1 = client.AppsV1Api(get_api_client())
+
+    workloads = []
+    for kind, items in (
+        ("Deployment", apps_v1.list_deployment_for_all_namespaces().items),
+        ("StatefulSet", apps_v1.list_stateful_set_for_all_namespaces().items),
+        ("DaemonSet", apps_v1.list_daemon_set_for_all_namespaces().items),
+    ):
+        for item in items:
+            if not settings.namespace_in_scope(item.metadata.namespace):
+                continue
+            workloads.append((kind, item))
+
+    services: dict[tuple[str, str], dict] = {}
+    for service in core_v1.list_service_for_all_namespaces().items:
+        services[(service.metadata.namespace, service.metadata.name)] = service.spec.selector or {}
+
+    # Service -> workloads whose pod template labels match its selector
+    backing: dict[tuple[str, str], list[str]] = {}
+    for key, selector in services.items():
+        if not selector:
+            continue
+        for _, item in workloads:
+            labels = item.spec.template.metadata.labels or {}
+            if item.metadata.namespace == key[0] and all(labels.get(k) == v for k, v in selector.items()):
+                backing.setdefault(key, []).append(item.metadata.name)
+
+    edges = []
+    seen = set()
+    service_keys = set(services)
+    for kind, item in workloads:
+        namespace = item.metadata.namespace
+        spec = item.spec.template.spec
+        for container in (spec.init_containers or []) + (spec.containers or []):
+            for env in container.env or []:
+                if not env.value:
+                    continue
+                for host in HOST_PATTERN.findall(env.value):
+                    target = _resolve_host(host, namespace, service_keys)
+                    if not target:
+                        continue
+                    for target_workload in backing.get(target) or [None]:
+                        if (namespace, item.metadata.name) == (target[0], target_workload):
+                            continue
+                        key = (namespace, item.metadata.name, target, target_workload)
+                        if key in seen:
+                            continue
+                        seen.add(key)
+                        edges.append({
+                            "namespace": namespace,
+                            "workload": item.metadata.name,
+                            "kind": kind,
+                            "target_namespace": target[0],
+                            "target_service": target[1],
+                            "target_workload": target_workload,
+                            "via": f"env:{env.name}",
+                        })
+
+    logger.info("dependencies_inferred", edges=len(edges), source="stats")
+
+    return edges
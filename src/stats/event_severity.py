@@ -0,0 +1,155 @@
+from collections import Counter
+from typing import Optional
+
+import structlog
+
+logger = structlog.get_logger()
+
+SEVERITIES = ("critical", "warning", "info")
+
+# Event reason -> severity. Critical: the node or data plane is failing, or
+# workloads cannot run at all; warning: degraded or retrying; info: expected noise
+DEFAULT_EVENT_SEVERITIES = {
+    # Nodes
+    "NodeNotReady": "critical",
+    "NodeNotSchedulable": "warning",
+    "Rebooted": "warning",
+    "KernelOops": "critical",
+    "KernelPanic": "critical",
+    "KernelDeadlock": "critical",
+    "TaskHung": "critical",
+    "SystemOOM": "critical",
+    "EvictionThresholdMet": "critical",
+    "NodeHasDiskPressure": "critical",
+    "NodeHasMemoryPressure": "critical",
+    "NodeHasInsufficientPID": "critical",
+    "FreeDiskSpaceFailed": "warning",
+    "ImageGCFailed": "warning",
+    "ContainerGCFailed": "warning",
+    # Pods
+    "Evicted": "critical",
+    "Preempted": "warning",
+    "OOMKilling": "critical",
+    "FailedScheduling": "warning",
+    "BackOff": "warning",
+    "CrashLoopBackOff": "warning",
+    "Failed": "warning",
+    "ErrImagePull": "warning",
+    "ImagePullBackOff": "warning",
+    "InspectFailed": "warning",
+    "FailedCreatePodSandBox": "critical",
+    "FailedKillPod": "warning",
+    "FailedPostStartHook": "warning",
+    "FailedPreStopHook": "info",
+    "ExceededGracePeriod": "warning",
+    "Unhealthy": "info",
+    "ProbeWarning": "info",
+    "DNSConfigForming": "info",
+    "NetworkNotReady": "critical",
+    # Storage
+    "FailedMount": "warning",
+    "FailedAttachVolume": "critical",
+    "FailedDetachVolume": "warning",
+    "VolumeResizeFailed": "warning",
+    "ProvisioningFailed": "critical",
+    "FailedBinding": "warning",
+    # Controllers
+    "FailedCreate": "critical",
+    "FailedDelete": "warning",
+    "FailedSync": "warning",
+    "BackoffLimitExceeded": "warning",
+    "DeadlineExceeded": "warning",
+    "FailedGetResourceMetric": "info",
+    "FailedComputeMetricsReplicas": "warning",
+    "FailedGetScale": "warning",
+    "FailedRescale": "warning",
+    "SyncLoadBalancerFailed": "critical",
+    "UpdateLoadBalancerFailed": "warning",
+    "FailedToUpdateEndpoint": "warning",
+}
+
+# Severity of reasons not in the mapping (Warning events only; Normal events are info)
+UNKNOWN_WARNING_SEVERITY = "warning"
+
+
+def parse_severity_overrides(value: str) -> dict[str, str]:
+    """Parse user event severity mappings.
+
+    The format is ``<reason>=<severity>`` pairs separated by commas, e.g.
+    ``Unhealthy=warning,BackoffLimitExceeded=info``; severities are critical,
+    warning or info.
+
+    Args:
+        value: EVENT_SEVERITY_OVERRIDES setting
+
+    Returns:
+        Reason -> severity mapping
+    """
+    overrides = {}
+    for item in (value or "").split(","):
+        reason, _, severity = item.partition("=")
+        reason, severity = reason.strip(), severity.strip().lower()
+        if not reason or severity not in SEVERITIES:
+            if item.strip():
+                logger.warning("event_severity_override_invalid", item=item.strip(), source="stats")
+            continue
+        overrides[reason] = severity
+    return overrides
+
+
+def classify_event(reason: Optional[str], event_type: Optional[str], overrides: Optional[dict[str, str]] = None) -> str:
+    """Return the severity of an event from its reason.
+
+    Args:
+        reason: Event reason (FailedScheduling, NodeNotReady...)
+        event_type: Event type (Warning or Normal)
+        overrides: Output of parse_severity_overrides(), applied before the defaults
+
+    Returns:
+        critical, warning or info
+    """
+    reason = reason or ""
+    severity = (overrides or {}).get(reason) or DEFAULT_EVENT_SEVERITIES.get(reason)
+    if severity:
+        return severity
+    return UNKNOWN_WARNING_SEVERITY if event_type == "Warning" else "info"
+
+
+def summarize_event_severities(events: list, overrides: Optional[dict[str, str]] = None) -> dict:
+    """Count events by severity and rank their reasons, most severe first.
+
+    Args:
+        events: Cluster events (warning events and node events)
+        overrides: Output of parse_severity_overrides()
+
+    Returns:
+        Dict with event counts per severity and the top reasons (reason,
+        severity, events, namespaces)
+    """
+    counts: Counter = Counter()
+    reasons: dict[str, dict] = {}
+    for event in events:
+        severity = classify_event(event.reason, event.type, overrides)
+        count = event.count or 1
+        counts[severity] += count
+        entry = reasons.setdefault(
+            event.reason or "Unknown", {"severity": severity, "events": 0, "namespaces": set()}
+        )
+        entry["events"] += count
+        if event.metadata.namespace:
+            entry["namespaces"].add(event.metadata.namespace)
+
+    ranked = sorted(reasons.items(), key=lambda item: (SEVERITIES.index(item[1]["severity"]), -item[1]["events"]))
+
+    return {
+        "counts": {severity: counts.get(severity, 0) for severity in SEVERITIES},
+        "reasons": [
+            {
+                "reason": reason,
+                "severity": entry["severity"],
+                "events": entry["events"],
+                "namespaces": sorted(entry["namespaces"]),
+            }
+            for reason, entry in ranked[:20]
+        ],
+    }
@@ -0,0 +1,135 @@
+from typing import Optional
+
+import structlog
+from kubernetes import client
+
+from src.config import settings
+from src.kube import get_api_client
+from src.reporter.heatmap import workload_name
+
+logger = structlog.get_logger()
+
+# Namespaces, workloads and pods annotated with this key set to "true" are
+# left out of the analysis (experiments, chaos-engineering targets...)
+IGNORE_ANNOTATION = "watchdog.helmcode.com/ignore"
+
+# Pod owner kind -> workload kind listed in the report appendix
+OWNER_KINDS = {"ReplicaSet": "Deployment"}
+
+
+def _opted_out(metadata) -> bool:
+    """Return True when an object carries the ignore annotation."""
+    return (metadata.annotations or {}).get(IGNORE_ANNOTATION, "").lower() == "true"
+
+
+def collect_exclusions() -> dict:
+    """List namespaces and workloads that opted out of the analysis.
+
+    Returns:
+        Dict with "namespaces" (names) and "workloads" (namespace, kind, name)
+    """
+    core_v1 = client.CoreV1Api(get_api_client())
+    apps_v1 = client.AppsV1Api(get_api_client())
+    batch_v1 = client.BatchV1Api(get_api_client())
+
+    namespaces = sorted(
+        ns.metadata.name for ns in core_v1.list_namespace().items if _opted_out(ns.metadata)
+    )
+
+    try:
+        cron_jobs = batch_v1.list_cron_job_for_all_namespaces().items
+    except client.ApiException as e:
+        logger.warning("cron_jobs_unavailable", status=e.status, source="stats")
+        cron_jobs = []
+
+    workloads = []
+    for kind, items in (
+        ("Deployment", apps_v1.list_deployment_for_all_namespaces().items),
+        ("StatefulSet", apps_v1.list_stateful_set_for_all_namespaces().items),
+        ("DaemonSet", apps_v1.list_daemon_set_for_all_namespaces().items),
+        ("CronJob", cron_jobs),
+    ):
+        for item in items:
+            if (
+                _opted_out(item.metadata)
+                and item.metadata.namespace not in namespaces
+                and settings.namespace_in_scope(item.metadata.namespace)
+            ):
+                workloads.append({"namespace": item.metadata.namespace, "kind": kind, "name": item.metadata.name})
+
+    if namespaces or workloads:
+        logger.info(
+            "analysis_exclusions_collected",
+            namespaces=len(namespaces),
+            workloads=len(workloads),
+            source="stats",
+        )
+
+    return {"namespaces": namespaces, "workloads": workloads}
+
+
+def is_excluded(
+    exclusions: Optional[dict],
+    namespace: str,
+    pod_name: Optional[str] = None,
+    annotations: Optional[dict] = None,
+) -> bool:
+    """Return True when a pod (or a whole namespace) opted out of the analysis.
+
+    Pods are attributed to their workload by name, like the restart heatmap,
+    so stored pod watcher rows can be filtered as well as live pods.
+
+    Args:
+        exclusions: Output of collect_exclusions()
+        namespace: Pod namespace
+        pod_name: Pod name, or None to check the namespace only
+        annotations: Pod annotations, when the live pod is at hand
+
+    Returns:
+        Whether the pod is excluded
+    """
+    if (annotations or {}).get(IGNORE_ANNOTATION, "").lower() == "true":
+        return True
+    if not exclusions:
+        return False
+    if namespace in exclusions["namespaces"]:
+        return True
+    if pod_name is None:
+        return False
+    pod_workload = workload_name(pod_name)
+    return any(
+        w["namespace"] == namespace and (
+            w["name"] == pod_workload
+            # CronJob pods are named <cronjob>-<schedule timestamp>-<suffix>
+            or (w["kind"] == "CronJob" and pod_workload.startswith(f"{w['name']}-"))
+        )
+        for w in exclusions["workloads"]
+    )
+
+
+def filter_excluded_pods(pods: list[client.V1Pod], exclusions: dict) -> list[client.V1Pod]:
+    """Drop pods that opted out of the analysis.
+
+    Workloads annotated only through their pod template are added to
+    exclusions["workloads"], so the report appendix lists them too.
+
+    Args:
+        pods: Pods of the cluster
+        exclusions: Output of collect_exclusions(), updated in place
+
+    Returns:
+        Pods to analyze
+    """
+    kept = []
+    for pod in pods:
+        namespace, name = pod.metadata.namespace, pod.metadata.name
+        if not is_excluded(exclusions, namespace, name, pod.metadata.annotations):
+            kept.append(pod)
+        elif not is_excluded(exclusions, namespace, name):
+            owner = (pod.metadata.owner_references or [None])[0]
+            exclusions["workloads"].append({
+                "namespace": namespace,
+                "kind": OWNER_KINDS.get(owner.kind, owner.kind) if owner else "Pod",
+                "name": workload_name(name),
+            })
+    return kept
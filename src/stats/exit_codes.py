@@ -0,0 +1,128 @@
+from collections import Counter
+from typing import Optional
+
+from kubernetes import client
+
+from src.reporter.heatmap import workload_name
+
+# Failure classes, in report order: (key, label, hint for the analysis)
+FAILURE_CLASSES = [
+    ("oom", "Out of memory (137 / OOMKilled)", "memory limit too low or a leak; compare usage with the limit"),
+    ("segfault", "Segmentation fault (139)", "native crash in the application or a library; not a Kubernetes issue"),
+    ("abort", "Aborted (134)", "the runtime aborted itself, e.g. a failed assertion or fatal JVM/Go error"),
+    ("config_error", "Application error (1, 2)", "the process exited on its own; usually bad configuration, missing secrets or a failed dependency"),
+    ("command_error", "Command not found / not executable (126, 127)", "wrong image, entrypoint or command"),
+    ("terminated", "Terminated (143 / SIGTERM)", "stopped by Kubernetes; slow shutdowns, failed liveness probes or evictions"),
+    ("other", "Other exit codes", "check the container logs"),
+]
+
+SIGNAL_NAMES = {6: "SIGABRT", 9: "SIGKILL", 11: "SIGSEGV", 15: "SIGTERM"}
+
+MAX_WORKLOADS_PER_CLASS = 5
+
+
+def termination_signal(exit_code: Optional[int], signal: Optional[int] = None) -> Optional[int]:
+    """Return the signal that killed a container, when the exit code encodes one (128 + n)."""
+    if signal:
+        return signal
+    if exit_code is not None and exit_code > 128:
+        return exit_code - 128
+    return None
+
+
+def classify_termination(exit_code: Optional[int], reason: Optional[str], signal: Optional[int] = None) -> str:
+    """Map a container termination to one of FAILURE_CLASSES.
+
+    Args:
+        exit_code: Container exit code
+        reason: Kubernetes termination reason (OOMKilled, Error...)
+        signal: Signal number, when the runtime reports it
+
+    Returns:
+        Failure class key
+    """
+    signal = termination_signal(exit_code, signal)
+    if reason == "OOMKilled" or exit_code == 137:
+        return "oom"
+    if signal == 11:
+        return "segfault"
+    if signal == 6:
+        return "abort"
+    if signal == 15:
+        return "terminated"
+    if exit_code in (1, 2):
+        return "config_error"
+    if exit_code in (126, 127):
+        return "command_error"
+    return "other"
+
+
+def _current_terminations(pods: list[client.V1Pod]) -> list[dict]:
+    """Read the last termination of every restarted container from the API."""
+    terminations = []
+    for pod in pods:
+        for cs in pod.status.container_statuses or []:
+            terminated = cs.last_state.terminated if cs.last_state else None
+            if cs.restart_count and terminated and (terminated.exit_code or terminated.reason != "Completed"):
+                terminations.append({
+                    "namespace": pod.metadata.namespace,
+                    "pod": pod.metadata.name,
+                    "container": cs.name,
+                    "reason": terminated.reason,
+                    "exit_code": terminated.exit_code,
+                    "signal": terminated.signal,
+                })
+    return terminations
+
+
+def summarize_exit_codes(pods: list[client.V1Pod], terminations: Optional[list[dict]] = None) -> dict:
+    """Group container terminations by exit code and failure class.
+
+    Uses the terminations recorded by the pod watcher when available (every
+    crash of the week); otherwise only the last termination of each restarted
+    container is visible through the API.
+
+    Args:
+        pods: Pods of the cluster
+        terminations: Container terminations recorded by the pod watcher
+
+    Returns:
+        Dict with the total, the source, per-class counts with the most
+        affected workloads, and per-exit-code counts
+    """
+    source = "watcher" if terminations is not None else "last_state"
+    rows = terminations if terminations is not None else _current_terminations(pods)
+
+    class_counts: Counter = Counter()
+    class_workloads: dict[str, Counter] = {}
+    code_counts: Counter = Counter()
+    for row in rows:
+        failure_class = classify_termination(row["exit_code"], row["reason"], row.get("signal"))
+        class_counts[failure_class] += 1
+        class_workloads.setdefault(failure_class, Counter())[
+            f"{row['namespace']}/{workload_name(row['pod'])}"
+        ] += 1
+        code_counts[(row["exit_code"], termination_signal(row["exit_code"], row.get("signal")))] += 1
+
+    return {
+        "total": len(rows),
+        "source": source,
+        "classes": [
+            {
+                "class": key,
+                "label": label,
+                "hint": hint,
+                "count": class_counts[key],
+                "workloads": [
+                    {"workload": workload, "count": count}
+                    for workload, count in class_workloads[key].most_common(MAX_WORKLOADS_PER_CLASS)
+                ],
+            }
+            for key, label, hint in FAILURE_CLASSES
+            if class_counts[key]
+        ],
+        "exit_codes": [
+            {"exit_code": exit_code, "signal": SIGNAL_NAMES.get(signal, signal), "count": count}
+            for (exit_code, signal), count in code_counts.most_common()
+        ],
+    }
@@ -0,0 +1,101 @@
+from collections import Counter
+from typing import Optional
+
+import httpx
+import structlog
+
+from src.config import settings
+from src.stats.appendix import MAX_APPENDIX_ROWS
+
+logger = structlog.get_logger()
+
+
+def _percent(part: float, total: float) -> float:
+    """Return part/total as a percentage rounded to one decimal (0 when total is 0)."""
+    return round(part / total * 100, 1) if total else 0.0
+
+
+def _query_vector(query: str) -> list[dict]:
+    """Run an instant PromQL query and return its result vector."""
+    response = httpx.get(
+        f"{settings.prometheus_url}/api/v1/query", params={"query": query}, timeout=30.0
+    )
+    response.raise_for_status()
+    return response.json().get("data", {}).get("result", [])
+
+
+def _in_scope(items: list[dict]) -> list[dict]:
+    """Drop series whose namespace label is filtered out by the include/exclude patterns."""
+    return [
+        item for item in items
+        if settings.namespace_in_scope(item["metric"].get("namespace", ""))
+    ]
+
+
+def _pod_key(metric: dict) -> tuple[str, str]:
+    """Return the (namespace, pod) labels of a series."""
+    return metric.get("namespace", ""), metric.get("pod", "")
+
+
+def collect_ksm_stats(restarts_this_week: Optional[int] = None) -> dict:
+    """Compute headline cluster figures from kube-state-metrics through Prometheus.
+
+    Used when WORKLOAD_DATA_SOURCE is "ksm": the service account is not expected
+    to list resources, so only the figures kube-state-metrics exposes are
+    collected. Warning events and exclusion annotations are not available.
+
+    Args:
+        restarts_this_week: Container restarts recorded by the pod watcher
+            over the last 7 days, when available
+
+    Returns:
+        Dict of cluster statistics, shaped like the output of collect_cluster_stats()
+    """
+    phases_by_pod = {
+        _pod_key(item["metric"]): item["metric"].get("phase") or "Unknown"
+        for item in _in_scope(_query_vector("kube_pod_status_phase == 1"))
+    }
+    restarts: Counter = Counter()
+    for item in _in_scope(
+        _query_vector("sum by (namespace, pod) (kube_pod_container_status_restarts_total)")
+    ):
+        restarts[_pod_key(item["metric"])] = int(float(item["value"][1]))
+
+    nodes = {item["metric"].get("node") for item in _query_vector("kube_node_info")}
+    ready_query = 'kube_node_status_condition{condition="Ready", status="true"} == 1'
+    ready_nodes = {item["metric"].get("node") for item in _query_vector(ready_query)}
+
+    phases = Counter(phases_by_pod.values())
+    stats = {
+        "total_pods": len(phases_by_pod),
+        "running_pods": phases.get("Running", 0),
+        "running_pct": _percent(phases.get("Running", 0), len(phases_by_pod)),
+        "pods_by_phase": dict(phases),
+        "total_restarts": sum(restarts.values()),
+        "restarts_this_week": restarts_this_week,
+        "total_nodes": len(nodes),
+        "ready_nodes": len(ready_nodes & nodes),
+        # kube-state-metrics does not export events
+        "top_warning_namespaces": [],
+        # Rows of the CSV appendices; only restarts are observable
+        "appendix": {
+            "restarting_pods": [
+                {"namespace": namespace, "pod": pod, "restarts": count, "reason": ""}
+                for (namespace, pod), count in restarts.most_common(MAX_APPENDIX_ROWS)
+                if count
+            ],
+            "rightsizing": [],
+            "warning_events": [],
+        },
+        "missing_sections": [],
+    }
+
+    logger.info(
+        "cluster_stats_collected",
+        total_pods=stats["total_pods"],
+        total_nodes=stats["total_nodes"],
+        data_source="ksm",
+        source="stats",
+    )
+
+    return stats
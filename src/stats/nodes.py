@@ -0,0 +1,145 @@
+from datetime import datetime, timedelta, timezone
+from typing import Optional
+
+import structlog
+from kubernetes import client
+
+from src.kube import get_api_client
+
+logger = structlog.get_logger()
+
+# Node event reason -> lifecycle event type
+NODE_EVENT_TYPES = {
+    "Rebooted": "reboot",
+    "Starting": "kubelet_restart",
+    "NodeNotReady": "not_ready",
+    "KernelOops": "kernel_panic",
+    "KernelPanic": "kernel_panic",
+    "KernelDeadlock": "kernel_panic",
+    "TaskHung": "kernel_panic",
+    "SystemOOM": "system_oom",
+}
+
+# node-problem-detector conditions that mean the node is degraded while True
+PROBLEM_CONDITIONS = {
+    "KernelDeadlock",
+    "ReadonlyFilesystem",
+    "FrequentKubeletRestart",
+    "FrequentContainerdRestart",
+    "FrequentDockerRestart",
+    "CorruptDockerOverlay2",
+}
+
+# Score deducted per occurrence, from a starting score of 100
+STABILITY_PENALTIES = {
+    "reboot": 20,
+    "kernel_panic": 25,
+    "kubelet_restart": 10,
+    "not_ready": 10,
+    "system_oom": 10,
+    "problem_condition": 15,
+}
+
+
+def _as_utc(value: Optional[datetime]) -> Optional[datetime]:
+    """Return a timezone-aware datetime (the API client may return naive ones)."""
+    if value is None:
+        return None
+    return value if value.tzinfo else value.replace(tzinfo=timezone.utc)
+
+
+def list_node_events() -> list[client.CoreV1Event]:
+    """List events about nodes (kubelet and node-problem-detector), empty if forbidden."""
+    try:
+        return client.CoreV1Api(get_api_client()).list_event_for_all_namespaces(
+            field_selector="involvedObject.kind=Node"
+        ).items
+    except client.ApiException as e:
+        logger.warning("node_events_unavailable", status=e.status, source="stats")
+        return []
+
+
+def collect_node_stability(
+    nodes: list[client.V1Node],
+    events: list[client.CoreV1Event],
+    since_hours: int = 168,
+) -> list[dict]:
+    """Detect reboots, kernel panics and kubelet restarts and score each node.
+
+    Combines node events (kubelet and node-problem-detector) with condition
+    transitions, since events usually expire long before the weekly report.
+
+    Args:
+        nodes: Cluster nodes
+        events: Node events (output of list_node_events())
+        since_hours: Window to look back over
+
+    Returns:
+        Per-node list with event counts, active problem conditions and a
+        0-100 stability score, least stable first
+    """
+    since = datetime.now(timezone.utc) - timedelta(hours=since_hours)
+
+    counts: dict[str, dict[str, int]] = {
+        node.metadata.name: {event_type: 0 for event_type in STABILITY_PENALTIES}
+        for node in nodes
+    }
+
+    for event in events:
+        event_type = NODE_EVENT_TYPES.get(event.reason)
+        name = event.involved_object.name
+        if not event_type or name not in counts:
+            continue
+        # A kubelet "Starting" event only means a restart when the source is the kubelet
+        if event_type == "kubelet_restart" and (event.source and event.source.component) != "kubelet":
+            continue
+        seen = _as_utc(event.last_timestamp or event.event_time or event.metadata.creation_timestamp)
+        if seen and seen < since:
+            continue
+        counts[name][event_type] += event.count or 1
+
+    result = []
+    for node in nodes:
+        name = node.metadata.name
+        node_counts = counts[name]
+        conditions = node.status.conditions or []
+
+        # Ready flipped inside the window and no event recorded it (ignoring new nodes)
+        ready = next((c for c in conditions if c.type == "Ready"), None)
+        ready_since = _as_utc(ready.last_transition_time) if ready else None
+        created = _as_utc(node.metadata.creation_timestamp)
+        if (
+            ready_since and ready_since >= since
+            and not (created and created >= since)
+            and not node_counts["not_ready"]
+        ):
+            node_counts["not_ready"] = 1
+
+        active_problems = sorted(
+            c.type for c in conditions if c.type in PROBLEM_CONDITIONS and c.status == "True"
+        )
+        node_counts["problem_condition"] = len(active_problems)
+
+        score = 100 - sum(STABILITY_PENALTIES[key] * value for key, value in node_counts.items())
+        result.append({
+            "node": name,
+            "reboots": node_counts["reboot"],
+            "kernel_panics": node_counts["kernel_panic"],
+            "kubelet_restarts": node_counts["kubelet_restart"],
+            "not_ready_transitions": node_counts["not_ready"],
+            "system_ooms": node_counts["system_oom"],
+            "active_problems": active_problems,
+            "ready": bool(ready and ready.status == "True"),
+            "stability_score": max(score, 0),
+        })
+
+    result.sort(key=lambda n: (n["stability_score"], n["node"]))
+
+    logger.info(
+        "node_stability_collected",
+        nodes=len(result),
+        unstable=sum(1 for n in result if n["stability_score"] < 100),
+        source="stats",
+    )
+
+    return result
@@ -0,0 +1,99 @@
+from collections import Counter
+
+from kubernetes import client
+
+from src.reporter.heatmap import workload_name
+
+# Node conditions that are a problem while True
+PRESSURE_CONDITIONS = ("MemoryPressure", "DiskPressure", "PIDPressure", "NetworkUnavailable")
+
+MAX_WORKLOADS_PER_NODE = 50
+
+
+def collect_pod_placement(pods: list[client.V1Pod]) -> dict[str, list[dict]]:
+    """Map each node to the workloads whose pods run on it at snapshot time.
+
+    Args:
+        pods: In-scope pods of the cluster
+
+    Returns:
+        Node name -> list of workloads (namespace/name, pods on the node,
+        pods not ready), most pods first
+    """
+    pods_by_node: dict[str, Counter] = {}
+    not_ready_by_node: dict[str, Counter] = {}
+    for pod in pods:
+        node = pod.spec.node_name
+        if not node or pod.status.phase in ("Succeeded", "Failed"):
+            continue
+        workload = f"{pod.metadata.namespace}/{workload_name(pod.metadata.name)}"
+        pods_by_node.setdefault(node, Counter())[workload] += 1
+        ready = next((c for c in pod.status.conditions or [] if c.type == "Ready"), None)
+        if not (ready and ready.status == "True"):
+            not_ready_by_node.setdefault(node, Counter())[workload] += 1
+
+    return {
+        node: [
+            {"workload": workload, "pods": count, "not_ready": not_ready_by_node.get(node, Counter())[workload]}
+            for workload, count in workloads.most_common(MAX_WORKLOADS_PER_NODE)
+        ]
+        for node, workloads in sorted(pods_by_node.items())
+    }
+
+
+def correlate_node_incidents(
+    nodes: list[client.V1Node],
+    node_stability: list[dict],
+    placement: dict[str, list[dict]],
+) -> list[dict]:
+    """Connect node-level incidents to the workloads running on the node.
+
+    Args:
+        nodes: Cluster nodes
+        node_stability: Output of collect_node_stability()
+        placement: Output of collect_pod_placement()
+
+    Returns:
+        One entry per node that is not ready, under pressure or unstable this
+        week (node, current conditions, incidents this week, affected
+        workloads, pods), not ready nodes first
+    """
+    stability = {n["node"]: n for n in node_stability}
+
+    incidents = []
+    for node in nodes:
+        name = node.metadata.name
+        conditions = {c.type: c.status for c in node.status.conditions or []}
+        current = [] if conditions.get("Ready") == "True" else ["NotReady"]
+        current += [c for c in PRESSURE_CONDITIONS if conditions.get(c) == "True"]
+
+        past = []
+        node_history = stability.get(name)
+        if node_history:
+            current += node_history["active_problems"]
+            past = [
+                f"{node_history[key]} {label}"
+                for key, label in (
+                    ("reboots", "reboots"),
+                    ("kernel_panics", "kernel panics"),
+                    ("not_ready_transitions", "NotReady transitions"),
+                    ("system_ooms", "system OOMs"),
+                )
+                if node_history[key]
+            ]
+
+        if not current and not past:
+            continue
+
+        workloads = placement.get(name, [])
+        incidents.append({
+            "node": name,
+            "ready": conditions.get("Ready") == "True",
+            "conditions": current,
+            "this_week": past,
+            "pods": sum(w["pods"] for w in workloads),
+            "workloads": workloads,
+        })
+
+    incidents.sort(key=lambda i: (i["ready"], -i["pods"], i["node"]))
+    return incidents
@@ -0,0 +1,136 @@
+import asyncio
+import time
+from datetime import datetime
+from statistics import median
+from typing import Optional
+
+import httpx
+import structlog
+
+logger = structlog.get_logger()
+
+MAX_ERROR_LENGTH = 200
+
+
+def parse_probe_targets(spec: str) -> list[tuple[str, str]]:
+    """Parse ENDPOINT_PROBES ("name=url,name=url").
+
+    Entries without a name use the URL as name; malformed entries are
+    logged and skipped.
+
+    Args:
+        spec: Comma-separated probe targets
+
+    Returns:
+        List of (name, url)
+    """
+    targets = []
+    for entry in filter(None, (e.strip() for e in spec.split(","))):
+        # "=" before the scheme separates the name; later ones belong to the query string
+        name, url = entry.split("=", 1) if "=" in entry.split("://", 1)[0] else ("", entry)
+        name, url = name.strip(), url.strip()
+        if not url.startswith(("http://", "https://")):
+            logger.warning("endpoint_probe_ignored", entry=entry, reason="not an http(s) URL", source="stats")
+            continue
+        targets.append((name or url, url))
+    return targets
+
+
+async def _probe(client: httpx.AsyncClient, name: str, url: str) -> dict:
+    """Send one GET request and time it; 2xx and 3xx responses count as up."""
+    probed_at = datetime.now().isoformat()
+    started = time.monotonic()
+    try:
+        response = await client.get(url)
+        latency_ms = round((time.monotonic() - started) * 1000, 1)
+        ok = response.status_code < 400
+        return {
+            "name": name,
+            "url": url,
+            "probed_at": probed_at,
+            "ok": ok,
+            "status_code": response.status_code,
+            "latency_ms": latency_ms,
+            "error": None if ok else f"HTTP {response.status_code}",
+        }
+    except httpx.HTTPError as e:
+        return {
+            "name": name,
+            "url": url,
+            "probed_at": probed_at,
+            "ok": False,
+            "status_code": None,
+            "latency_ms": None,
+            "error": f"{type(e).__name__}: {e}"[:MAX_ERROR_LENGTH],
+        }
+
+
+async def run_endpoint_probes(targets: list[tuple[str, str]], timeout: float, attempts: int) -> list[dict]:
+    """Probe every target `attempts` times, targets in parallel.
+
+    Args:
+        targets: Output of parse_probe_targets()
+        timeout: Seconds before a request counts as failed
+        attempts: Requests per target (sequential, to smooth out one slow response)
+
+    Returns:
+        One result per request (name, url, probed_at, ok, status_code,
+        latency_ms, error)
+    """
+    async with httpx.AsyncClient(timeout=timeout, follow_redirects=False) as client:
+        async def probe_target(name: str, url: str) -> list[dict]:
+            return [await _probe(client, name, url) for _ in range(max(attempts, 1))]
+
+        results = await asyncio.gather(*(probe_target(name, url) for name, url in targets))
+
+    flat = [result for target_results in results for result in target_results]
+    logger.info(
+        "endpoint_probes_completed",
+        targets=len(targets),
+        failed=sum(1 for r in flat if not r["ok"]),
+        source="stats",
+    )
+    return flat
+
+
+def _percentile(values: list[float], pct: float) -> Optional[float]:
+    """Nearest-rank percentile, None for an empty list."""
+    if not values:
+        return None
+    ordered = sorted(values)
+    return ordered[min(len(ordered) - 1, max(0, round(pct / 100 * len(ordered)) - 1))]
+
+
+def summarize_endpoint_probes(current: list[dict], history: list[dict]) -> list[dict]:
+    """Combine this snapshot's probes with the stored ones of the week.
+
+    Args:
+        current: Output of run_endpoint_probes() for this snapshot
+        history: Stored probe results of the last 7 days (this snapshot included)
+
+    Returns:
+        One entry per target (name, url, up now, status code, median latency,
+        last error, and over the week: probes, success %, p50 and p95
+        latency), failing targets first
+    """
+    summary = []
+    for name in dict.fromkeys(r["name"] for r in current):
+        now = [r for r in current if r["name"] == name]
+        week = [r for r in history if r["name"] == name]
+        latencies = [r["latency_ms"] for r in week if r["ok"] and r["latency_ms"] is not None]
+        up_now = any(r["ok"] for r in now)
+        summary.append({
+            "name": name,
+            "url": now[0]["url"],
+            "up": up_now,
+            "status_code": now[-1]["status_code"],
+            "latency_ms": median([r["latency_ms"] for r in now if r["ok"]]) if up_now else None,
+            "error": next((r["error"] for r in reversed(now) if r["error"]), None),
+            "probes_this_week": len(week),
+            "success_pct": round(sum(1 for r in week if r["ok"]) / len(week) * 100, 1) if week else None,
+            "p50_latency_ms": _percentile(latencies, 50),
+            "p95_latency_ms": _percentile(latencies, 95),
+        })
+
+    summary.sort(key=lambda p: (p["up"], p["success_pct"] if p["success_pct"] is not None else 100, p["name"]))
+    return summary
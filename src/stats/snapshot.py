@@ -0,0 +1,77 @@
+from numbers import Number
+
+# Fields read unconditionally by the prompt block and the report header
+REQUIRED_FIELDS = {
+    "total_pods": int,
+    "running_pods": int,
+    "running_pct": Number,
+    "pods_by_phase": dict,
+    "total_restarts": int,
+    "total_nodes": int,
+    "ready_nodes": int,
+    "top_warning_namespaces": list,
+}
+
+# Optional fields that, when present, must have this type
+OPTIONAL_FIELDS = {
+    "restarts_this_week": int,
+    "event_severities": dict,
+    "workload_classes": list,
+    "cpu_requested_pct": Number,
+    "memory_requested_pct": Number,
+    "cpu_used_pct": Number,
+    "memory_used_pct": Number,
+    "node_usage": list,
+    "resource_coverage": list,
+    "pod_labels": list,
+    "cluster_identity": dict,
+    "node_stability": list,
+    "node_pools": list,
+    "pod_placement": dict,
+    "node_incidents": list,
+    "restart_correlations": list,
+    "exit_codes": dict,
+    "control_plane": dict,
+    "webhook_failures": list,
+    "credential_risks": dict,
+    "exclusions": dict,
+    "recent_rollouts": list,
+    "namespace_thresholds": list,
+    "system_components": list,
+    "watched_workloads": list,
+    "endpoint_probes": list,
+    "appendix": dict,
+    "missing_sections": list,
+}
+
+
+def validate_snapshot(stats: dict) -> list[str]:
+    """Check that injected statistics have the shape of collect_cluster_stats().
+
+    Only the top-level fields are checked; nested entries are trusted so
+    fixtures can stay small.
+
+    Args:
+        stats: Statistics dict to validate
+
+    Returns:
+        Validation errors, empty when the snapshot can be rendered
+    """
+    errors = []
+    for field, expected in REQUIRED_FIELDS.items():
+        if field not in stats:
+            errors.append(f"{field}: required")
+        elif isinstance(stats[field], bool) or not isinstance(stats[field], expected):
+            errors.append(f"{field}: expected {expected.__name__}")
+
+    for field, expected in OPTIONAL_FIELDS.items():
+        value = stats.get(field)
+        if value is not None and (isinstance(value, bool) or not isinstance(value, expected)):
+            errors.append(f"{field}: expected {expected.__name__} or null")
+
+    if not errors and stats["running_pods"] > stats["total_pods"]:
+        errors.append("running_pods: cannot exceed total_pods")
+    if not errors and stats["ready_nodes"] > stats["total_nodes"]:
+        errors.append("ready_nodes: cannot exceed total_nodes")
+
+    return errors
@@ -0,0 +1,120 @@
+import structlog
+from kubernetes import client
+
+from src.config import settings
+from src.kube import get_api_client
+
+logger = structlog.get_logger()
+
+# Workload name prefix -> add-on category
+ADDON_CATEGORIES = [
+    ("coredns", "dns"),
+    ("kube-dns", "dns"),
+    ("node-local-dns", "dns"),
+    ("kube-proxy", "networking"),
+    ("calico", "cni"),
+    ("cilium", "cni"),
+    ("aws-node", "cni"),
+    ("flannel", "cni"),
+    ("kube-flannel", "cni"),
+    ("weave", "cni"),
+    ("antrea", "cni"),
+    ("azure-cni", "cni"),
+    ("metrics-server", "metrics"),
+    ("ebs-csi", "storage"),
+    ("efs-csi", "storage"),
+    ("pdcsi", "storage"),
+    ("csi-", "storage"),
+    ("cluster-autoscaler", "autoscaling"),
+    ("konnectivity", "control-plane"),
+    ("kube-apiserver", "control-plane"),
+    ("kube-controller-manager", "control-plane"),
+    ("kube-scheduler", "control-plane"),
+    ("etcd", "control-plane"),
+]
+
+
+def _addon_category(name: str) -> str:
+    """Classify a system workload by its name."""
+    return next((category for prefix, category in ADDON_CATEGORIES if name.startswith(prefix)), "other")
+
+
+def collect_system_components() -> list[dict]:
+    """Summarize the health of workloads in the system namespaces (kube-system by default).
+
+    Static control plane pods (kubeadm clusters) are included as workloads of
+    their own; managed control planes do not run there.
+
+    Returns:
+        List of components with kind, desired/ready counts, restarts and
+        health, unhealthy ones first
+    """
+    core_v1 = client.CoreV1Api(get_api_client())
+    apps_v1 = client.AppsV1Api(get_api_client())
+
+    components = []
+    for namespace in settings.system_namespace_list:
+        pods = core_v1.list_namespaced_pod(namespace).items
+        restarts_by_pod: dict[str, int] = {}
+        for pod in pods:
+            restarts = sum(cs.restart_count for cs in pod.status.container_statuses or [])
+            restarts_by_pod[pod.metadata.name] = restarts
+
+        def _restarts(workload_name: str) -> int:
+            return sum(count for pod, count in restarts_by_pod.items() if pod.startswith(f"{workload_name}-"))
+
+        for deployment in apps_v1.list_namespaced_deployment(namespace).items:
+            desired = deployment.spec.replicas or 0
+            ready = deployment.status.ready_replicas or 0
+            components.append({
+                "namespace": namespace,
+                "name": deployment.metadata.name,
+                "kind": "Deployment",
+                "category": _addon_category(deployment.metadata.name),
+                "desired": desired,
+                "ready": ready,
+                "restarts": _restarts(deployment.metadata.name),
+            })
+
+        for daemon_set in apps_v1.list_namespaced_daemon_set(namespace).items:
+            desired = daemon_set.status.desired_number_scheduled or 0
+            ready = daemon_set.status.number_ready or 0
+            components.append({
+                "namespace": namespace,
+                "name": daemon_set.metadata.name,
+                "kind": "DaemonSet",
+                "category": _addon_category(daemon_set.metadata.name),
+                "desired": desired,
+                "ready": ready,
+                "restarts": _restarts(daemon_set.metadata.name),
+            })
+
+        # Static pods (kubeadm control plane) have a Node owner instead of a workload
+        for pod in pods:
+            owners = pod.metadata.owner_references or []
+            if not any(owner.kind == "Node" for owner in owners):
+                continue
+            ready = all(cs.ready for cs in pod.status.container_statuses or [])
+            components.append({
+                "namespace": namespace,
+                "name": pod.metadata.name,
+                "kind": "StaticPod",
+                "category": _addon_category(pod.metadata.name),
+                "desired": 1,
+                "ready": 1 if ready else 0,
+                "restarts": restarts_by_pod.get(pod.metadata.name, 0),
+            })
+
+    for component in components:
+        component["healthy"] = component["ready"] >= component["desired"]
+
+    components.sort(key=lambda c: (c["healthy"], -c["restarts"], c["name"]))
+
+    logger.info(
+        "system_components_collected",
+        components=len(components),
+        unhealthy=sum(1 for c in components if not c["healthy"]),
+        source="stats",
+    )
+
+    return components
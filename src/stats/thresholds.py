@@ -0,0 +1,99 @@
+from collections import Counter
+from typing import Optional
+
+import structlog
+
+from src.tools.namespaces import namespace_matches
+
+logger = structlog.get_logger()
+
+# Metric -> what its threshold counts, per namespace over the report window
+METRICS = {
+    "restarts": "container restarts",
+    "warnings": "warning events",
+}
+
+
+def parse_thresholds(value: str) -> list[tuple[str, dict[str, int]]]:
+    """Parse per-namespace severity thresholds.
+
+    The format is ``<namespace>:<metric>=<limit>[,<metric>=<limit>...]``
+    rules separated by semicolons, e.g. ``batch:restarts=50;payments:restarts=0,warnings=0``.
+    The namespace may be a name, a glob or a ``re:`` regex; the first matching rule wins.
+
+    Args:
+        value: NAMESPACE_THRESHOLDS setting
+
+    Returns:
+        List of (namespace pattern, {metric: tolerated count}) rules
+    """
+    rules = []
+    for rule in (value or "").split(";"):
+        # The limits hold no colon, unlike a re: pattern
+        pattern, _, limits = rule.strip().rpartition(":")
+        parsed = {}
+        for item in limits.split(","):
+            metric, _, limit = item.partition("=")
+            metric = metric.strip()
+            if metric not in METRICS or not limit.strip().isdigit():
+                if item.strip():
+                    logger.warning(
+                        "namespace_threshold_invalid", rule=rule.strip(), item=item.strip(), source="stats"
+                    )
+                continue
+            parsed[metric] = int(limit)
+        if pattern.strip() and parsed:
+            rules.append((pattern.strip(), parsed))
+    return rules
+
+
+def thresholds_for(namespace: str, rules: list[tuple[str, dict[str, int]]]) -> Optional[dict[str, int]]:
+    """Return the thresholds of the first rule matching a namespace, if any."""
+    return next((limits for pattern, limits in rules if namespace_matches(namespace, [pattern])), None)
+
+
+def evaluate_thresholds(
+    rules: list[tuple[str, dict[str, int]]],
+    restarts_by_namespace: Counter,
+    warnings_by_namespace: Counter,
+) -> list[dict]:
+    """Compare per-namespace figures with their configured tolerance.
+
+    Args:
+        rules: Output of parse_thresholds()
+        restarts_by_namespace: Container restarts per namespace
+        warnings_by_namespace: Warning events per namespace
+
+    Returns:
+        One entry per namespace and configured metric (namespace, metric,
+        value, threshold, breached), breaches first
+    """
+    observed = {"restarts": restarts_by_namespace, "warnings": warnings_by_namespace}
+    namespaces = set(restarts_by_namespace) | set(warnings_by_namespace)
+
+    results = []
+    for namespace in sorted(namespaces):
+        limits = thresholds_for(namespace, rules)
+        for metric, threshold in (limits or {}).items():
+            value = observed[metric][namespace]
+            results.append({
+                "namespace": namespace,
+                "metric": metric,
+                "value": value,
+                "threshold": threshold,
+                "breached": value > threshold,
+            })
+
+    results.sort(key=lambda r: (not r["breached"], r["namespace"], r["metric"]))
+    return results
+
+
+def format_thresholds_for_prompt(rules: list[tuple[str, dict[str, int]]]) -> str:
+    """Describe the configured tolerances for the system prompt."""
+    return "\n".join(
+        f"- {pattern}: " + ", ".join(
+            f"{'zero tolerance for' if limit == 0 else f'up to {limit}'} {METRICS[metric]} per week"
+            for metric, limit in limits.items()
+        )
+        for pattern, limits in rules
+    )
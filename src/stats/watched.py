@@ -0,0 +1,52 @@
+def summarize_workload_samples(samples: list[dict]) -> list[dict]:
+    """Aggregate watched workload samples into per-workload and per-day trends.
+
+    Restart counters are cumulative per pod and reset when pods are replaced,
+    so only increases between consecutive samples are counted.
+
+    Args:
+        samples: Output of get_workload_samples(), oldest first
+
+    Returns:
+        One dict per workload (namespace, kind, name, current ready/desired,
+        restarts, unavailable_samples, days), least available first
+    """
+    workloads: dict[tuple[str, str, str], dict] = {}
+    for sample in samples:
+        key = (sample["namespace"], sample["kind"], sample["name"])
+        workload = workloads.setdefault(key, {
+            "namespace": sample["namespace"],
+            "kind": sample["kind"],
+            "name": sample["name"],
+            "samples": 0,
+            "unavailable_samples": 0,
+            "restarts": 0,
+            "days": {},
+            "_last_restarts": None,
+        })
+        workload["samples"] += 1
+        workload["ready"] = sample["ready"]
+        workload["desired"] = sample["desired"]
+
+        restarts = 0
+        if workload["_last_restarts"] is not None:
+            restarts = max(0, (sample["restarts"] or 0) - workload["_last_restarts"])
+        workload["_last_restarts"] = sample["restarts"] or 0
+        workload["restarts"] += restarts
+
+        ready_pct = 100.0 if not sample["desired"] else round(sample["ready"] / sample["desired"] * 100, 1)
+        if ready_pct < 100:
+            workload["unavailable_samples"] += 1
+
+        day = workload["days"].setdefault(sample["sampled_at"][:10], {"restarts": 0, "min_ready_pct": 100.0})
+        day["restarts"] += restarts
+        day["min_ready_pct"] = min(day["min_ready_pct"], ready_pct)
+
+    summary = []
+    for workload in workloads.values():
+        workload.pop("_last_restarts")
+        summary.append(workload)
+
+    summary.sort(key=lambda w: (-w["unavailable_samples"], -w["restarts"], w["namespace"], w["name"]))
+
+    return summary
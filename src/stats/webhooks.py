@@ -0,0 +1,129 @@
+import re
+from typing import Optional
+
+import structlog
+from kubernetes import client
+
+from src.kube import get_api_client
+
+logger = structlog.get_logger()
+
+# Object creation rejected because the webhook could not be called, e.g.
+# 'Error creating: Internal error occurred: failed calling webhook "validate.nginx.ingress.kubernetes.io": ...'
+FAILED_CALL_PATTERN = re.compile(r'failed calling webhook "([^"]+)"', re.IGNORECASE)
+
+# Message fragment -> failure cause, most specific first
+FAILURE_CAUSES = [
+    ("context deadline exceeded", "timeout"),
+    ("timeout", "timeout"),
+    ("no endpoints available", "no_endpoints"),
+    ("connection refused", "unreachable"),
+    ("no such host", "unreachable"),
+    ("not found", "unreachable"),
+    ("x509", "tls"),
+    ("certificate", "tls"),
+]
+
+
+def _failure_cause(message: str) -> str:
+    """Classify why an admission webhook call failed from the event message."""
+    lowered = message.lower()
+    return next((cause for fragment, cause in FAILURE_CAUSES if fragment in lowered), "other")
+
+
+def _webhook_configurations() -> dict[str, dict]:
+    """Map webhook names to their configuration, failure policy and backing service.
+
+    Returns:
+        Dict keyed by webhook name; empty when the configurations cannot be listed
+    """
+    admission_v1 = client.AdmissionregistrationV1Api(get_api_client())
+    webhooks = {}
+    try:
+        configurations = [
+            ("Validating", item) for item in admission_v1.list_validating_webhook_configuration().items
+        ] + [
+            ("Mutating", item) for item in admission_v1.list_mutating_webhook_configuration().items
+        ]
+    except client.ApiException as e:
+        logger.warning("webhook_configurations_unavailable", status=e.status, source="stats")
+        return webhooks
+
+    for kind, configuration in configurations:
+        for webhook in configuration.webhooks or []:
+            service = webhook.client_config.service
+            webhooks[webhook.name] = {
+                "configuration": configuration.metadata.name,
+                "kind": kind,
+                "failure_policy": webhook.failure_policy or "Fail",
+                "service": f"{service.namespace}/{service.name}" if service else None,
+                "timeout_seconds": webhook.timeout_seconds,
+            }
+    return webhooks
+
+
+def _ready_endpoints(service: str) -> Optional[int]:
+    """Count the ready addresses behind a webhook service, None when unknown."""
+    namespace, name = service.split("/", 1)
+    try:
+        endpoints = client.CoreV1Api(get_api_client()).read_namespaced_endpoints(name, namespace)
+    except client.ApiException as e:
+        return 0 if e.status == 404 else None
+    return sum(len(subset.addresses or []) for subset in endpoints.subsets or [])
+
+
+def collect_webhook_failures(events: list) -> list[dict]:
+    """Flag admission webhooks that fail calls or have no ready backend.
+
+    A failing webhook with failurePolicy Fail silently blocks every create or
+    update it intercepts (FailedCreate on ReplicaSets and Jobs), which is hard
+    to spot from pod state alone.
+
+    Args:
+        events: Warning events of the cluster
+
+    Returns:
+        One entry per broken webhook (webhook, configuration, kind, failure
+        policy, service, ready endpoints, failed calls, cause, affected
+        namespaces), blocking ones first
+    """
+    failures: dict[str, dict] = {}
+    for event in events:
+        match = FAILED_CALL_PATTERN.search(event.message or "")
+        if not match:
+            continue
+        entry = failures.setdefault(match.group(1), {"failed_calls": 0, "causes": {}, "namespaces": set()})
+        entry["failed_calls"] += event.count or 1
+        cause = _failure_cause(event.message)
+        entry["causes"][cause] = entry["causes"].get(cause, 0) + (event.count or 1)
+        entry["namespaces"].add(event.metadata.namespace)
+
+    configurations = _webhook_configurations()
+
+    broken = []
+    for name in sorted(set(failures) | set(configurations)):
+        configuration = configurations.get(name, {})
+        failure = failures.get(name)
+        ready_endpoints = _ready_endpoints(configuration["service"]) if configuration.get("service") else None
+        if not failure and ready_endpoints != 0:
+            continue
+        broken.append({
+            "webhook": name,
+            "configuration": configuration.get("configuration"),
+            "kind": configuration.get("kind"),
+            "failure_policy": configuration.get("failure_policy"),
+            "service": configuration.get("service"),
+            "ready_endpoints": ready_endpoints,
+            "failed_calls": failure["failed_calls"] if failure else 0,
+            "cause": (
+                max(failure["causes"], key=failure["causes"].get) if failure else "no_endpoints"
+            ),
+            "namespaces": sorted(failure["namespaces"]) if failure else [],
+        })
+
+    broken.sort(key=lambda w: (w["failure_policy"] != "Fail", -w["failed_calls"], w["webhook"]))
+
+    if broken:
+        logger.info("admission_webhook_failures_collected", webhooks=len(broken), source="stats")
+
+    return broken
@@ -0,0 +1,145 @@
+import re
+from collections import Counter
+from typing import Optional
+
+from kubernetes import client
+
+from src.config import settings
+from src.reporter.heatmap import workload_name
+
+# Report order: batch churn last so it does not bury service regressions
+WORKLOAD_CLASSES = [
+    ("stateless", "Stateless services"),
+    ("stateful", "Stateful services"),
+    ("batch", "Batch jobs"),
+    ("system", "System workloads"),
+]
+
+# Jobs created by a CronJob are named <cronjob>-<scheduled time in minutes>
+CRONJOB_SUFFIX = re.compile(r"-\d{8,}$")
+
+MAX_WORKLOADS_PER_CLASS = 5
+
+
+def _owner_kind(pod: client.V1Pod) -> Optional[str]:
+    """Return the kind of the pod's controller (ReplicaSet, StatefulSet, Job...)."""
+    owners = pod.metadata.owner_references or []
+    owner = next((o for o in owners if o.controller), owners[0] if owners else None)
+    return owner.kind if owner else None
+
+
+def classify_pod(pod: client.V1Pod) -> str:
+    """Classify a pod's workload as system, batch, stateful or stateless.
+
+    System namespaces and system-* priority classes win over the owner kind;
+    Jobs are batch; StatefulSets and pods mounting a PersistentVolumeClaim are
+    stateful; everything else is stateless.
+
+    Args:
+        pod: Pod to classify
+
+    Returns:
+        Class key from WORKLOAD_CLASSES
+    """
+    if pod.metadata.namespace in settings.system_namespace_list or (
+        (pod.spec.priority_class_name or "").startswith("system-")
+    ):
+        return "system"
+    owner_kind = _owner_kind(pod)
+    if owner_kind in ("Job", "CronJob"):
+        return "batch"
+    if owner_kind == "StatefulSet" or any(v.persistent_volume_claim for v in pod.spec.volumes or []):
+        return "stateful"
+    return "stateless"
+
+
+def _workload(namespace: str, pod_name: str) -> str:
+    """Return namespace/workload, grouping the Jobs of a CronJob under the CronJob."""
+    return f"{namespace}/{CRONJOB_SUFFIX.sub('', workload_name(pod_name))}"
+
+
+def summarize_workload_classes(
+    pods: list[client.V1Pod],
+    events: list[client.CoreV1Event],
+    terminations: Optional[list[dict]] = None,
+) -> list[dict]:
+    """Summarize health per workload class.
+
+    Restarts come from the pod watcher when available, so crashes of Job pods
+    that are already gone still count; their class is taken from a current pod
+    of the same workload, or inferred as batch from a CronJob-style name;
+    other vanished pods are left out.
+
+    Args:
+        pods: In-scope pods of the cluster
+        events: In-scope warning events
+        terminations: Container terminations recorded by the pod watcher
+
+    Returns:
+        One entry per class with pods (class, label, workloads, pods, not
+        ready, failed, restarts, warnings and the workloads with most
+        restarts), in WORKLOAD_CLASSES order; classes without pods are omitted
+    """
+    pod_classes = {(pod.metadata.namespace, pod.metadata.name): classify_pod(pod) for pod in pods}
+    workload_classes = {
+        _workload(namespace, name): pod_class for (namespace, name), pod_class in pod_classes.items()
+    }
+
+    def class_of(namespace: str, pod_name: str) -> Optional[str]:
+        known = pod_classes.get((namespace, pod_name)) or workload_classes.get(_workload(namespace, pod_name))
+        if known:
+            return known
+        if namespace in settings.system_namespace_list:
+            return "system"
+        return "batch" if CRONJOB_SUFFIX.search(workload_name(pod_name)) else None
+
+    summary = {
+        key: {"workloads": set(), "pods": 0, "not_ready": 0, "failed": 0, "restarts": Counter(), "warnings": 0}
+        for key, _ in WORKLOAD_CLASSES
+    }
+
+    for pod in pods:
+        entry = summary[pod_classes[(pod.metadata.namespace, pod.metadata.name)]]
+        entry["workloads"].add(_workload(pod.metadata.namespace, pod.metadata.name))
+        entry["pods"] += 1
+        if pod.status.phase == "Failed":
+            entry["failed"] += 1
+        elif pod.status.phase == "Running":
+            ready = next((c for c in pod.status.conditions or [] if c.type == "Ready"), None)
+            if not (ready and ready.status == "True"):
+                entry["not_ready"] += 1
+        if terminations is None:
+            restarts = sum(cs.restart_count for cs in pod.status.container_statuses or [])
+            if restarts:
+                entry["restarts"][_workload(pod.metadata.namespace, pod.metadata.name)] += restarts
+
+    for termination in terminations or []:
+        pod_class = class_of(termination["namespace"], termination["pod"])
+        if pod_class:
+            summary[pod_class]["restarts"][_workload(termination["namespace"], termination["pod"])] += 1
+
+    for event in events:
+        if event.involved_object.kind != "Pod":
+            continue
+        pod_class = class_of(event.metadata.namespace, event.involved_object.name)
+        if pod_class:
+            summary[pod_class]["warnings"] += event.count or 1
+
+    return [
+        {
+            "class": key,
+            "label": label,
+            "workloads": len(summary[key]["workloads"]),
+            "pods": summary[key]["pods"],
+            "not_ready": summary[key]["not_ready"],
+            "failed": summary[key]["failed"],
+            "restarts": sum(summary[key]["restarts"].values()),
+            "warnings": summary[key]["warnings"],
+            "top_restarts": [
+                {"workload": workload, "restarts": count}
+                for workload, count in summary[key]["restarts"].most_common(MAX_WORKLOADS_PER_CLASS)
+            ],
+        }
+        for key, label in WORKLOAD_CLASSES
+        if summary[key]["pods"]
+    ]
@@ -1,13 +1,48 @@
+import asyncio
+import hashlib
+import json
+import os
+import shutil
+import sqlite3
+import threading
+import weakref
+import zlib
+
 import aiosqlite
 import structlog
+from contextlib import asynccontextmanager
 from datetime import datetime, timedelta
 from pathlib import Path
-from typing import Optional
+from typing import AsyncIterator, Optional
 
 from src.config import settings
 
 logger = structlog.get_logger()
 
+# Over the size quota, history older than this is expired before reports are thinned
+# (reports only look back one week); pod details are dropped after a day
+QUOTA_HISTORY_DAYS = 7
+QUOTA_POD_DETAILS_DAYS = 1
+
+# A delivery claimed before its report was stored is taken over after this long,
+# so a worker that died mid-generation does not block the period
+PENDING_DELIVERY_CLAIM_HOURS = 2
+
+# Every open SQLite connection of the process (API, worker threads, watchers),
+# so a shutdown can interrupt long queries instead of waiting for them
+_open_connections: "weakref.WeakSet[sqlite3.Connection]" = weakref.WeakSet()
+_connections_lock = threading.Lock()
+_shutting_down = threading.Event()
+
+
+class _TrackedConnection(sqlite3.Connection):
+    """sqlite3 connection that registers itself for interruption on shutdown."""
+
+    def __init__(self, *args, **kwargs) -> None:
+        super().__init__(*args, **kwargs)
+        with _connections_lock:
+            _open_connections.add(self)
+
 
 class ReportStorage:
     """Manages report storage in SQLite database."""
@@ -19,13 +54,71 @@ class ReportStorage:
             db_path: Path to SQLite database file. Uses settings if not provided.
         """
         self.db_path = db_path or settings.sqlite_path
+        self.backup_path = f"{self.db_path}.bak"
         Path(self.db_path).parent.mkdir(parents=True, exist_ok=True)
 
         logger.info("report_storage_initialized", db_path=self.db_path)
 
+    @asynccontextmanager
+    async def _connect(self, db_path: Optional[str] = None) -> AsyncIterator[aiosqlite.Connection]:
+        """Open a connection to the database.
+
+        Each call gets its own connection (aiosqlite has no pool); SQLite
+        allows a single writer, so concurrent writers from the API, the worker
+        thread and the watchers wait up to SQLITE_BUSY_TIMEOUT seconds for the
+        lock instead of failing with "database is locked". When the awaiting
+        task is cancelled, the running query is interrupted so the connection
+        can close right away.
+
+        Args:
+            db_path: Database file (defaults to the live database)
+
+        Raises:
+            sqlite3.OperationalError: When the application is shutting down
+        """
+        if _shutting_down.is_set():
+            raise sqlite3.OperationalError("storage is shutting down")
+
+        async with aiosqlite.connect(
+            db_path or self.db_path,
+            timeout=settings.sqlite_busy_timeout,
+            factory=_TrackedConnection,
+        ) as db:
+            try:
+                yield db
+            except asyncio.CancelledError:
+                await db.interrupt()
+                raise
+
+    @staticmethod
+    def interrupt_all() -> int:
+        """Refuse new connections and interrupt the queries running on open ones.
+
+        Called on shutdown so a long query (purge, downsampling, integrity
+        check) in the worker thread cannot delay the exit; the interrupted
+        call fails with sqlite3.OperationalError("interrupted").
+
+        Returns:
+            Number of connections interrupted
+        """
+        _shutting_down.set()
+        with _connections_lock:
+            connections = list(_open_connections)
+        for connection in connections:
+            try:
+                connection.interrupt()
+            except sqlite3.ProgrammingError:
+                pass  # Already closed
+        if connections:
+            logger.info("storage_queries_interrupted", connections=len(connections))
+        return len(connections)
+
     async def initialize(self) -> None:
         """Initialize database schema."""
-        async with aiosqlite.connect(self.db_path) as db:
+        async with self._connect() as db:
+            # WAL lets API reads proceed while the worker writes a report (persistent setting)
+            await db.execute("PRAGMA journal_mode=WAL")
+
             # Reports table
             await db.execute("""
                 CREATE TABLE IF NOT EXISTS reports (
@@ -43,6 +136,44 @@ class ReportStorage:
                 ON reports(cluster_name, generated_at DESC)
             """)
 
+            # Columns added after the initial schema
+            await self._ensure_column(db, "reports", "model", "TEXT")
+            await self._ensure_column(db, "reports", "cost_usd", "REAL")
+            await self._ensure_column(db, "reports", "metadata", "TEXT")
+            await self._ensure_column(db, "reports", "status", "TEXT DEFAULT 'published'")
+
+            # Cost of every LLM call (reports, dry runs, rollups, failed attempts) for the monthly budget
+            async with db.execute(
+                "SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'llm_spend'"
+            ) as cursor:
+                spend_ledger_exists = await cursor.fetchone() is not None
+            await db.execute("""
+                CREATE TABLE IF NOT EXISTS llm_spend (
+                    id INTEGER PRIMARY KEY AUTOINCREMENT,
+                    cluster_name TEXT NOT NULL,
+                    recorded_at TIMESTAMP NOT NULL,
+                    job_id INTEGER,
+                    purpose TEXT NOT NULL,
+                    model TEXT,
+                    cost_usd REAL NOT NULL,
+                    input_tokens INTEGER,
+                    output_tokens INTEGER
+                )
+            """)
+
+            await db.execute("""
+                CREATE INDEX IF NOT EXISTS idx_llm_spend_cluster_recorded
+                ON llm_spend(cluster_name, recorded_at)
+            """)
+            if not spend_ledger_exists:
+                # Spend was summed from reports before the ledger existed
+                await db.execute("""
+                    INSERT INTO llm_spend (cluster_name, recorded_at, purpose, model, cost_usd)
+                    SELECT cluster_name, generated_at, 'report', model, cost_usd
+                    FROM reports
+                    WHERE cost_usd > 0
+                """)
+
             # Jobs table for queue system
             await db.execute("""
                 CREATE TABLE IF NOT EXISTS jobs (
@@ -63,31 +194,299 @@ class ReportStorage:
                 CREATE INDEX IF NOT EXISTS idx_jobs_status_created
                 ON jobs(status, created_at ASC)
             """)
+            # Seconds per pipeline stage (collection, prompt_build, llm, pdf_render, delivery)
+            await self._ensure_column(db, "jobs", "stage_timings", "TEXT")
+
+            # Pod transitions recorded by the pod watcher
+            await db.execute("""
+                CREATE TABLE IF NOT EXISTS pod_transitions (
+                    id INTEGER PRIMARY KEY AUTOINCREMENT,
+                    cluster_name TEXT NOT NULL,
+                    namespace TEXT NOT NULL,
+                    pod TEXT NOT NULL,
+                    container TEXT,
+                    transition_type TEXT NOT NULL,
+                    from_phase TEXT,
+                    to_phase TEXT,
+                    reason TEXT,
+                    exit_code INTEGER,
+                    signal INTEGER,
+                    observed_at TIMESTAMP NOT NULL,
+                    details TEXT
+                )
+            """)
+
+            await db.execute("""
+                CREATE INDEX IF NOT EXISTS idx_pod_transitions_cluster_observed
+                ON pod_transitions(cluster_name, observed_at DESC)
+            """)
+            await self._ensure_column(db, "pod_transitions", "signal", "INTEGER")
+            # Describe-like detail of unhealthy pods (JSON), only on rate-limited rows
+            await self._ensure_column(db, "pod_transitions", "details", "TEXT")
+
+            # Periodic samples of watched workloads (finer-grained than weekly reports)
+            await db.execute("""
+                CREATE TABLE IF NOT EXISTS workload_samples (
+                    id INTEGER PRIMARY KEY AUTOINCREMENT,
+                    cluster_name TEXT NOT NULL,
+                    namespace TEXT NOT NULL,
+                    kind TEXT NOT NULL,
+                    name TEXT NOT NULL,
+                    desired INTEGER,
+                    ready INTEGER,
+                    restarts INTEGER,
+                    sampled_at TIMESTAMP NOT NULL
+                )
+            """)
+
+            await db.execute("""
+                CREATE INDEX IF NOT EXISTS idx_workload_samples_cluster_sampled
+                ON workload_samples(cluster_name, sampled_at DESC)
+            """)
+
+            # Active HTTP checks of configured endpoints, one row per request
+            await db.execute("""
+                CREATE TABLE IF NOT EXISTS endpoint_probes (
+                    id INTEGER PRIMARY KEY AUTOINCREMENT,
+                    cluster_name TEXT NOT NULL,
+                    name TEXT NOT NULL,
+                    url TEXT NOT NULL,
+                    probed_at TIMESTAMP NOT NULL,
+                    ok INTEGER NOT NULL,
+                    status_code INTEGER,
+                    latency_ms REAL,
+                    error TEXT
+                )
+            """)
+
+            await db.execute("""
+                CREATE INDEX IF NOT EXISTS idx_endpoint_probes_cluster_probed
+                ON endpoint_probes(cluster_name, probed_at DESC)
+            """)
+
+            # Workload dependency graph inferred before each report
+            await db.execute("""
+                CREATE TABLE IF NOT EXISTS workload_dependencies (
+                    id INTEGER PRIMARY KEY AUTOINCREMENT,
+                    cluster_name TEXT NOT NULL,
+                    namespace TEXT NOT NULL,
+                    workload TEXT NOT NULL,
+                    kind TEXT NOT NULL,
+                    target_namespace TEXT NOT NULL,
+                    target_service TEXT NOT NULL,
+                    target_workload TEXT,
+                    via TEXT,
+                    observed_at TIMESTAMP NOT NULL
+                )
+            """)
+
+            await db.execute("""
+                CREATE INDEX IF NOT EXISTS idx_workload_dependencies_cluster
+                ON workload_dependencies(cluster_name, target_namespace, target_workload)
+            """)
+
+            # Recommendations issued in reports, tracked until acted on
+            await db.execute("""
+                CREATE TABLE IF NOT EXISTS recommendations (
+                    id INTEGER PRIMARY KEY AUTOINCREMENT,
+                    cluster_name TEXT NOT NULL,
+                    fingerprint TEXT NOT NULL,
+                    category TEXT,
+                    namespace TEXT,
+                    target TEXT,
+                    action TEXT NOT NULL,
+                    status TEXT NOT NULL DEFAULT 'open',
+                    first_report_id INTEGER,
+                    last_report_id INTEGER,
+                    first_seen TIMESTAMP NOT NULL,
+                    last_seen TIMESTAMP NOT NULL,
+                    resolved_at TIMESTAMP,
+                    evidence TEXT
+                )
+            """)
+
+            await db.execute("""
+                CREATE INDEX IF NOT EXISTS idx_recommendations_cluster_status
+                ON recommendations(cluster_name, status, fingerprint)
+            """)
+
+            # One delivered report per idempotency key (cluster + period)
+            await db.execute("""
+                CREATE TABLE IF NOT EXISTS report_deliveries (
+                    cluster_name TEXT NOT NULL,
+                    idempotency_key TEXT NOT NULL,
+                    report_id INTEGER,
+                    claimed_at TIMESTAMP NOT NULL,
+                    PRIMARY KEY (cluster_name, idempotency_key)
+                )
+            """)
+
+            # Every attempt to deliver a report, per destination (channel, DMs, reviewers...)
+            await db.execute("""
+                CREATE TABLE IF NOT EXISTS delivery_attempts (
+                    id INTEGER PRIMARY KEY AUTOINCREMENT,
+                    cluster_name TEXT NOT NULL,
+                    report_id INTEGER NOT NULL,
+                    attempted_at TIMESTAMP NOT NULL,
+                    destination TEXT NOT NULL,
+                    format TEXT,
+                    size_bytes INTEGER,
+                    ok INTEGER NOT NULL,
+                    error TEXT
+                )
+            """)
+
+            await db.execute("""
+                CREATE INDEX IF NOT EXISTS idx_delivery_attempts_cluster_attempted
+                ON delivery_attempts(cluster_name, attempted_at DESC)
+            """)
+
+            # Notifications whose delivery failed, retried with backoff by the outbox sender
+            await db.execute("""
+                CREATE TABLE IF NOT EXISTS outbox (
+                    id INTEGER PRIMARY KEY AUTOINCREMENT,
+                    cluster_name TEXT NOT NULL,
+                    report_id INTEGER NOT NULL,
+                    kind TEXT NOT NULL,
+                    status TEXT NOT NULL DEFAULT 'pending',
+                    attempts INTEGER NOT NULL DEFAULT 0,
+                    last_error TEXT,
+                    created_at TIMESTAMP NOT NULL,
+                    next_attempt_at TIMESTAMP NOT NULL,
+                    sent_at TIMESTAMP
+                )
+            """)
+
+            await db.execute("""
+                CREATE INDEX IF NOT EXISTS idx_outbox_status_next
+                ON outbox(status, next_attempt_at ASC)
+            """)
+
+            # Findings tracked across reports by fingerprint, so chronic issues get an age
+            await db.execute("""
+                CREATE TABLE IF NOT EXISTS findings (
+                    id INTEGER PRIMARY KEY AUTOINCREMENT,
+                    cluster_name TEXT NOT NULL,
+                    fingerprint TEXT NOT NULL,
+                    kind TEXT,
+                    namespace TEXT,
+                    resource TEXT,
+                    reason TEXT,
+                    severity TEXT,
+                    title TEXT,
+                    status TEXT NOT NULL DEFAULT 'open',
+                    occurrences INTEGER NOT NULL DEFAULT 1,
+                    first_report_id INTEGER,
+                    last_report_id INTEGER,
+                    first_seen TIMESTAMP NOT NULL,
+                    last_seen TIMESTAMP NOT NULL,
+                    closed_at TIMESTAMP
+                )
+            """)
+
+            await db.execute("""
+                CREATE INDEX IF NOT EXISTS idx_findings_cluster_status
+                ON findings(cluster_name, status, fingerprint)
+            """)
+
+            # Who triggered reports, reviewed them or changed data through the API
+            await db.execute("""
+                CREATE TABLE IF NOT EXISTS audit_log (
+                    id INTEGER PRIMARY KEY AUTOINCREMENT,
+                    cluster_name TEXT NOT NULL,
+                    occurred_at TIMESTAMP NOT NULL,
+                    actor TEXT NOT NULL,
+                    action TEXT NOT NULL,
+                    target TEXT,
+                    client_ip TEXT,
+                    details TEXT
+                )
+            """)
+
+            await db.execute("""
+                CREATE INDEX IF NOT EXISTS idx_audit_log_cluster_occurred
+                ON audit_log(cluster_name, occurred_at DESC)
+            """)
+
+            # Full JSON of unhealthy pods/nodes at snapshot time, zlib-compressed, for postmortems
+            await db.execute("""
+                CREATE TABLE IF NOT EXISTS raw_objects (
+                    id INTEGER PRIMARY KEY AUTOINCREMENT,
+                    cluster_name TEXT NOT NULL,
+                    report_id INTEGER NOT NULL,
+                    captured_at TIMESTAMP NOT NULL,
+                    kind TEXT NOT NULL,
+                    namespace TEXT,
+                    name TEXT NOT NULL,
+                    reason TEXT,
+                    object BLOB NOT NULL
+                )
+            """)
+
+            await db.execute("""
+                CREATE INDEX IF NOT EXISTS idx_raw_objects_report
+                ON raw_objects(report_id)
+            """)
 
             await db.commit()
 
         logger.info("database_initialized")
 
-    async def save_report(self, html_content: str) -> int:
+    async def _ensure_column(
+        self, db: aiosqlite.Connection, table: str, column: str, definition: str
+    ) -> None:
+        """Add a column to an existing table if it is not there yet.
+
+        Args:
+            db: Open database connection
+            table: Table name
+            column: Column name
+            definition: SQL type/definition for the column
+        """
+        async with db.execute(f"PRAGMA table_info({table})") as cursor:
+            columns = [row[1] for row in await cursor.fetchall()]
+
+        if column not in columns:
+            await db.execute(f"ALTER TABLE {table} ADD COLUMN {column} {definition}")
+            logger.info("database_column_added", table=table, column=column)
+
+    async def save_report(
+        self,
+        html_content: str,
+        metadata: Optional[dict] = None,
+        status: str = "published",
+    ) -> int:
         """Save a generated report.
 
         Args:
             html_content: HTML report content
+            metadata: Optional generation metadata (model, cost, routing, stats...)
+            status: Delivery status ('published', 'pending_review' or 'collected'
+                for collect-only snapshots)
 
         Returns:
             Report ID
         """
-        async with aiosqlite.connect(self.db_path) as db:
+        metadata = metadata or {}
+
+        async with self._connect() as db:
             cursor = await db.execute(
                 """
-                INSERT INTO reports (cluster_name, generated_at, report_html, report_size)
-                VALUES (?, ?, ?, ?)
+                INSERT INTO reports (
+                    cluster_name, generated_at, report_html, report_size,
+                    model, cost_usd, metadata, status
+                )
+                VALUES (?, ?, ?, ?, ?, ?, ?, ?)
                 """,
                 (
                     settings.cluster_name,
                     datetime.now().isoformat(),
                     html_content,
                     len(html_content),
+                    metadata.get("model"),
+                    metadata.get("total_cost_usd"),
+                    json.dumps(metadata, default=str),
+                    status,
                 ),
             )
             await db.commit()
@@ -108,7 +507,7 @@ class ReportStorage:
         Returns:
             Report dict or None if no reports exist
         """
-        async with aiosqlite.connect(self.db_path) as db:
+        async with self._connect() as db:
             db.row_factory = aiosqlite.Row
             async with db.execute(
                 """
@@ -126,119 +525,1878 @@ class ReportStorage:
 
         return None
 
-    async def cleanup_old_reports(self) -> int:
-        """Remove reports older than retention period.
+    async def get_report(self, report_id: int) -> Optional[dict]:
+        """Get a report by ID, including its parsed metadata.
+
+        Args:
+            report_id: Report ID
 
         Returns:
-            Number of reports deleted
+            Report dict or None if it does not exist
         """
-        cutoff_date = datetime.now() - timedelta(weeks=settings.retention_weeks)
+        async with self._connect() as db:
+            db.row_factory = aiosqlite.Row
+            async with db.execute(
+                """
+                SELECT id, cluster_name, generated_at, report_html, report_size,
+                       metadata, status
+                FROM reports
+                WHERE id = ? AND cluster_name = ?
+                """,
+                (report_id, settings.cluster_name),
+            ) as cursor:
+                row = await cursor.fetchone()
+
+        if not row:
+            return None
+
+        report = dict(row)
+        try:
+            report["metadata"] = json.loads(report["metadata"] or "{}")
+        except json.JSONDecodeError:
+            report["metadata"] = {}
 
-        async with aiosqlite.connect(self.db_path) as db:
+        return report
+
+    async def transition_report_status(
+        self, report_id: int, from_status: str, to_status: str
+    ) -> bool:
+        """Move a report between delivery states.
+
+        The update only applies if the report is still in `from_status`, so
+        concurrent approvals (e.g., a double click) cannot publish twice.
+
+        Args:
+            report_id: Report ID
+            from_status: Expected current status
+            to_status: New status
+
+        Returns:
+            True if the transition was applied
+        """
+        async with self._connect() as db:
             cursor = await db.execute(
                 """
-                DELETE FROM reports
-                WHERE cluster_name = ? AND generated_at < ?
+                UPDATE reports
+                SET status = ?
+                WHERE id = ? AND cluster_name = ? AND status = ?
                 """,
-                (settings.cluster_name, cutoff_date.isoformat()),
+                (to_status, report_id, settings.cluster_name, from_status),
             )
             await db.commit()
-            deleted_count = cursor.rowcount
+            applied = cursor.rowcount == 1
 
         logger.info(
-            "old_reports_cleaned",
-            deleted_count=deleted_count,
-            cutoff_date=cutoff_date.isoformat(),
+            "report_status_transition",
+            report_id=report_id,
+            from_status=from_status,
+            to_status=to_status,
+            applied=applied,
         )
 
-        return deleted_count
+        return applied
 
-    async def get_report_stats(self) -> dict:
-        """Get statistics about stored reports.
+    async def get_recent_report_metadata(
+        self, limit: int = 8, since: Optional[datetime] = None
+    ) -> list[dict]:
+        """Get the metadata of the most recent weekly reports, newest first.
+
+        Namespace deep-dives and collect-only snapshots are skipped so trends
+        compare like with like.
+
+        Args:
+            limit: Maximum number of reports
+            since: Only include reports generated after this time
 
         Returns:
-            Dict with report statistics
+            List of dicts with id, generated_at and parsed metadata
         """
-        async with aiosqlite.connect(self.db_path) as db:
+        async with self._connect() as db:
+            db.row_factory = aiosqlite.Row
             async with db.execute(
                 """
-                SELECT
-                    COUNT(*) as total_reports,
-                    SUM(report_size) as total_size,
-                    MAX(generated_at) as latest_report_date,
-                    MIN(generated_at) as oldest_report_date
+                SELECT id, generated_at, metadata
                 FROM reports
                 WHERE cluster_name = ?
+                  AND metadata IS NOT NULL
+                  AND json_extract(metadata, '$.scope') IS NULL
+                  AND COALESCE(status, 'published') != 'collected'
+                  AND generated_at >= ?
+                ORDER BY generated_at DESC
+                LIMIT ?
                 """,
-                (settings.cluster_name,),
+                (settings.cluster_name, since.isoformat() if since else "", limit),
             ) as cursor:
-                row = await cursor.fetchone()
+                rows = await cursor.fetchall()
 
-                return {
-                    "total_reports": row[0] or 0,
-                    "total_size_bytes": row[1] or 0,
-                    "latest_report_date": row[2],
-                    "oldest_report_date": row[3],
-                }
+        result = []
+        for row in rows:
+            try:
+                metadata = json.loads(row["metadata"])
+            except json.JSONDecodeError:
+                continue
+            result.append({"id": row["id"], "generated_at": row["generated_at"], "metadata": metadata})
 
-    # Job queue methods
+        return result
 
-    async def insert_job(self, job_type: str, payload: Optional[str] = None) -> int:
-        """Insert a new job into the queue.
+    async def cleanup_old_reports(self) -> int:
+        """Remove reports older than retention period.
+
+        Returns:
+            Number of reports deleted
+        """
+        cutoff_date = datetime.now() - timedelta(weeks=settings.retention_weeks)
+
+        async with self._connect() as db:
+            cursor = await db.execute(
+                """
+                DELETE FROM reports
+                WHERE cluster_name = ? AND generated_at < ?
+                """,
+                (settings.cluster_name, cutoff_date.isoformat()),
+            )
+            deleted_count = cursor.rowcount
+            await self._delete_orphaned_report_rows(db)
+            # The current month's spend is kept for the budget whatever the retention
+            month_start = datetime.now().replace(day=1, hour=0, minute=0, second=0, microsecond=0)
+            await db.execute(
+                "DELETE FROM llm_spend WHERE cluster_name = ? AND recorded_at < ?",
+                (settings.cluster_name, min(cutoff_date, month_start).isoformat()),
+            )
+            await db.commit()
+
+        logger.info(
+            "old_reports_cleaned",
+            deleted_count=deleted_count,
+            cutoff_date=cutoff_date.isoformat(),
+        )
+
+        return deleted_count
+
+    async def record_llm_spend(self, calls: list[dict], purpose: str, job_id: Optional[int] = None) -> float:
+        """Record the cost of LLM calls in the spend ledger.
 
         Args:
-            job_type: Type of job to process (e.g., 'generate_report')
-            payload: Optional JSON payload with job data
+            calls: K8sWatchdogAgent.calls entries (model, cost_usd, input_tokens, output_tokens)
+            purpose: What the calls were for (report, dry_run, deep_dive, rollup)
+            job_id: Job that made the calls
 
         Returns:
-            Job ID
+            Total cost recorded in USD
         """
-        async with aiosqlite.connect(self.db_path) as db:
-            cursor = await db.execute(
+        if not calls:
+            return 0.0
+
+        async with self._connect() as db:
+            await db.executemany(
                 """
-                INSERT INTO jobs (type, status, payload)
-                VALUES (?, 'pending', ?)
+                INSERT INTO llm_spend (
+                    cluster_name, recorded_at, job_id, purpose, model, cost_usd, input_tokens, output_tokens
+                )
+                VALUES (?, ?, ?, ?, ?, ?, ?, ?)
                 """,
-                (job_type, payload),
+                [
+                    (
+                        settings.cluster_name,
+                        call["recorded_at"],
+                        job_id,
+                        purpose,
+                        call["model"],
+                        call["cost_usd"],
+                        call["input_tokens"],
+                        call["output_tokens"],
+                    )
+                    for call in calls
+                ],
             )
             await db.commit()
-            job_id = cursor.lastrowid
 
-        logger.info("job_inserted", job_id=job_id, job_type=job_type, source="queue")
+        return sum(call["cost_usd"] for call in calls)
 
-        return job_id
+    async def get_spend_since(self, since: datetime) -> float:
+        """Sum the cost of every LLM call made since a given date.
 
-    async def get_pending_job(self) -> Optional[dict]:
-        """Get the next pending job from the queue.
+        Dry runs, rollups and attempts that produced no report count too, and
+        deleting or downsampling reports does not lower the spend.
+
+        Args:
+            since: Start of the period
 
         Returns:
-            Job dict or None if no pending jobs exist
+            Total cost in USD
         """
-        async with aiosqlite.connect(self.db_path) as db:
-            db.row_factory = aiosqlite.Row
+        async with self._connect() as db:
             async with db.execute(
                 """
-                SELECT id, type, status, payload, created_at, retry_count
-                FROM jobs
-                WHERE status = 'pending'
-                ORDER BY created_at ASC
-                LIMIT 1
+                SELECT COALESCE(SUM(cost_usd), 0)
+                FROM llm_spend
+                WHERE cluster_name = ? AND recorded_at >= ?
                 """,
+                (settings.cluster_name, since.isoformat()),
             ) as cursor:
                 row = await cursor.fetchone()
-                if row:
-                    return dict(row)
 
-        return None
+        return float(row[0])
 
-    async def update_job_status(
-        self,
-        job_id: int,
-        status: str,
-        result: Optional[str] = None,
-        error: Optional[str] = None,
-    ) -> None:
-        """Update job status and result.
+    def get_database_size(self) -> int:
+        """Return the current size of the SQLite database in bytes, WAL and shared memory files included."""
+        size = 0
+        for path in (self.db_path, f"{self.db_path}-wal", f"{self.db_path}-shm"):
+            try:
+                size += os.path.getsize(path)
+            except OSError:
+                pass
+        return size
+
+    async def _expire_history_for_quota(self, db: aiosqlite.Connection) -> None:
+        """Drop the history that reports no longer read, before touching reports.
+
+        Removes pod details older than QUOTA_POD_DETAILS_DAYS, pod transitions,
+        workload samples and probe results, sent or abandoned outbox entries and
+        finished jobs older than QUOTA_HISTORY_DAYS. Pending outbox entries are kept.
+
+        Args:
+            db: Open database connection
+        """
+        history_cutoff = (datetime.now() - timedelta(days=QUOTA_HISTORY_DAYS)).isoformat()
+        details_cutoff = (datetime.now() - timedelta(days=QUOTA_POD_DETAILS_DAYS)).isoformat()
+
+        await db.execute(
+            """
+            UPDATE pod_transitions SET details = NULL
+            WHERE cluster_name = ? AND observed_at < ? AND details IS NOT NULL
+            """,
+            (settings.cluster_name, details_cutoff),
+        )
+        for table, column in (
+            ("pod_transitions", "observed_at"),
+            ("workload_samples", "sampled_at"),
+            ("endpoint_probes", "probed_at"),
+        ):
+            await db.execute(
+                f"DELETE FROM {table} WHERE cluster_name = ? AND {column} < ?",
+                (settings.cluster_name, history_cutoff),
+            )
+        # Pending entries are deliveries still owed, whatever their age
+        await db.execute(
+            "DELETE FROM outbox WHERE cluster_name = ? AND status IN ('sent', 'failed') AND created_at < ?",
+            (settings.cluster_name, history_cutoff),
+        )
+        # Jobs are not per cluster; finished ones only serve GET /jobs/{id} and timings
+        await db.execute(
+            "DELETE FROM jobs WHERE status IN ('completed', 'failed') AND completed_at < ?",
+            (history_cutoff,),
+        )
+
+    async def enforce_size_quota(self) -> list[int]:
+        """Free space when the database exceeds its size quota.
+
+        The first pass expires history older than a week (pod transitions and
+        their details, workload samples, probe results, finished outbox entries
+        and jobs). Further passes keep the newest report and one of every
+        `downsample_keep_every` older reports. Each pass vacuums the file and
+        truncates the WAL so the space is actually released; the loop stops
+        when the database fits, nothing is left to remove, or a pass frees
+        nothing.
+
+        Returns:
+            IDs of the reports that were deleted
+        """
+        max_size = settings.max_database_size_mb * 1024 * 1024
+        if max_size <= 0:
+            return []
+
+        removed_ids: list[int] = []
+        history_expired = False
+
+        while (size_before := self.get_database_size()) > max_size:
+            to_delete: list[int] = []
+            async with self._connect() as db:
+                if not history_expired:
+                    await self._expire_history_for_quota(db)
+                else:
+                    async with db.execute(
+                        """
+                        SELECT id FROM reports
+                        WHERE cluster_name = ?
+                        ORDER BY generated_at DESC
+                        """,
+                        (settings.cluster_name,),
+                    ) as cursor:
+                        report_ids = [row[0] for row in await cursor.fetchall()]
+
+                    # Never touch the newest report; keep 1 of N among the rest
+                    older_ids = report_ids[1:]
+                    to_delete = [
+                        report_id
+                        for index, report_id in enumerate(older_ids)
+                        if index % max(settings.downsample_keep_every, 1) != 0
+                    ]
+                    if not to_delete and older_ids:
+                        # Nothing left to thin out: drop the oldest report
+                        to_delete = older_ids[-1:]
+
+                    if not to_delete:
+                        break
+
+                    placeholders = ",".join("?" for _ in to_delete)
+                    await db.execute(
+                        f"DELETE FROM reports WHERE id IN ({placeholders})", to_delete
+                    )
+                    await self._delete_orphaned_report_rows(db)
+
+                await db.commit()
+                await db.execute("VACUUM")
+                # VACUUM goes through the WAL, which would otherwise keep the freed size
+                await db.execute("PRAGMA wal_checkpoint(TRUNCATE)")
+
+            removed_ids.extend(to_delete)
+
+            # Expiring history may free nothing and still leave reports to thin out
+            if history_expired and self.get_database_size() >= size_before:
+                logger.warning(
+                    "database_quota_not_reducible",
+                    database_size_bytes=size_before,
+                    max_size_bytes=max_size,
+                )
+                break
+            history_expired = True
+
+        if removed_ids:
+            logger.warning(
+                "database_quota_downsampled",
+                removed_report_ids=removed_ids,
+                database_size_bytes=self.get_database_size(),
+                max_size_bytes=max_size,
+            )
+
+        return removed_ids
+
+    async def check_integrity(self, db_path: Optional[str] = None) -> list[str]:
+        """Run PRAGMA integrity_check on the database.
+
+        Args:
+            db_path: Database file to check (defaults to the live database)
+
+        Returns:
+            List of problems reported by SQLite (empty when the file is healthy)
+        """
+        try:
+            async with self._connect(db_path) as db:
+                async with db.execute("PRAGMA integrity_check") as cursor:
+                    rows = [row[0] for row in await cursor.fetchall()]
+        except sqlite3.DatabaseError as e:
+            # e.g., "file is not a database" when the header itself is damaged
+            return [str(e)]
+
+        return [] if rows == ["ok"] else rows
+
+    async def backup(self) -> str:
+        """Copy the database to the backup file using SQLite's online backup API.
+
+        Returns:
+            Backup file path
+        """
+        async with self._connect() as source:
+            async with self._connect(self.backup_path) as target:
+                await source.backup(target)
+
+        logger.info("database_backed_up", backup_path=self.backup_path)
+
+        return self.backup_path
+
+    async def recover(self) -> str:
+        """Replace a corrupted database with the latest backup or a fresh schema.
+
+        The corrupted file is kept next to the database for inspection.
+
+        Returns:
+            'restored_from_backup' or 'rebuilt_schema'
+        """
+        corrupt_path = f"{self.db_path}.corrupt-{datetime.now().strftime('%Y%m%d%H%M%S')}"
+        if os.path.exists(self.db_path):
+            os.replace(self.db_path, corrupt_path)
+        for suffix in ("-journal", "-wal", "-shm"):
+            if os.path.exists(self.db_path + suffix):
+                os.remove(self.db_path + suffix)
+
+        outcome = "rebuilt_schema"
+        if os.path.exists(self.backup_path) and not await self.check_integrity(self.backup_path):
+            shutil.copyfile(self.backup_path, self.db_path)
+            outcome = "restored_from_backup"
+
+        await self.initialize()
+
+        logger.warning(
+            "database_recovered",
+            outcome=outcome,
+            corrupt_path=corrupt_path,
+        )
+
+        return outcome
+
+    async def purge(
+        self,
+        cluster_name: str,
+        namespace: Optional[str] = None,
+        before: Optional[datetime] = None,
+        after: Optional[datetime] = None,
+    ) -> dict[str, int]:
+        """Delete stored data for a cluster, optionally limited to a namespace and time range.
+
+        Namespace purges remove namespace-scoped rows (pod transitions, dependency
+        edges, recommendations, findings, archived objects and namespace
+        deep-dive reports).
+        Recommendations and findings first seen in deleted reports are removed
+        with them. The file is vacuumed so
+        the data is gone from disk, not only unlinked.
+
+        Args:
+            cluster_name: Cluster whose data is deleted
+            namespace: Only delete data about this namespace
+            before: Only delete data recorded before this time
+            after: Only delete data recorded at or after this time
+
+        Returns:
+            Number of deleted rows per table
+        """
+        def time_filter(column: str) -> tuple[str, list]:
+            clauses, params = "", []
+            if before:
+                clauses += f" AND {column} < ?"
+                params.append(before.isoformat())
+            if after:
+                clauses += f" AND {column} >= ?"
+                params.append(after.isoformat())
+            return clauses, params
+
+        deleted: dict[str, int] = {}
+
+        async with self._connect() as db:
+            where, params = time_filter("generated_at")
+            if namespace:
+                where += " AND json_extract(metadata, '$.scope.namespace') = ?"
+                params.append(namespace)
+            async with db.execute(
+                f"SELECT id FROM reports WHERE cluster_name = ?{where}",
+                [cluster_name, *params],
+            ) as cursor:
+                report_ids = [row[0] for row in await cursor.fetchall()]
+
+            placeholders = ",".join("?" for _ in report_ids)
+            if report_ids:
+                await db.execute(f"DELETE FROM reports WHERE id IN ({placeholders})", report_ids)
+            deleted["reports"] = len(report_ids)
+            if report_ids:
+                await db.execute(
+                    f"DELETE FROM report_deliveries WHERE report_id IN ({placeholders})", report_ids
+                )
+                await db.execute(f"DELETE FROM outbox WHERE report_id IN ({placeholders})", report_ids)
+                await db.execute(
+                    f"DELETE FROM delivery_attempts WHERE report_id IN ({placeholders})", report_ids
+                )
+
+            where, params = time_filter("last_seen")
+            if namespace:
+                where += " AND namespace = ?"
+                params.append(namespace)
+            if report_ids:
+                where = f" AND ((1 = 1{where}) OR first_report_id IN ({placeholders}))"
+                params.extend(report_ids)
+            cursor = await db.execute(
+                f"DELETE FROM recommendations WHERE cluster_name = ?{where}",
+                [cluster_name, *params],
+            )
+            deleted["recommendations"] = cursor.rowcount
+
+            where, params = time_filter("last_seen")
+            if namespace:
+                where += " AND namespace = ?"
+                params.append(namespace)
+            if report_ids:
+                where = f" AND ((1 = 1{where}) OR first_report_id IN ({placeholders}))"
+                params.extend(report_ids)
+            cursor = await db.execute(
+                f"DELETE FROM findings WHERE cluster_name = ?{where}",
+                [cluster_name, *params],
+            )
+            deleted["findings"] = cursor.rowcount
+
+            where, params = time_filter("captured_at")
+            if namespace:
+                where += " AND namespace = ?"
+                params.append(namespace)
+            if report_ids:
+                where = f" AND ((1 = 1{where}) OR report_id IN ({placeholders}))"
+                params.extend(report_ids)
+            cursor = await db.execute(
+                f"DELETE FROM raw_objects WHERE cluster_name = ?{where}",
+                [cluster_name, *params],
+            )
+            deleted["raw_objects"] = cursor.rowcount
+
+            where, params = time_filter("observed_at")
+            if namespace:
+                where += " AND namespace = ?"
+                params.append(namespace)
+            cursor = await db.execute(
+                f"DELETE FROM pod_transitions WHERE cluster_name = ?{where}",
+                [cluster_name, *params],
+            )
+            deleted["pod_transitions"] = cursor.rowcount
+
+            where, params = time_filter("sampled_at")
+            if namespace:
+                where += " AND namespace = ?"
+                params.append(namespace)
+            cursor = await db.execute(
+                f"DELETE FROM workload_samples WHERE cluster_name = ?{where}",
+                [cluster_name, *params],
+            )
+            deleted["workload_samples"] = cursor.rowcount
+
+            # Probes are not namespace-scoped: only whole-cluster purges remove them
+            if not namespace:
+                where, params = time_filter("probed_at")
+                cursor = await db.execute(
+                    f"DELETE FROM endpoint_probes WHERE cluster_name = ?{where}",
+                    [cluster_name, *params],
+                )
+                deleted["endpoint_probes"] = cursor.rowcount
+
+            where, params = time_filter("observed_at")
+            if namespace:
+                where += " AND (namespace = ? OR target_namespace = ?)"
+                params.extend([namespace, namespace])
+            cursor = await db.execute(
+                f"DELETE FROM workload_dependencies WHERE cluster_name = ?{where}",
+                [cluster_name, *params],
+            )
+            deleted["workload_dependencies"] = cursor.rowcount
+
+            await db.commit()
+            await db.execute("VACUUM")
+
+        # The backup would otherwise keep a copy of the purged data
+        if os.path.exists(self.backup_path):
+            await self.backup()
+
+        logger.warning(
+            "data_purged",
+            cluster=cluster_name,
+            namespace=namespace,
+            before=before.isoformat() if before else None,
+            after=after.isoformat() if after else None,
+            deleted=deleted,
+        )
+
+        return deleted
+
+    async def get_report_stats(self) -> dict:
+        """Get statistics about stored reports.
+
+        Returns:
+            Dict with report statistics
+        """
+        async with self._connect() as db:
+            async with db.execute(
+                """
+                SELECT
+                    COUNT(*) as total_reports,
+                    SUM(report_size) as total_size,
+                    MAX(generated_at) as latest_report_date,
+                    MIN(generated_at) as oldest_report_date
+                FROM reports
+                WHERE cluster_name = ?
+                """,
+                (settings.cluster_name,),
+            ) as cursor:
+                row = await cursor.fetchone()
+
+                database_size = self.get_database_size()
+                max_size = settings.max_database_size_mb * 1024 * 1024
+
+                return {
+                    "total_reports": row[0] or 0,
+                    "total_size_bytes": row[1] or 0,
+                    "latest_report_date": row[2],
+                    "oldest_report_date": row[3],
+                    "database_size_bytes": database_size,
+                    "database_remaining_bytes": (
+                        max(max_size - database_size, 0) if max_size > 0 else None
+                    ),
+                }
+
+    # Pod transition methods
+
+    async def insert_pod_transition(
+        self,
+        namespace: str,
+        pod: str,
+        transition_type: str,
+        observed_at: str,
+        container: Optional[str] = None,
+        from_phase: Optional[str] = None,
+        to_phase: Optional[str] = None,
+        reason: Optional[str] = None,
+        exit_code: Optional[int] = None,
+        signal: Optional[int] = None,
+        details: Optional[str] = None,
+    ) -> int:
+        """Record a pod phase transition or container termination.
+
+        Args:
+            namespace: Pod namespace
+            pod: Pod name
+            transition_type: 'phase' or 'container_terminated'
+            observed_at: ISO timestamp when the watcher saw the change
+            container: Container name for terminations
+            from_phase: Pod phase before the change
+            to_phase: Pod phase after the change
+            reason: Kubernetes reason (e.g., OOMKilled, Error, Evicted)
+            exit_code: Container exit code for terminations
+            signal: Signal that killed the container, when the runtime reports it
+            details: Describe-like detail of the pod (JSON), for unhealthy pods
+
+        Returns:
+            Transition ID
+        """
+        async with self._connect() as db:
+            cursor = await db.execute(
+                """
+                INSERT INTO pod_transitions (
+                    cluster_name, namespace, pod, container, transition_type,
+                    from_phase, to_phase, reason, exit_code, signal, observed_at, details
+                )
+                VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+                """,
+                (
+                    settings.cluster_name,
+                    namespace,
+                    pod,
+                    container,
+                    transition_type,
+                    from_phase,
+                    to_phase,
+                    reason,
+                    exit_code,
+                    signal,
+                    observed_at,
+                    details,
+                ),
+            )
+            await db.commit()
+            transition_id = cursor.lastrowid
+
+        return transition_id
+
+    async def insert_workload_samples(self, samples: list[dict], sampled_at: str) -> int:
+        """Record one sample per watched workload.
+
+        Args:
+            samples: Dicts with namespace, kind, name, desired, ready and restarts
+            sampled_at: ISO timestamp of the sampling round
+
+        Returns:
+            Number of samples stored
+        """
+        async with self._connect() as db:
+            await db.executemany(
+                """
+                INSERT INTO workload_samples (
+                    cluster_name, namespace, kind, name, desired, ready, restarts, sampled_at
+                )
+                VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+                """,
+                [
+                    (
+                        settings.cluster_name,
+                        sample["namespace"],
+                        sample["kind"],
+                        sample["name"],
+                        sample["desired"],
+                        sample["ready"],
+                        sample["restarts"],
+                        sampled_at,
+                    )
+                    for sample in samples
+                ],
+            )
+            await db.commit()
+
+        return len(samples)
+
+    async def get_workload_samples(self, days: int = 7) -> list[dict]:
+        """Get watched workload samples, oldest first.
+
+        Args:
+            days: Number of days to look back
+
+        Returns:
+            List of dicts with namespace, kind, name, desired, ready, restarts and sampled_at
+        """
+        since = (datetime.now() - timedelta(days=days)).isoformat()
+
+        async with self._connect() as db:
+            db.row_factory = aiosqlite.Row
+            async with db.execute(
+                """
+                SELECT namespace, kind, name, desired, ready, restarts, sampled_at
+                FROM workload_samples
+                WHERE cluster_name = ? AND sampled_at >= ?
+                ORDER BY sampled_at ASC
+                """,
+                (settings.cluster_name, since),
+            ) as cursor:
+                rows = await cursor.fetchall()
+
+        return [dict(row) for row in rows]
+
+    async def insert_probe_results(self, results: list[dict]) -> int:
+        """Record endpoint probe results.
+
+        Args:
+            results: Output of run_endpoint_probes()
+
+        Returns:
+            Number of results stored
+        """
+        async with self._connect() as db:
+            await db.executemany(
+                """
+                INSERT INTO endpoint_probes (
+                    cluster_name, name, url, probed_at, ok, status_code, latency_ms, error
+                )
+                VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+                """,
+                [
+                    (
+                        settings.cluster_name,
+                        result["name"],
+                        result["url"],
+                        result["probed_at"],
+                        int(result["ok"]),
+                        result["status_code"],
+                        result["latency_ms"],
+                        result["error"],
+                    )
+                    for result in results
+                ],
+            )
+            await db.commit()
+
+        return len(results)
+
+    async def get_probe_results(self, days: int = 7) -> list[dict]:
+        """Get endpoint probe results, oldest first.
+
+        Args:
+            days: Number of days to look back
+
+        Returns:
+            List of dicts with name, url, probed_at, ok, status_code, latency_ms and error
+        """
+        since = (datetime.now() - timedelta(days=days)).isoformat()
+
+        async with self._connect() as db:
+            db.row_factory = aiosqlite.Row
+            async with db.execute(
+                """
+                SELECT name, url, probed_at, ok, status_code, latency_ms, error
+                FROM endpoint_probes
+                WHERE cluster_name = ? AND probed_at >= ?
+                ORDER BY probed_at ASC
+                """,
+                (settings.cluster_name, since),
+            ) as cursor:
+                rows = await cursor.fetchall()
+
+        return [{**dict(row), "ok": bool(row["ok"])} for row in rows]
+
+    async def get_restarts_by_day(self, days: int = 7) -> list[dict]:
+        """Count recorded container terminations per pod and day.
+
+        Args:
+            days: Number of days to look back
+
+        Returns:
+            List of dicts with namespace, pod, day (YYYY-MM-DD) and restarts
+        """
+        since = (datetime.now() - timedelta(days=days)).isoformat()
+
+        async with self._connect() as db:
+            db.row_factory = aiosqlite.Row
+            async with db.execute(
+                """
+                SELECT namespace, pod, substr(observed_at, 1, 10) AS day,
+                       COUNT(*) AS restarts
+                FROM pod_transitions
+                WHERE cluster_name = ?
+                  AND transition_type = 'container_terminated'
+                  AND observed_at >= ?
+                GROUP BY namespace, pod, day
+                """,
+                (settings.cluster_name, since),
+            ) as cursor:
+                rows = await cursor.fetchall()
+
+        return [dict(row) for row in rows]
+
+    async def get_container_terminations(self, days: int = 7) -> list[dict]:
+        """Get recorded container terminations.
+
+        Args:
+            days: Number of days to look back
+
+        Returns:
+            List of dicts with namespace, pod, container, reason, exit_code, signal and observed_at
+        """
+        since = (datetime.now() - timedelta(days=days)).isoformat()
+
+        async with self._connect() as db:
+            db.row_factory = aiosqlite.Row
+            async with db.execute(
+                """
+                SELECT namespace, pod, container, reason, exit_code, signal, observed_at
+                FROM pod_transitions
+                WHERE cluster_name = ?
+                  AND transition_type = 'container_terminated'
+                  AND observed_at >= ?
+                ORDER BY observed_at DESC
+                """,
+                (settings.cluster_name, since),
+            ) as cursor:
+                rows = await cursor.fetchall()
+
+        return [dict(row) for row in rows]
+
+    async def cleanup_old_pod_transitions(self) -> int:
+        """Remove pod transitions, workload samples and probe results older than retention period.
+
+        Returns:
+            Number of transitions deleted
+        """
+        cutoff_date = datetime.now() - timedelta(weeks=settings.retention_weeks)
+
+        async with self._connect() as db:
+            cursor = await db.execute(
+                """
+                DELETE FROM pod_transitions
+                WHERE cluster_name = ? AND observed_at < ?
+                """,
+                (settings.cluster_name, cutoff_date.isoformat()),
+            )
+            deleted_count = cursor.rowcount
+
+            # Watched workload samples share the pod watcher's retention
+            await db.execute(
+                """
+                DELETE FROM workload_samples
+                WHERE cluster_name = ? AND sampled_at < ?
+                """,
+                (settings.cluster_name, cutoff_date.isoformat()),
+            )
+            await db.execute(
+                "DELETE FROM endpoint_probes WHERE cluster_name = ? AND probed_at < ?",
+                (settings.cluster_name, cutoff_date.isoformat()),
+            )
+            await db.commit()
+
+        logger.info(
+            "old_pod_transitions_cleaned",
+            deleted_count=deleted_count,
+            cutoff_date=cutoff_date.isoformat(),
+        )
+
+        return deleted_count
+
+    # Audit trail methods
+
+    async def record_audit(
+        self,
+        actor: str,
+        action: str,
+        target: Optional[str] = None,
+        client_ip: Optional[str] = None,
+        details: Optional[dict] = None,
+    ) -> int:
+        """Append an entry to the audit trail.
+
+        Args:
+            actor: Who acted (token:<fingerprint>, slack:<user ID> or anonymous)
+            action: What was done (report.trigger, report.approve, data.purge...)
+            target: Object acted on (job, report ID, cluster...)
+            client_ip: Address of the caller
+            details: Extra request parameters
+
+        Returns:
+            Audit entry ID
+        """
+        async with self._connect() as db:
+            cursor = await db.execute(
+                """
+                INSERT INTO audit_log
+                    (cluster_name, occurred_at, actor, action, target, client_ip, details)
+                VALUES (?, ?, ?, ?, ?, ?, ?)
+                """,
+                (
+                    settings.cluster_name,
+                    datetime.now().isoformat(),
+                    actor,
+                    action,
+                    target,
+                    client_ip,
+                    json.dumps(details) if details else None,
+                ),
+            )
+            await db.commit()
+            return cursor.lastrowid
+
+    async def get_audit_log(
+        self, limit: int = 100, action: Optional[str] = None, actor: Optional[str] = None
+    ) -> list[dict]:
+        """Get the most recent audit entries.
+
+        Args:
+            limit: Maximum number of entries
+            action: Only entries of this action
+            actor: Only entries of this actor
+
+        Returns:
+            Audit entries, newest first
+        """
+        query = "SELECT * FROM audit_log WHERE cluster_name = ?"
+        params: list = [settings.cluster_name]
+        if action:
+            query += " AND action = ?"
+            params.append(action)
+        if actor:
+            query += " AND actor = ?"
+            params.append(actor)
+        query += " ORDER BY occurred_at DESC, id DESC LIMIT ?"
+        params.append(limit)
+
+        async with self._connect() as db:
+            db.row_factory = aiosqlite.Row
+            async with db.execute(query, params) as cursor:
+                rows = await cursor.fetchall()
+
+        return [
+            {**dict(row), "details": json.loads(row["details"]) if row["details"] else None}
+            for row in rows
+        ]
+
+    async def cleanup_old_audit_log(self) -> int:
+        """Remove audit entries older than AUDIT_RETENTION_DAYS.
+
+        Returns:
+            Number of entries deleted
+        """
+        cutoff_date = datetime.now() - timedelta(days=settings.audit_retention_days)
+
+        async with self._connect() as db:
+            cursor = await db.execute(
+                "DELETE FROM audit_log WHERE cluster_name = ? AND occurred_at < ?",
+                (settings.cluster_name, cutoff_date.isoformat()),
+            )
+            await db.commit()
+
+        return cursor.rowcount
+
+    # Raw object archive methods
+
+    async def save_raw_objects(self, report_id: int, objects: list[dict]) -> int:
+        """Archive the full objects of unhealthy resources, linked to a report.
+
+        Args:
+            report_id: Report (or collect-only snapshot) the objects belong to
+            objects: Output of collect_unhealthy_objects()
+
+        Returns:
+            Number of objects stored
+        """
+        if not objects:
+            return 0
+
+        captured_at = datetime.now().isoformat()
+
+        async with self._connect() as db:
+            await db.executemany(
+                """
+                INSERT INTO raw_objects (
+                    cluster_name, report_id, captured_at, kind, namespace, name, reason, object
+                )
+                VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+                """,
+                [
+                    (
+                        settings.cluster_name,
+                        report_id,
+                        captured_at,
+                        obj["kind"],
+                        obj["namespace"],
+                        obj["name"],
+                        obj["reason"],
+                        zlib.compress(json.dumps(obj["object"], default=str).encode("utf-8")),
+                    )
+                    for obj in objects
+                ],
+            )
+            await db.commit()
+
+        logger.info("raw_objects_archived", report_id=report_id, objects=len(objects))
+
+        return len(objects)
+
+    async def get_raw_objects(self, report_id: int) -> list[dict]:
+        """List the objects archived with a report, without their content.
+
+        Args:
+            report_id: Report ID
+
+        Returns:
+            Entries with id, kind, namespace, name, reason, captured_at and
+            compressed size
+        """
+        async with self._connect() as db:
+            db.row_factory = aiosqlite.Row
+            async with db.execute(
+                """
+                SELECT id, kind, namespace, name, reason, captured_at, LENGTH(object) AS size
+                FROM raw_objects
+                WHERE cluster_name = ? AND report_id = ?
+                ORDER BY id
+                """,
+                (settings.cluster_name, report_id),
+            ) as cursor:
+                rows = await cursor.fetchall()
+
+        return [dict(row) for row in rows]
+
+    async def get_raw_object(self, report_id: int, object_id: int) -> Optional[dict]:
+        """Get one archived object, decompressed.
+
+        Args:
+            report_id: Report ID
+            object_id: Archived object ID
+
+        Returns:
+            The object as captured, or None if not found
+        """
+        async with self._connect() as db:
+            async with db.execute(
+                "SELECT object FROM raw_objects WHERE cluster_name = ? AND report_id = ? AND id = ?",
+                (settings.cluster_name, report_id, object_id),
+            ) as cursor:
+                row = await cursor.fetchone()
+
+        return json.loads(zlib.decompress(row[0])) if row else None
+
+    async def _delete_orphaned_report_rows(self, db: aiosqlite.Connection) -> None:
+        """Remove archived objects and delivery attempts whose report was deleted (caller commits)."""
+        for table in ("raw_objects", "delivery_attempts"):
+            await db.execute(f"DELETE FROM {table} WHERE report_id NOT IN (SELECT id FROM reports)")
+
+    # Dependency graph methods
+
+    async def replace_workload_dependencies(self, edges: list[dict]) -> int:
+        """Replace the stored dependency graph with a fresh inference.
+
+        Args:
+            edges: Output of infer_dependencies()
+
+        Returns:
+            Number of edges stored
+        """
+        observed_at = datetime.now().isoformat()
+
+        async with self._connect() as db:
+            await db.execute(
+                "DELETE FROM workload_dependencies WHERE cluster_name = ?",
+                (settings.cluster_name,),
+            )
+            await db.executemany(
+                """
+                INSERT INTO workload_dependencies (
+                    cluster_name, namespace, workload, kind, target_namespace,
+                    target_service, target_workload, via, observed_at
+                )
+                VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+                """,
+                [
+                    (
+                        settings.cluster_name,
+                        edge["namespace"],
+                        edge["workload"],
+                        edge["kind"],
+                        edge["target_namespace"],
+                        edge["target_service"],
+                        edge["target_workload"],
+                        edge["via"],
+                        observed_at,
+                    )
+                    for edge in edges
+                ],
+            )
+            await db.commit()
+
+        logger.info("workload_dependencies_stored", edges=len(edges))
+
+        return len(edges)
+
+    # Recommendation follow-up methods
+
+    @staticmethod
+    def _recommendation_fingerprint(recommendation: dict) -> str:
+        """Identify a recommendation by what it targets, not by its wording."""
+        key = "|".join(
+            str(recommendation.get(field) or "").strip().lower()
+            for field in ("category", "namespace", "target")
+        )
+        return hashlib.sha1(key.encode("utf-8")).hexdigest()
+
+    async def get_open_recommendations(self, limit: int = 20) -> list[dict]:
+        """Get recommendations not yet acted on, oldest first.
+
+        Args:
+            limit: Maximum number of recommendations
+
+        Returns:
+            List of recommendation dicts
+        """
+        async with self._connect() as db:
+            db.row_factory = aiosqlite.Row
+            async with db.execute(
+                """
+                SELECT id, category, namespace, target, action, first_seen, last_seen
+                FROM recommendations
+                WHERE cluster_name = ? AND status = 'open'
+                ORDER BY first_seen ASC
+                LIMIT ?
+                """,
+                (settings.cluster_name, limit),
+            ) as cursor:
+                rows = await cursor.fetchall()
+
+        return [dict(row) for row in rows]
+
+    async def apply_recommendation_followup(self, followups: list[dict]) -> list[dict]:
+        """Close recommendations the agent verified as acted on.
+
+        Args:
+            followups: Dicts with id, status ('resolved' or 'open') and evidence
+
+        Returns:
+            The open recommendations that were updated, with their new status
+        """
+        now = datetime.now().isoformat()
+        updated = []
+
+        async with self._connect() as db:
+            db.row_factory = aiosqlite.Row
+            for followup in followups:
+                try:
+                    recommendation_id = int(followup.get("id"))
+                except (TypeError, ValueError):
+                    continue
+
+                async with db.execute(
+                    """
+                    SELECT id, category, namespace, target, action, first_seen
+                    FROM recommendations
+                    WHERE id = ? AND cluster_name = ? AND status = 'open'
+                    """,
+                    (recommendation_id, settings.cluster_name),
+                ) as cursor:
+                    row = await cursor.fetchone()
+                if not row:
+                    continue
+
+                resolved = followup.get("status") == "resolved"
+                await db.execute(
+                    """
+                    UPDATE recommendations
+                    SET status = ?, resolved_at = ?, evidence = ?
+                    WHERE id = ?
+                    """,
+                    (
+                        "resolved" if resolved else "open",
+                        now if resolved else None,
+                        followup.get("evidence"),
+                        recommendation_id,
+                    ),
+                )
+                updated.append({
+                    **dict(row),
+                    "status": "resolved" if resolved else "open",
+                    "evidence": followup.get("evidence"),
+                })
+            await db.commit()
+
+        logger.info(
+            "recommendation_followup_applied",
+            updated=len(updated),
+            resolved=sum(1 for r in updated if r["status"] == "resolved"),
+        )
+
+        return updated
+
+    async def record_recommendations(self, report_id: int, recommendations: list[dict]) -> int:
+        """Store the recommendations issued in a report.
+
+        A recommendation matching an open one (same category, namespace and
+        target) refreshes it instead of creating a duplicate.
+
+        Args:
+            report_id: Report that issued the recommendations
+            recommendations: Dicts with category, namespace, target and action
+
+        Returns:
+            Number of new recommendations
+        """
+        now = datetime.now().isoformat()
+        created = 0
+
+        async with self._connect() as db:
+            for recommendation in recommendations:
+                if not recommendation.get("action"):
+                    continue
+                fingerprint = self._recommendation_fingerprint(recommendation)
+
+                cursor = await db.execute(
+                    """
+                    UPDATE recommendations
+                    SET last_seen = ?, last_report_id = ?, action = ?
+                    WHERE cluster_name = ? AND fingerprint = ? AND status = 'open'
+                    """,
+                    (now, report_id, recommendation["action"], settings.cluster_name, fingerprint),
+                )
+                if cursor.rowcount:
+                    continue
+
+                await db.execute(
+                    """
+                    INSERT INTO recommendations (
+                        cluster_name, fingerprint, category, namespace, target, action,
+                        first_report_id, last_report_id, first_seen, last_seen
+                    )
+                    VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+                    """,
+                    (
+                        settings.cluster_name,
+                        fingerprint,
+                        recommendation.get("category"),
+                        recommendation.get("namespace"),
+                        recommendation.get("target"),
+                        recommendation["action"],
+                        report_id,
+                        report_id,
+                        now,
+                        now,
+                    ),
+                )
+                created += 1
+            await db.commit()
+
+        logger.info("recommendations_recorded", report_id=report_id, created=created)
+
+        return created
+
+    async def get_recommendation_stats(self) -> dict:
+        """Get the closure rate of all recommendations issued so far.
+
+        Returns:
+            Dict with issued, resolved and closure_rate_pct
+        """
+        async with self._connect() as db:
+            async with db.execute(
+                """
+                SELECT COUNT(*), SUM(CASE WHEN status = 'resolved' THEN 1 ELSE 0 END)
+                FROM recommendations
+                WHERE cluster_name = ?
+                """,
+                (settings.cluster_name,),
+            ) as cursor:
+                row = await cursor.fetchone()
+
+        issued = row[0] or 0
+        resolved = row[1] or 0
+
+        return {
+            "issued": issued,
+            "resolved": resolved,
+            "closure_rate_pct": round(resolved / issued * 100, 1) if issued else None,
+        }
+
+    # Job queue methods
+
+    @staticmethod
+    def finding_fingerprint(finding: dict) -> str:
+        """Identify a finding by kind, resource and reason, not by its wording."""
+        key = "|".join(
+            str(finding.get(field) or "").strip().lower()
+            for field in ("kind", "namespace", "resource", "reason")
+        )
+        return hashlib.sha1(key.encode("utf-8")).hexdigest()
+
+    async def get_open_findings(self) -> dict[str, dict]:
+        """Get findings still open after the previous report.
+
+        Returns:
+            Dict of fingerprint -> finding row (first_seen, occurrences, ...)
+        """
+        async with self._connect() as db:
+            db.row_factory = aiosqlite.Row
+            async with db.execute(
+                """
+                SELECT fingerprint, kind, namespace, resource, reason, severity, title,
+                       occurrences, first_seen, last_seen
+                FROM findings
+                WHERE cluster_name = ? AND status = 'open'
+                """,
+                (settings.cluster_name,),
+            ) as cursor:
+                rows = await cursor.fetchall()
+
+        return {row["fingerprint"]: dict(row) for row in rows}
+
+    async def get_findings_seen_since(self, since: datetime) -> list[dict]:
+        """Get findings reported at least once after a given time, open or closed.
+
+        Args:
+            since: Start of the period
+
+        Returns:
+            Finding rows, most recurrent first
+        """
+        async with self._connect() as db:
+            db.row_factory = aiosqlite.Row
+            async with db.execute(
+                """
+                SELECT fingerprint, kind, namespace, resource, reason, severity, title,
+                       status, occurrences, first_seen, last_seen, closed_at
+                FROM findings
+                WHERE cluster_name = ? AND last_seen >= ?
+                ORDER BY occurrences DESC, first_seen ASC
+                """,
+                (settings.cluster_name, since.isoformat()),
+            ) as cursor:
+                rows = await cursor.fetchall()
+
+        return [dict(row) for row in rows]
+
+    async def record_findings(self, report_id: int, findings: list[dict]) -> dict[str, int]:
+        """Store the findings of a weekly report and close those no longer reported.
+
+        A finding matching an open one (same fingerprint) is counted as another
+        occurrence of the same ongoing issue.
+
+        Args:
+            report_id: Report that contains the findings
+            findings: Dicts with kind, namespace, resource, reason, severity and title
+
+        Returns:
+            Number of new, ongoing and closed findings
+        """
+        now = datetime.now().isoformat()
+        counts = {"new": 0, "ongoing": 0, "closed": 0}
+        seen = set()
+
+        async with self._connect() as db:
+            for finding in findings:
+                if not finding.get("resource") or not finding.get("reason"):
+                    continue
+                fingerprint = self.finding_fingerprint(finding)
+                if fingerprint in seen:
+                    continue
+                seen.add(fingerprint)
+
+                cursor = await db.execute(
+                    """
+                    UPDATE findings
+                    SET last_seen = ?, last_report_id = ?, occurrences = occurrences + 1,
+                        severity = ?, title = ?
+                    WHERE cluster_name = ? AND fingerprint = ? AND status = 'open'
+                    """,
+                    (
+                        now,
+                        report_id,
+                        finding.get("severity"),
+                        finding.get("title"),
+                        settings.cluster_name,
+                        fingerprint,
+                    ),
+                )
+                if cursor.rowcount:
+                    counts["ongoing"] += 1
+                    continue
+
+                await db.execute(
+                    """
+                    INSERT INTO findings (
+                        cluster_name, fingerprint, kind, namespace, resource, reason,
+                        severity, title, first_report_id, last_report_id, first_seen, last_seen
+                    )
+                    VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+                    """,
+                    (
+                        settings.cluster_name,
+                        fingerprint,
+                        finding.get("kind"),
+                        finding.get("namespace"),
+                        finding["resource"],
+                        finding["reason"],
+                        finding.get("severity"),
+                        finding.get("title"),
+                        report_id,
+                        report_id,
+                        now,
+                        now,
+                    ),
+                )
+                counts["new"] += 1
+
+            # Open findings missing from this report are considered fixed
+            cursor = await db.execute(
+                """
+                UPDATE findings
+                SET status = 'closed', closed_at = ?
+                WHERE cluster_name = ? AND status = 'open' AND last_report_id != ?
+                """,
+                (now, settings.cluster_name, report_id),
+            )
+            counts["closed"] = cursor.rowcount
+            await db.commit()
+
+        logger.info("findings_recorded", report_id=report_id, **counts)
+
+        return counts
+
+    async def get_delivery(self, idempotency_key: str) -> Optional[dict]:
+        """Get the delivery recorded for an idempotency key.
+
+        Args:
+            idempotency_key: Key of the report period (e.g., 'weekly:2025-W14')
+
+        Returns:
+            Dict with report_id (None while the report is being generated) and
+            claimed_at, or None if nothing was claimed
+        """
+        async with self._connect() as db:
+            db.row_factory = aiosqlite.Row
+            async with db.execute(
+                """
+                SELECT report_id, claimed_at
+                FROM report_deliveries
+                WHERE cluster_name = ? AND idempotency_key = ?
+                """,
+                (settings.cluster_name, idempotency_key),
+            ) as cursor:
+                row = await cursor.fetchone()
+
+        return dict(row) if row else None
+
+    async def claim_delivery(self, idempotency_key: str, report_id: Optional[int] = None) -> bool:
+        """Atomically claim the right to deliver the report of a period.
+
+        Claimed before the report is generated or stored (report_id None), then
+        bound to the report with assign_delivery(), so a concurrent job gives up
+        before any side effect.
+
+        Args:
+            idempotency_key: Key of the report period
+            report_id: Report about to be delivered, or None for a pending claim
+
+        Returns:
+            True if claimed, False if another job already claimed the key
+        """
+        stale_before = datetime.now() - timedelta(hours=PENDING_DELIVERY_CLAIM_HOURS)
+
+        async with self._connect() as db:
+            await db.execute(
+                """
+                DELETE FROM report_deliveries
+                WHERE cluster_name = ? AND idempotency_key = ?
+                  AND report_id IS NULL AND claimed_at < ?
+                """,
+                (settings.cluster_name, idempotency_key, stale_before.isoformat()),
+            )
+            cursor = await db.execute(
+                """
+                INSERT OR IGNORE INTO report_deliveries (
+                    cluster_name, idempotency_key, report_id, claimed_at
+                )
+                VALUES (?, ?, ?, ?)
+                """,
+                (settings.cluster_name, idempotency_key, report_id, datetime.now().isoformat()),
+            )
+            await db.commit()
+            claimed = cursor.rowcount == 1
+
+        logger.info(
+            "report_delivery_claimed" if claimed else "report_delivery_duplicate",
+            idempotency_key=idempotency_key,
+            report_id=report_id,
+        )
+
+        return claimed
+
+    async def assign_delivery(self, idempotency_key: str, report_id: int) -> None:
+        """Bind a pending claim to the report stored for the period.
+
+        Args:
+            idempotency_key: Key of the report period
+            report_id: Report that will be delivered
+        """
+        async with self._connect() as db:
+            await db.execute(
+                """
+                UPDATE report_deliveries
+                SET report_id = ?
+                WHERE cluster_name = ? AND idempotency_key = ? AND report_id IS NULL
+                """,
+                (report_id, settings.cluster_name, idempotency_key),
+            )
+            await db.commit()
+
+    async def release_delivery(self, idempotency_key: str) -> None:
+        """Release a pending claim whose job failed before saving, so a retry can send the report.
+
+        Claims assigned to a stored report are never released: the outbox
+        delivers that report instead.
+
+        Args:
+            idempotency_key: Key of the report period
+        """
+        async with self._connect() as db:
+            await db.execute(
+                """
+                DELETE FROM report_deliveries
+                WHERE cluster_name = ? AND idempotency_key = ? AND report_id IS NULL
+                """,
+                (settings.cluster_name, idempotency_key),
+            )
+            await db.commit()
+
+        logger.info("report_delivery_released", idempotency_key=idempotency_key)
+
+    # Outbox methods
+
+    async def record_delivery_attempts(self, report_id: int, attempts: list[dict]) -> int:
+        """Record the per-destination outcome of delivering a report.
+
+        Args:
+            report_id: Delivered report ID
+            attempts: SlackReporter.deliveries entries
+
+        Returns:
+            Number of attempts stored
+        """
+        if not attempts:
+            return 0
+
+        async with self._connect() as db:
+            await db.executemany(
+                """
+                INSERT INTO delivery_attempts (
+                    cluster_name, report_id, attempted_at, destination, format, size_bytes, ok, error
+                )
+                VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+                """,
+                [
+                    (
+                        settings.cluster_name,
+                        report_id,
+                        attempt["attempted_at"],
+                        attempt["destination"],
+                        attempt["format"],
+                        attempt["size_bytes"],
+                        int(attempt["ok"]),
+                        attempt["error"],
+                    )
+                    for attempt in attempts
+                ],
+            )
+            await db.commit()
+
+        return len(attempts)
+
+    async def get_delivery_attempts(
+        self, limit: int = 50, report_id: Optional[int] = None, failed_only: bool = False
+    ) -> list[dict]:
+        """Get report delivery attempts, newest first.
+
+        Args:
+            limit: Maximum number of attempts
+            report_id: Only attempts of this report
+            failed_only: Only failed attempts
+
+        Returns:
+            Attempts with report_id, attempted_at, destination, format, size_bytes, ok and error
+        """
+        query = """
+            SELECT report_id, attempted_at, destination, format, size_bytes, ok, error
+            FROM delivery_attempts
+            WHERE cluster_name = ?
+        """
+        params: list = [settings.cluster_name]
+        if report_id is not None:
+            query += " AND report_id = ?"
+            params.append(report_id)
+        if failed_only:
+            query += " AND ok = 0"
+        query += " ORDER BY attempted_at DESC, id DESC LIMIT ?"
+        params.append(limit)
+
+        async with self._connect() as db:
+            db.row_factory = aiosqlite.Row
+            async with db.execute(query, params) as cursor:
+                rows = await cursor.fetchall()
+
+        return [{**dict(row), "ok": bool(row["ok"])} for row in rows]
+
+    async def get_delivery_summary(self, days: int = 30) -> list[dict]:
+        """Summarize delivery attempts per destination.
+
+        Args:
+            days: Number of days to look back
+
+        Returns:
+            Per destination: attempts, failures, last success and last failure
+            (with its error), most failures first
+        """
+        since = (datetime.now() - timedelta(days=days)).isoformat()
+
+        async with self._connect() as db:
+            db.row_factory = aiosqlite.Row
+            async with db.execute(
+                """
+                SELECT
+                    destination,
+                    COUNT(*) AS attempts,
+                    SUM(CASE WHEN ok = 0 THEN 1 ELSE 0 END) AS failures,
+                    MAX(CASE WHEN ok = 1 THEN attempted_at END) AS last_success,
+                    MAX(CASE WHEN ok = 0 THEN attempted_at END) AS last_failure
+                FROM delivery_attempts
+                WHERE cluster_name = ? AND attempted_at >= ?
+                GROUP BY destination
+                ORDER BY failures DESC, destination
+                """,
+                (settings.cluster_name, since),
+            ) as cursor:
+                rows = [dict(row) for row in await cursor.fetchall()]
+
+            for row in rows:
+                async with db.execute(
+                    """
+                    SELECT error FROM delivery_attempts
+                    WHERE cluster_name = ? AND destination = ? AND ok = 0
+                    ORDER BY attempted_at DESC LIMIT 1
+                    """,
+                    (settings.cluster_name, row["destination"]),
+                ) as cursor:
+                    last_error = await cursor.fetchone()
+                row["last_error"] = last_error["error"] if last_error else None
+
+        return rows
+
+    async def enqueue_outbox(self, report_id: int, kind: str, error: str) -> int:
+        """Queue a stored report whose delivery failed for retries.
+
+        Args:
+            report_id: Report to deliver
+            kind: 'report' (publish to the channel) or 'review' (send to reviewers)
+            error: Error of the failed attempt
+
+        Returns:
+            Outbox entry ID
+        """
+        now = datetime.now()
+        next_attempt = now + timedelta(seconds=settings.outbox_backoff_seconds)
+
+        async with self._connect() as db:
+            cursor = await db.execute(
+                """
+                INSERT INTO outbox (
+                    cluster_name, report_id, kind, attempts, last_error, created_at, next_attempt_at
+                )
+                VALUES (?, ?, ?, 1, ?, ?, ?)
+                """,
+                (settings.cluster_name, report_id, kind, error, now.isoformat(), next_attempt.isoformat()),
+            )
+            await db.commit()
+            entry_id = cursor.lastrowid
+
+        logger.warning(
+            "delivery_queued_in_outbox",
+            outbox_id=entry_id,
+            report_id=report_id,
+            kind=kind,
+            next_attempt_at=next_attempt.isoformat(),
+        )
+
+        return entry_id
+
+    async def get_due_outbox(self, limit: int = 10) -> list[dict]:
+        """Get pending outbox entries whose next attempt is due, oldest first.
+
+        Args:
+            limit: Maximum number of entries
+
+        Returns:
+            List of outbox rows
+        """
+        async with self._connect() as db:
+            db.row_factory = aiosqlite.Row
+            async with db.execute(
+                """
+                SELECT id, report_id, kind, attempts, last_error, created_at, next_attempt_at
+                FROM outbox
+                WHERE cluster_name = ? AND status = 'pending' AND next_attempt_at <= ?
+                ORDER BY next_attempt_at ASC
+                LIMIT ?
+                """,
+                (settings.cluster_name, datetime.now().isoformat(), limit),
+            ) as cursor:
+                rows = await cursor.fetchall()
+
+        return [dict(row) for row in rows]
+
+    async def complete_outbox(self, entry_id: int) -> None:
+        """Mark an outbox entry as delivered.
+
+        Args:
+            entry_id: Outbox entry ID
+        """
+        async with self._connect() as db:
+            await db.execute(
+                "UPDATE outbox SET status = 'sent', sent_at = ?, last_error = NULL WHERE id = ?",
+                (datetime.now().isoformat(), entry_id),
+            )
+            await db.commit()
+
+    async def retry_outbox(self, entry_id: int, attempts: int, error: str) -> Optional[datetime]:
+        """Record a failed attempt and schedule the next one with exponential backoff.
+
+        Args:
+            entry_id: Outbox entry ID
+            attempts: Attempts made so far, including this one
+            error: Error of this attempt
+
+        Returns:
+            Time of the next attempt, or None when the entry gave up after
+            OUTBOX_MAX_ATTEMPTS attempts
+        """
+        next_attempt = None
+        status = "failed"
+        if attempts < settings.outbox_max_attempts:
+            delay = min(settings.outbox_backoff_seconds * 2 ** (attempts - 1), 6 * 3600)
+            next_attempt = datetime.now() + timedelta(seconds=delay)
+            status = "pending"
+
+        async with self._connect() as db:
+            await db.execute(
+                """
+                UPDATE outbox
+                SET status = ?, attempts = ?, last_error = ?, next_attempt_at = COALESCE(?, next_attempt_at)
+                WHERE id = ?
+                """,
+                (status, attempts, error, next_attempt.isoformat() if next_attempt else None, entry_id),
+            )
+            await db.commit()
+
+        return next_attempt
+
+    async def insert_job(self, job_type: str, payload: Optional[str] = None) -> int:
+        """Insert a new job into the queue.
+
+        Args:
+            job_type: Type of job to process (e.g., 'generate_report')
+            payload: Optional JSON payload with job data
+
+        Returns:
+            Job ID
+        """
+        async with self._connect() as db:
+            cursor = await db.execute(
+                """
+                INSERT INTO jobs (type, status, payload)
+                VALUES (?, 'pending', ?)
+                """,
+                (job_type, payload),
+            )
+            await db.commit()
+            job_id = cursor.lastrowid
+
+        logger.info("job_inserted", job_id=job_id, job_type=job_type, source="queue")
+
+        return job_id
+
+    async def get_job(self, job_id: int) -> Optional[dict]:
+        """Get a job by ID with its parsed payload and result.
+
+        Args:
+            job_id: Job ID
+
+        Returns:
+            Job dict or None if it does not exist
+        """
+        async with self._connect() as db:
+            db.row_factory = aiosqlite.Row
+            async with db.execute(
+                """
+                SELECT id, type, status, payload, created_at, started_at,
+                       completed_at, result, error, retry_count, stage_timings
+                FROM jobs
+                WHERE id = ?
+                """,
+                (job_id,),
+            ) as cursor:
+                row = await cursor.fetchone()
+
+        if not row:
+            return None
+
+        job = dict(row)
+        for field in ("payload", "result", "stage_timings"):
+            try:
+                job[field] = json.loads(job[field]) if job[field] else None
+            except json.JSONDecodeError:
+                pass
+
+        return job
+
+    async def get_pending_job(self) -> Optional[dict]:
+        """Get the next pending job from the queue.
+
+        Returns:
+            Job dict or None if no pending jobs exist
+        """
+        async with self._connect() as db:
+            db.row_factory = aiosqlite.Row
+            async with db.execute(
+                """
+                SELECT id, type, status, payload, created_at, retry_count
+                FROM jobs
+                WHERE status = 'pending'
+                ORDER BY created_at ASC
+                LIMIT 1
+                """,
+            ) as cursor:
+                row = await cursor.fetchone()
+                if row:
+                    return dict(row)
+
+        return None
+
+    async def update_job_status(
+        self,
+        job_id: int,
+        status: str,
+        result: Optional[str] = None,
+        error: Optional[str] = None,
+    ) -> None:
+        """Update job status and result.
 
         Args:
             job_id: Job ID to update
@@ -250,7 +2408,7 @@ class ReportStorage:
             "started_at" if status == "processing" else "completed_at"
         )
 
-        async with aiosqlite.connect(self.db_path) as db:
+        async with self._connect() as db:
             await db.execute(
                 f"""
                 UPDATE jobs
@@ -268,6 +2426,74 @@ class ReportStorage:
             source="queue",
         )
 
+    async def record_stage_timings(self, job_id: int, timings: dict) -> None:
+        """Store the pipeline stage durations of a job run.
+
+        Args:
+            job_id: Job ID
+            timings: Stage -> seconds
+        """
+        async with self._connect() as db:
+            await db.execute(
+                "UPDATE jobs SET stage_timings = ? WHERE id = ?",
+                (json.dumps(timings), job_id),
+            )
+            await db.commit()
+
+    async def get_stage_timing_stats(self, days: int = 30, recent: int = 20) -> dict:
+        """Summarize pipeline stage durations, to spot regressions in any stage.
+
+        Args:
+            days: Look-back window
+            recent: Number of latest job runs returned with their timings
+
+        Returns:
+            Dict with per-stage statistics (runs, average, p95, max and latest
+            seconds) and the latest job runs, newest first
+        """
+        async with self._connect() as db:
+            db.row_factory = aiosqlite.Row
+            async with db.execute(
+                """
+                SELECT id, type, status, created_at, stage_timings
+                FROM jobs
+                WHERE stage_timings IS NOT NULL AND created_at >= datetime('now', ?)
+                ORDER BY id DESC
+                """,
+                (f"-{days} days",),
+            ) as cursor:
+                rows = await cursor.fetchall()
+
+        runs = []
+        durations: dict[str, list[float]] = {}
+        for row in rows:
+            try:
+                timings = json.loads(row["stage_timings"])
+            except json.JSONDecodeError:
+                continue
+            runs.append({
+                "job_id": row["id"],
+                "type": row["type"],
+                "status": row["status"],
+                "created_at": row["created_at"],
+                "stage_timings": timings,
+            })
+            for stage, seconds in timings.items():
+                durations.setdefault(stage, []).append(seconds)
+
+        stages = {}
+        for stage, values in durations.items():
+            ordered = sorted(values)
+            stages[stage] = {
+                "runs": len(values),
+                "avg_seconds": round(sum(values) / len(values), 3),
+                "p95_seconds": ordered[min(len(ordered) - 1, int(len(ordered) * 0.95))],
+                "max_seconds": ordered[-1],
+                "latest_seconds": values[0],
+            }
+
+        return {"days": days, "stages": stages, "recent": runs[:recent]}
+
     async def increment_job_retry(self, job_id: int) -> int:
         """Increment retry count for a job.
 
@@ -277,7 +2503,7 @@ class ReportStorage:
         Returns:
             New retry count
         """
-        async with aiosqlite.connect(self.db_path) as db:
+        async with self._connect() as db:
             await db.execute(
                 """
                 UPDATE jobs
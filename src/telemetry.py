@@ -0,0 +1,89 @@
+"""Opt-in anonymized health telemetry for fleet-wide dashboards.
+
+Only aggregate numbers leave the cluster: no cluster, namespace, node, pod or
+workload names. The cluster is identified by a one-way hash so reports from
+the same cluster can be grouped without revealing which cluster it is.
+"""
+
+import hashlib
+from datetime import datetime
+from typing import Optional
+
+import httpx
+import structlog
+
+from src import __version__
+from src.config import settings
+
+logger = structlog.get_logger()
+
+TELEMETRY_SCHEMA_VERSION = 1
+
+# Aggregate stats copied verbatim; anything not listed here is never sent
+TELEMETRY_STAT_FIELDS = [
+    "total_pods", "running_pods", "running_pct", "total_restarts", "restarts_this_week",
+    "total_nodes", "ready_nodes", "cpu_requested_pct", "memory_requested_pct",
+    "cpu_used_pct", "memory_used_pct",
+]
+
+
+def telemetry_active() -> bool:
+    """Telemetry is only sent when explicitly enabled and an endpoint is configured."""
+    return settings.telemetry_enabled and bool(settings.telemetry_endpoint)
+
+
+def build_telemetry_payload(metadata: dict) -> dict:
+    """Build the anonymized telemetry payload of a weekly report.
+
+    Args:
+        metadata: Report metadata (cluster_stats and report_data)
+
+    Returns:
+        Payload dict; exactly what send_telemetry() posts
+    """
+    stats = metadata.get("cluster_stats") or {}
+    report_data = metadata.get("report_data") or {}
+    identity = stats.get("cluster_identity") or {}
+    coverage = stats.get("resource_coverage") or []
+    node_stability = stats.get("node_stability") or []
+
+    containers = sum(c["containers"] for c in coverage)
+    cluster_key = f"{settings.client_name}/{settings.cluster_name}"
+
+    return {
+        "schema_version": TELEMETRY_SCHEMA_VERSION,
+        "watchdog_version": __version__,
+        "cluster_hash": hashlib.sha256(cluster_key.encode("utf-8")).hexdigest()[:16],
+        "generated_at": datetime.now().replace(microsecond=0).isoformat(),
+        "cloud_provider": identity.get("provider"),
+        "health_status": report_data.get("health_status"),
+        "stats": {field: stats.get(field) for field in TELEMETRY_STAT_FIELDS},
+        "missing_requests_pct": (
+            round(sum(c["missing_requests"] for c in coverage) / containers * 100, 1)
+            if containers else None
+        ),
+        "unstable_nodes": sum(1 for n in node_stability if n["stability_score"] < 100),
+        "findings": len(report_data.get("findings") or []),
+        "recommendations": len(report_data.get("recommendations") or []),
+    }
+
+
+async def send_telemetry(payload: dict) -> Optional[int]:
+    """Post the payload to the telemetry endpoint if telemetry is active.
+
+    Args:
+        payload: Output of build_telemetry_payload()
+
+    Returns:
+        HTTP status code, or None when telemetry is disabled
+    """
+    if not telemetry_active():
+        return None
+
+    async with httpx.AsyncClient(timeout=10.0) as client:
+        response = await client.post(settings.telemetry_endpoint, json=payload)
+        response.raise_for_status()
+
+    logger.info("telemetry_sent", status_code=response.status_code, cluster_hash=payload["cluster_hash"])
+
+    return response.status_code
@@ -0,0 +1,131 @@
+"""Reversible pseudonymization of resource names for privacy mode.
+
+When privacy mode is enabled, the MCP servers replace pod, node and workload
+names, image registries and image repositories with stable tokens before results reach the model. Every token issued is
+appended to a shared mapping file (one JSON object per line) so the other MCP
+server can resolve tokens passed back as tool arguments, and the agent can
+re-substitute the real names in the rendered report.
+"""
+
+import hashlib
+import hmac
+import json
+import os
+import re
+from typing import Optional
+
+TOKEN_PATTERN = re.compile(r"\b(?:pod|node|workload|registry|image)-[0-9a-f]{10}\b")
+
+
+def load_mapping(map_path: str) -> dict[str, str]:
+    """Load token -> real name mapping from a mapping file.
+
+    Args:
+        map_path: Path to the JSON lines mapping file
+
+    Returns:
+        Dict of token to real name (empty if the file does not exist)
+    """
+    mapping: dict[str, str] = {}
+    if not map_path or not os.path.exists(map_path):
+        return mapping
+
+    with open(map_path, encoding="utf-8") as f:
+        for line in f:
+            try:
+                entry = json.loads(line)
+                mapping[entry["token"]] = entry["value"]
+            except (json.JSONDecodeError, KeyError):
+                continue
+
+    return mapping
+
+
+def deanonymize(text: str, mapping: dict[str, str]) -> str:
+    """Replace every known token in text with its real name.
+
+    Args:
+        text: Text containing tokens (e.g., generated HTML)
+        mapping: Token -> real name mapping
+
+    Returns:
+        Text with tokens substituted back
+    """
+    return TOKEN_PATTERN.sub(lambda m: mapping.get(m.group(0), m.group(0)), text)
+
+
+class Anonymizer:
+    """Issue and resolve name tokens inside an MCP server process."""
+
+    def __init__(self, map_path: Optional[str], salt: str) -> None:
+        """Initialize anonymizer.
+
+        Args:
+            map_path: Shared mapping file path; privacy mode is off when empty
+            salt: Per-report secret used to derive tokens
+        """
+        self.map_path = map_path or ""
+        self.salt = salt.encode("utf-8")
+        self.enabled = bool(self.map_path)
+        self._forward: dict[str, str] = {}
+        self._reverse: dict[str, str] = {}
+
+    @classmethod
+    def from_env(cls) -> "Anonymizer":
+        """Build anonymizer from ANONYMIZER_MAP_PATH / ANONYMIZER_SALT."""
+        return cls(
+            os.environ.get("ANONYMIZER_MAP_PATH"),
+            os.environ.get("ANONYMIZER_SALT", ""),
+        )
+
+    def token(self, kind: str, value: Optional[str]) -> Optional[str]:
+        """Return the token for a name, recording it in the mapping file.
+
+        Args:
+            kind: Token prefix ('pod', 'node', 'workload', 'registry' or 'image')
+            value: Real name
+
+        Returns:
+            Token, or the value unchanged when privacy mode is off
+        """
+        if not self.enabled or not value:
+            return value
+
+        key = f"{kind}:{value}"
+        if key in self._forward:
+            return self._forward[key]
+
+        digest = hmac.new(self.salt, key.encode("utf-8"), hashlib.sha256).hexdigest()
+        token = f"{kind}-{digest[:10]}"
+
+        self._forward[key] = token
+        self._reverse[token] = value
+        with open(self.map_path, "a", encoding="utf-8") as f:
+            f.write(json.dumps({"token": token, "value": value}) + "\n")
+
+        return token
+
+    def resolve(self, value: Optional[str]) -> Optional[str]:
+        """Return the real name for a token passed back by the model.
+
+        Args:
+            value: Token or plain name
+
+        Returns:
+            Real name if the token is known, otherwise the value unchanged
+        """
+        if not self.enabled or not value:
+            return value
+
+        if value not in self._reverse:
+            # Token may have been issued by the other MCP server
+            self._reverse.update(load_mapping(self.map_path))
+
+        return self._reverse.get(value, value)
+
+    def resolve_text(self, text: str) -> str:
+        """Resolve every token embedded in free text (e.g., a PromQL query)."""
+        if not self.enabled:
+            return text
+
+        return TOKEN_PATTERN.sub(lambda m: self.resolve(m.group(0)), text)
@@ -1,31 +1,137 @@
 """MCP server for Kubernetes read-only operations."""
 
 import json
+import os
+import re
+import sqlite3
 import sys
+from datetime import datetime, timedelta, timezone
 from typing import Optional
 
 from mcp.server.fastmcp import FastMCP
 from kubernetes import client, config
 from kubernetes.client import ApiException
+from kubernetes.utils import parse_quantity
+
+from anonymizer import Anonymizer
+from namespaces import namespace_in_scope, parse_patterns
+from offline import OfflineApiClient
 
 
 mcp = FastMCP("kubernetes")
 
 # Initialize Kubernetes client at module level
-try:
-    config.load_incluster_config()
-    print("Loaded in-cluster Kubernetes config", file=sys.stderr)
-except config.ConfigException:
-    config.load_kube_config()
-    print("Loaded kubeconfig", file=sys.stderr)
+api_client = None
+if os.environ.get("OFFLINE_MANIFESTS_PATH"):
+    # Offline audit: answer from exported manifests instead of an API server
+    api_client = OfflineApiClient(os.environ["OFFLINE_MANIFESTS_PATH"])
+else:
+    try:
+        config.load_incluster_config()
+        print("Loaded in-cluster Kubernetes config", file=sys.stderr)
+    except config.ConfigException:
+        config.load_kube_config()
+        print("Loaded kubeconfig", file=sys.stderr)
+
+core_v1 = client.CoreV1Api(api_client)
+apps_v1 = client.AppsV1Api(api_client)
+events_v1 = client.EventsV1Api(api_client)
+custom_objects = client.CustomObjectsApi(api_client)
+
+IMAGE_PULL_REASONS = {"ErrImagePull", "ImagePullBackOff", "InvalidImageName", "ErrImageNeverPull"}
+EVENT_IMAGE_PATTERN = re.compile(r'image "([^"]+)"')
+
+# Substrings of pull errors -> failure category
+PULL_ERROR_CATEGORIES = [
+    ("rate_limited", ("toomanyrequests", "429", "rate limit")),
+    ("auth", ("unauthorized", "denied", "401", "403", "authentication required")),
+    ("not_found", ("not found", "manifest unknown", "name unknown")),
+    ("registry_unreachable", (
+        "timeout", "connection refused", "no such host", "i/o timeout",
+        "503", "502", "500 internal server error", "tls handshake",
+    )),
+]
+
+WATCHDOG_DB_PATH = os.environ.get("WATCHDOG_DB_PATH", "")
+CLUSTER_NAME = os.environ.get("CLUSTER_NAME", "default")
+POD_LABEL_KEYS = [key.strip() for key in os.environ.get("POD_LABEL_ALLOWLIST", "").split(",") if key.strip()]
+# Cluster-wide listings leave out namespaces filtered by NAMESPACES_EXCLUDE/INCLUDE
+NAMESPACES_EXCLUDE = parse_patterns(os.environ.get("NAMESPACES_EXCLUDE", ""))
+NAMESPACES_INCLUDE = parse_patterns(os.environ.get("NAMESPACES_INCLUDE", ""))
+
+# Privacy mode: pseudonymize names and drop free-text event messages
+anonymizer = Anonymizer.from_env()
+
+
+def _in_scope(items: list) -> list:
+    """Drop API objects in namespaces filtered out by the include/exclude patterns."""
+    return [
+        item for item in items
+        if namespace_in_scope(item.metadata.namespace, NAMESPACES_INCLUDE, NAMESPACES_EXCLUDE)
+    ]
+
+
+def _custom_in_scope(items: list[dict]) -> list[dict]:
+    """Drop custom objects (plain dicts) in namespaces filtered out by the include/exclude patterns."""
+    return [
+        item for item in items
+        if namespace_in_scope(item["metadata"].get("namespace"), NAMESPACES_INCLUDE, NAMESPACES_EXCLUDE)
+    ]
+
 
-core_v1 = client.CoreV1Api()
-apps_v1 = client.AppsV1Api()
+def _event_object_name(kind: Optional[str], name: Optional[str]) -> Optional[str]:
+    """Return the involved object name, pseudonymized when privacy mode is on."""
+    token_kind = {"Pod": "pod", "Node": "node"}.get(kind, "workload")
+    return anonymizer.token(token_kind, name)
+
+
+def _pod_os(pod, node_os: dict[str, str]) -> str:
+    """Resolve a pod's OS from spec.os, its nodeSelector, or the node it runs on."""
+    if pod.spec.os and pod.spec.os.name:
+        return pod.spec.os.name
+    selector_os = (pod.spec.node_selector or {}).get("kubernetes.io/os")
+    if selector_os:
+        return selector_os
+    return node_os.get(pod.spec.node_name, "linux")
+
+
+def _init_status(pod) -> Optional[str]:
+    """Return kubectl's Init:<reason> / Init:<done>/<total> status while init containers run."""
+    statuses = pod.status.init_container_statuses or []
+    for index, cs in enumerate(statuses):
+        if cs.state.terminated and cs.state.terminated.exit_code == 0:
+            continue
+        if cs.state.terminated:
+            return f"Init:{cs.state.terminated.reason or 'Error'}"
+        if cs.state.waiting and cs.state.waiting.reason and cs.state.waiting.reason != "PodInitializing":
+            return f"Init:{cs.state.waiting.reason}"
+        return f"Init:{index}/{len(statuses)}"
+    return None
+
+
+def _container_state(state) -> dict:
+    """Summarize a container state as {state, reason, exit_code, signal}."""
+    if state.running:
+        return {"state": "running"}
+    if state.terminated:
+        return {
+            "state": "terminated",
+            "reason": state.terminated.reason,
+            "exit_code": state.terminated.exit_code,
+            "signal": state.terminated.signal,
+        }
+    if state.waiting:
+        return {"state": "waiting", "reason": state.waiting.reason}
+    return {"state": "unknown"}
 
 
 @mcp.tool()
 def kubectl_get_pods(namespace: Optional[str] = None, label_selector: Optional[str] = None) -> str:
-    """List pods in a namespace. Returns pod names, status, restarts, and age."""
+    """List pods in a namespace. Returns pod names, status, restarts, and age.
+
+    Pods stuck in the init phase report kubectl's status (e.g., Init:CrashLoopBackOff)
+    and pods with running ephemeral debug containers list them.
+    """
     try:
         if namespace:
             pods = core_v1.list_namespaced_pod(
@@ -36,18 +142,45 @@ def kubectl_get_pods(namespace: Optional[str] = None, label_selector: Optional[s
             pods = core_v1.list_pod_for_all_namespaces(
                 label_selector=label_selector or ""
             )
+            pods.items = _in_scope(pods.items)
+
+        node_os = {
+            node.metadata.name: node.status.node_info.operating_system
+            for node in core_v1.list_node().items
+        }
 
         result = []
         for pod in pods.items:
-            restarts = sum(cs.restart_count for cs in pod.status.container_statuses or [])
-            result.append({
-                "name": pod.metadata.name,
+            restarts = sum(
+                cs.restart_count
+                for cs in (pod.status.container_statuses or []) + (pod.status.init_container_statuses or [])
+            )
+            entry = {
+                "name": anonymizer.token("pod", pod.metadata.name),
                 "namespace": pod.metadata.namespace,
-                "status": pod.status.phase,
+                "status": _init_status(pod) or pod.status.phase,
                 "restarts": restarts,
-                "node": pod.spec.node_name,
+                "node": anonymizer.token("node", pod.spec.node_name),
+                "os": _pod_os(pod, node_os),
                 "age": str(pod.metadata.creation_timestamp)
-            })
+            }
+            # Allowlisted labels (team, app, version); values may contain real names
+            if POD_LABEL_KEYS and not anonymizer.enabled:
+                labels = pod.metadata.labels or {}
+                annotations = pod.metadata.annotations or {}
+                captured = {
+                    key: labels.get(key, annotations.get(key))
+                    for key in POD_LABEL_KEYS
+                    if labels.get(key, annotations.get(key)) is not None
+                }
+                if captured:
+                    entry["labels"] = captured
+            debug_containers = [
+                cs.name for cs in pod.status.ephemeral_container_statuses or [] if cs.state.running
+            ]
+            if debug_containers:
+                entry["running_debug_containers"] = debug_containers
+            result.append(entry)
 
         return json.dumps(result, indent=2)
     except ApiException as e:
@@ -56,18 +189,32 @@ def kubectl_get_pods(namespace: Optional[str] = None, label_selector: Optional[s
 
 @mcp.tool()
 def kubectl_get_nodes() -> str:
-    """List cluster nodes with status, roles, age, and version."""
+    """List cluster nodes with status, roles, age, version, OS and container runtime.
+
+    Pressure conditions that are currently active are listed per node.
+    """
     try:
         nodes = core_v1.list_node()
 
         result = []
         for node in nodes.items:
             conditions = {c.type: c.status for c in node.status.conditions}
+            node_info = node.status.node_info
+            labels = node.metadata.labels or {}
             result.append({
-                "name": node.metadata.name,
+                "name": anonymizer.token("node", node.metadata.name),
                 "status": "Ready" if conditions.get("Ready") == "True" else "NotReady",
-                "roles": node.metadata.labels.get("node-role.kubernetes.io/control-plane", "worker"),
-                "version": node.status.node_info.kubelet_version,
+                "roles": labels.get("node-role.kubernetes.io/control-plane", "worker"),
+                "version": node_info.kubelet_version,
+                "os": node_info.operating_system,
+                "os_image": node_info.os_image,
+                "windows_build": labels.get("node.kubernetes.io/windows-build"),
+                "architecture": node_info.architecture,
+                "container_runtime": node_info.container_runtime_version,
+                "active_conditions": [
+                    condition for condition, status in conditions.items()
+                    if condition != "Ready" and status == "True"
+                ],
                 "age": str(node.metadata.creation_timestamp)
             })
 
@@ -76,10 +223,52 @@ def kubectl_get_nodes() -> str:
         return f"Kubernetes API error: {e.reason}"
 
 
+@mcp.tool()
+def kubectl_top_nodes() -> str:
+    """Current CPU and memory usage per node (metrics-server) against allocatable capacity.
+
+    Use it to judge real node utilization rather than only requested resources.
+    """
+    try:
+        metrics = custom_objects.list_cluster_custom_object("metrics.k8s.io", "v1beta1", "nodes")
+        nodes = core_v1.list_node()
+    except ApiException as e:
+        if e.status == 404:
+            return "metrics-server is not installed in this cluster"
+        return f"Kubernetes API error: {e.reason}"
+
+    allocatable = {node.metadata.name: node.status.allocatable or {} for node in nodes.items}
+
+    result = []
+    for item in metrics.get("items", []):
+        name = item["metadata"]["name"]
+        cpu = float(parse_quantity(item["usage"].get("cpu", "0")))
+        memory = float(parse_quantity(item["usage"].get("memory", "0")))
+        node_cpu = float(parse_quantity(allocatable.get(name, {}).get("cpu", "0")))
+        node_memory = float(parse_quantity(allocatable.get(name, {}).get("memory", "0")))
+        result.append({
+            "name": anonymizer.token("node", name),
+            "cpu_usage_cores": round(cpu, 3),
+            "cpu_allocatable_cores": node_cpu,
+            "cpu_usage_pct": round(cpu / node_cpu * 100, 1) if node_cpu else None,
+            "memory_usage_mib": round(memory / 1024 / 1024),
+            "memory_allocatable_mib": round(node_memory / 1024 / 1024),
+            "memory_usage_pct": round(memory / node_memory * 100, 1) if node_memory else None,
+            "window": item.get("window"),
+        })
+
+    return json.dumps(result, indent=2)
+
+
 @mcp.tool()
 def kubectl_describe_pod(name: str, namespace: str) -> str:
-    """Get detailed information about a specific pod including events, conditions, and container states."""
+    """Get detailed information about a specific pod including events, conditions, and container states.
+
+    Includes init containers (which explain Pending and Init:* pods) and ephemeral
+    debug containers attached with kubectl debug.
+    """
     try:
+        name = anonymizer.resolve(name)
         pod = core_v1.read_namespaced_pod(name=name, namespace=namespace)
         events = core_v1.list_namespaced_event(
             namespace=namespace,
@@ -87,7 +276,7 @@ def kubectl_describe_pod(name: str, namespace: str) -> str:
         )
 
         result = {
-            "name": pod.metadata.name,
+            "name": anonymizer.token("pod", pod.metadata.name),
             "namespace": pod.metadata.namespace,
             "status": pod.status.phase,
             "conditions": [
@@ -103,11 +292,40 @@ def kubectl_describe_pod(name: str, namespace: str) -> str:
                 }
                 for cs in pod.status.container_statuses or []
             ],
+            "init_containers": [
+                {
+                    "name": cs.name,
+                    "ready": cs.ready,
+                    "restarts": cs.restart_count,
+                    **_container_state(cs.state),
+                    "last_termination": (
+                        _container_state(cs.last_state)
+                        if cs.last_state and cs.last_state.terminated else None
+                    ),
+                }
+                for cs in pod.status.init_container_statuses or []
+            ],
+            "ephemeral_containers": [
+                {
+                    "name": container.name,
+                    "image": _anonymize_image(container.image),
+                    "target_container": container.target_container_name,
+                    **next(
+                        (
+                            _container_state(cs.state)
+                            for cs in pod.status.ephemeral_container_statuses or []
+                            if cs.name == container.name
+                        ),
+                        {"state": "unknown"},
+                    ),
+                }
+                for container in pod.spec.ephemeral_containers or []
+            ],
             "events": [
                 {
                     "type": e.type,
                     "reason": e.reason,
-                    "message": e.message,
+                    "message": None if anonymizer.enabled else e.message,
                     "time": str(e.last_timestamp)
                 }
                 for e in events.items[-10:]
@@ -120,28 +338,114 @@ def kubectl_describe_pod(name: str, namespace: str) -> str:
 
 
 @mcp.tool()
-def kubectl_get_events(namespace: Optional[str] = None, limit: int = 50) -> str:
-    """Get recent events in a namespace, useful for debugging issues."""
+def kubectl_get_pod_logs(
+    name: str,
+    namespace: str,
+    container: Optional[str] = None,
+    tail_lines: int = 50,
+    previous: bool = False,
+) -> str:
+    """Get the last log lines of a pod container (use previous=True for the crashed instance)."""
+    if anonymizer.enabled:
+        return "Logs are withheld in privacy mode"
+
+    try:
+        logs = core_v1.read_namespaced_pod_log(
+            name=name,
+            namespace=namespace,
+            container=container,
+            tail_lines=min(max(tail_lines, 1), 200),
+            previous=previous,
+        )
+    except ApiException as e:
+        return f"Kubernetes API error: {e.reason}"
+
+    # Keep excerpts short: very long lines are usually JSON payloads or stack dumps
+    lines = [line[:500] for line in (logs or "").splitlines()]
+    return "\n".join(lines) or "No log output"
+
+
+def _list_events(namespace: Optional[str] = None) -> list[dict]:
+    """List events normalized across events.k8s.io/v1 and core/v1.
+
+    Prefers events.k8s.io/v1, whose EventSeries carries the real occurrence count
+    of repeated events, and falls back to core/v1 on clusters without it.
+    """
     try:
         if namespace:
-            events = core_v1.list_namespaced_event(namespace=namespace)
+            events = events_v1.list_namespaced_event(namespace=namespace)
         else:
-            events = core_v1.list_event_for_all_namespaces()
+            events = events_v1.list_event_for_all_namespaces()
+            events.items = _in_scope(events.items)
+
+        return [
+            {
+                "type": e.type,
+                "reason": e.reason,
+                "message": e.note,
+                "kind": e.regarding.kind if e.regarding else None,
+                "name": e.regarding.name if e.regarding else None,
+                "namespace": e.metadata.namespace,
+                "count": e.series.count if e.series else (e.deprecated_count or 1),
+                "time": (
+                    (e.series.last_observed_time if e.series else None)
+                    or e.event_time
+                    or e.deprecated_last_timestamp
+                    or e.metadata.creation_timestamp
+                ),
+            }
+            for e in events.items
+        ]
+    except ApiException as e:
+        if e.status not in (403, 404):
+            raise
+        print(f"events.k8s.io/v1 unavailable ({e.status}), using core/v1", file=sys.stderr)
+
+    if namespace:
+        events = core_v1.list_namespaced_event(namespace=namespace)
+    else:
+        events = core_v1.list_event_for_all_namespaces()
+        events.items = _in_scope(events.items)
+
+    return [
+        {
+            "type": e.type,
+            "reason": e.reason,
+            "message": e.message,
+            "kind": e.involved_object.kind,
+            "name": e.involved_object.name,
+            "namespace": e.metadata.namespace,
+            "count": (e.series.count if e.series else None) or e.count or 1,
+            "time": e.last_timestamp or e.event_time or e.metadata.creation_timestamp,
+        }
+        for e in events.items
+    ]
+
+
+@mcp.tool()
+def kubectl_get_events(namespace: Optional[str] = None, limit: int = 50) -> str:
+    """Get recent events in a namespace, useful for debugging issues.
+
+    Each event includes how many times it occurred (series count).
+    """
+    try:
+        events = _list_events(namespace)
 
         sorted_events = sorted(
-            events.items,
-            key=lambda e: e.last_timestamp or e.event_time,
+            events,
+            key=lambda e: str(e["time"] or ""),
             reverse=True
         )[:limit]
 
         result = [
             {
-                "type": e.type,
-                "reason": e.reason,
-                "message": e.message,
-                "object": f"{e.involved_object.kind}/{e.involved_object.name}",
-                "namespace": e.metadata.namespace,
-                "time": str(e.last_timestamp or e.event_time)
+                "type": e["type"],
+                "reason": e["reason"],
+                "message": None if anonymizer.enabled else e["message"],
+                "object": f"{e['kind']}/{_event_object_name(e['kind'], e['name'])}",
+                "namespace": e["namespace"],
+                "count": e["count"],
+                "time": str(e["time"])
             }
             for e in sorted_events
         ]
@@ -151,6 +455,226 @@ def kubectl_get_events(namespace: Optional[str] = None, limit: int = 50) -> str:
         return f"Kubernetes API error: {e.reason}"
 
 
+def _image_registry(image: str) -> str:
+    """Return the registry host of an image reference (docker.io when implicit)."""
+    first, _, rest = image.partition("/")
+    if rest and ("." in first or ":" in first or first == "localhost"):
+        return first
+    return "docker.io"
+
+
+def _anonymize_image(image: Optional[str]) -> Optional[str]:
+    """Tokenize the registry and repository of an image reference, keeping its tag or digest."""
+    if not anonymizer.enabled or not image:
+        return image
+    name, at, digest = image.partition("@")
+    suffix = f"@{digest}" if at else ""
+    repository, colon, tag = name.rpartition(":")
+    if colon and "/" not in tag:
+        name, suffix = repository, f":{tag}{suffix}"
+    registry = _image_registry(name)
+    path = name.split("/", 1)[1] if name.startswith(f"{registry}/") else name
+    return f"{anonymizer.token('registry', registry)}/{anonymizer.token('image', path)}{suffix}"
+
+
+def _pull_error_category(message: Optional[str]) -> str:
+    """Classify an image pull error message."""
+    text = (message or "").lower()
+    for category, needles in PULL_ERROR_CATEGORIES:
+        if any(needle in text for needle in needles):
+            return category
+    return "unknown"
+
+
+@mcp.tool()
+def get_image_pull_failures(namespace: Optional[str] = None) -> str:
+    """Aggregate image pull failures (ErrImagePull/ImagePullBackOff) by registry and image.
+
+    Combines pods currently stuck pulling with recent pull-failure events. A registry is
+    flagged as a likely outage when several different images from it fail with
+    connectivity or rate-limit errors; otherwise failures point to individual bad tags
+    or credentials.
+    """
+    try:
+        if namespace:
+            pods = core_v1.list_namespaced_pod(namespace=namespace)
+        else:
+            pods = core_v1.list_pod_for_all_namespaces()
+            pods.items = _in_scope(pods.items)
+        events = _list_events(namespace)
+    except ApiException as e:
+        return f"Kubernetes API error: {e.reason}"
+
+    images: dict[str, dict] = {}
+
+    def _entry(image: str) -> dict:
+        return images.setdefault(image, {
+            "image": _anonymize_image(image),
+            "registry": anonymizer.token("registry", _image_registry(image)),
+            "pods": set(),
+            "namespaces": set(),
+            "reasons": set(),
+            "categories": set(),
+            "event_count": 0,
+            "sample_error": None,
+        })
+
+    for pod in pods.items:
+        statuses = (pod.status.init_container_statuses or []) + (pod.status.container_statuses or [])
+        for cs in statuses:
+            waiting = cs.state.waiting if cs.state else None
+            if not waiting or waiting.reason not in IMAGE_PULL_REASONS:
+                continue
+            entry = _entry(cs.image)
+            entry["pods"].add(anonymizer.token("pod", pod.metadata.name))
+            entry["namespaces"].add(pod.metadata.namespace)
+            entry["reasons"].add(waiting.reason)
+            entry["categories"].add(_pull_error_category(waiting.message))
+            entry["sample_error"] = entry["sample_error"] or waiting.message
+
+    for event in events:
+        if event["reason"] not in ("Failed", "BackOff") or event["kind"] != "Pod":
+            continue
+        match = EVENT_IMAGE_PATTERN.search(event["message"] or "")
+        if not match or "pull" not in (event["message"] or "").lower():
+            continue
+        entry = _entry(match.group(1))
+        entry["namespaces"].add(event["namespace"])
+        entry["event_count"] += event["count"] or 1
+        entry["categories"].add(_pull_error_category(event["message"]))
+        entry["sample_error"] = entry["sample_error"] or event["message"]
+
+    if not images:
+        return "No image pull failures found"
+
+    registries: dict[str, dict] = {}
+    for entry in images.values():
+        if len(entry["categories"]) > 1:
+            entry["categories"].discard("unknown")
+        registry = registries.setdefault(entry["registry"], {
+            "registry": entry["registry"],
+            "failing_images": 0,
+            "affected_pods": 0,
+            "categories": set(),
+            "images": [],
+        })
+        registry["failing_images"] += 1
+        registry["affected_pods"] += len(entry["pods"])
+        registry["categories"].update(entry["categories"])
+        registry["images"].append({
+            "image": entry["image"],
+            "pods": sorted(entry["pods"])[:10],
+            "affected_pods": len(entry["pods"]),
+            "namespaces": sorted(entry["namespaces"]),
+            "reasons": sorted(entry["reasons"]),
+            "categories": sorted(entry["categories"]),
+            "event_count": entry["event_count"],
+            "sample_error": None if anonymizer.enabled else entry["sample_error"],
+        })
+
+    result = []
+    for registry in sorted(registries.values(), key=lambda r: r["affected_pods"], reverse=True):
+        outage_signals = registry["categories"] & {"registry_unreachable", "rate_limited"}
+        registry["diagnosis"] = (
+            "likely_registry_outage"
+            if registry["failing_images"] >= 2 and outage_signals
+            else "image_specific"
+        )
+        registry["categories"] = sorted(registry["categories"])
+        result.append(registry)
+
+    return json.dumps(result, indent=2)
+
+
+def _runs_as_non_root(pod, container) -> bool:
+    """Whether a container is forced to run as non-root (container or pod level)."""
+    container_ctx = container.security_context
+    if container_ctx and container_ctx.run_as_non_root is not None:
+        return container_ctx.run_as_non_root
+    if container_ctx and container_ctx.run_as_user not in (None, 0):
+        return True
+    pod_ctx = pod.spec.security_context
+    if pod_ctx and pod_ctx.run_as_non_root is not None:
+        return pod_ctx.run_as_non_root
+    return bool(pod_ctx and pod_ctx.run_as_user not in (None, 0))
+
+
+@mcp.tool()
+def audit_pod_security(namespace: Optional[str] = None) -> str:
+    """Audit Pod Security Admission labels per namespace and pod security contexts.
+
+    Flags namespaces enforcing the `privileged` level (or no level at all) and pods
+    with containers missing runAsNonRoot, running privileged, or sharing host namespaces.
+    """
+    try:
+        if namespace:
+            namespaces = [core_v1.read_namespace(namespace)]
+            pods = core_v1.list_namespaced_pod(namespace=namespace).items
+        else:
+            namespaces = [
+                ns for ns in core_v1.list_namespace().items
+                if namespace_in_scope(ns.metadata.name, NAMESPACES_INCLUDE, NAMESPACES_EXCLUDE)
+            ]
+            pods = _in_scope(core_v1.list_pod_for_all_namespaces().items)
+    except ApiException as e:
+        return f"Kubernetes API error: {e.reason}"
+
+    namespace_result = []
+    for ns in namespaces:
+        labels = ns.metadata.labels or {}
+        levels = {
+            mode: labels.get(f"pod-security.kubernetes.io/{mode}")
+            for mode in ("enforce", "audit", "warn")
+        }
+        namespace_result.append({
+            "namespace": ns.metadata.name,
+            **levels,
+            "flag": (
+                "privileged" if levels["enforce"] == "privileged"
+                else "no_enforce_label" if not levels["enforce"]
+                else None
+            ),
+        })
+
+    pod_findings = []
+    for pod in pods:
+        issues = set()
+        if pod.spec.host_network:
+            issues.add("hostNetwork")
+        if pod.spec.host_pid:
+            issues.add("hostPID")
+        if pod.spec.host_ipc:
+            issues.add("hostIPC")
+
+        containers = (pod.spec.init_containers or []) + (pod.spec.containers or [])
+        missing_non_root = []
+        for container in containers:
+            ctx = container.security_context
+            if not _runs_as_non_root(pod, container):
+                missing_non_root.append(container.name)
+            if ctx and ctx.privileged:
+                issues.add(f"privileged:{container.name}")
+            if not ctx or ctx.allow_privilege_escalation is not False:
+                issues.add(f"allowPrivilegeEscalation:{container.name}")
+
+        if missing_non_root:
+            issues.add(f"missing runAsNonRoot: {', '.join(missing_non_root)}")
+
+        if issues:
+            pod_findings.append({
+                "namespace": pod.metadata.namespace,
+                "pod": anonymizer.token("pod", pod.metadata.name),
+                "issues": sorted(issues),
+            })
+
+    return json.dumps({
+        "namespaces": namespace_result,
+        "flagged_namespaces": sum(1 for ns in namespace_result if ns["flag"]),
+        "pods_with_issues": len(pod_findings),
+        "pods": pod_findings[:100],
+    }, indent=2)
+
+
 @mcp.tool()
 def kubectl_get_deployments(namespace: Optional[str] = None) -> str:
     """List deployments with replicas status."""
@@ -159,10 +683,11 @@ def kubectl_get_deployments(namespace: Optional[str] = None) -> str:
             deployments = apps_v1.list_namespaced_deployment(namespace=namespace)
         else:
             deployments = apps_v1.list_deployment_for_all_namespaces()
+            deployments.items = _in_scope(deployments.items)
 
         result = [
             {
-                "name": d.metadata.name,
+                "name": anonymizer.token("workload", d.metadata.name),
                 "namespace": d.metadata.namespace,
                 "replicas": d.spec.replicas,
                 "available": d.status.available_replicas or 0,
@@ -177,5 +702,311 @@ def kubectl_get_deployments(namespace: Optional[str] = None) -> str:
         return f"Kubernetes API error: {e.reason}"
 
 
+@mcp.tool()
+def argo_get_rollouts(namespace: Optional[str] = None) -> str:
+    """List Argo Rollouts with phase, strategy, canary step and recent AnalysisRun results.
+
+    Use it to attribute post-deploy instability to in-progress canary or blue/green rollouts.
+    """
+    try:
+        if namespace:
+            rollouts = custom_objects.list_namespaced_custom_object(
+                "argoproj.io", "v1alpha1", namespace, "rollouts"
+            )
+            runs = custom_objects.list_namespaced_custom_object(
+                "argoproj.io", "v1alpha1", namespace, "analysisruns"
+            )
+        else:
+            rollouts = custom_objects.list_cluster_custom_object("argoproj.io", "v1alpha1", "rollouts")
+            runs = custom_objects.list_cluster_custom_object("argoproj.io", "v1alpha1", "analysisruns")
+    except ApiException as e:
+        if e.status == 404:
+            return "Argo Rollouts is not installed in this cluster"
+        return f"Kubernetes API error: {e.reason}"
+
+    # Latest analysis runs per rollout (owner reference)
+    runs_by_rollout: dict[tuple[str, str], list[dict]] = {}
+    for run in _custom_in_scope(runs.get("items", [])):
+        metadata = run["metadata"]
+        owner = next(
+            (ref["name"] for ref in metadata.get("ownerReferences", []) if ref["kind"] == "Rollout"),
+            None,
+        )
+        if not owner:
+            continue
+        status = run.get("status", {})
+        runs_by_rollout.setdefault((metadata["namespace"], owner), []).append({
+            # Run names embed the rollout name
+            "name": anonymizer.token("workload", metadata["name"]),
+            "phase": status.get("phase"),
+            "message": None if anonymizer.enabled else status.get("message"),
+            "started": metadata.get("creationTimestamp"),
+            "metrics": [
+                {
+                    "name": m.get("name"),
+                    "phase": m.get("phase"),
+                    "failed": m.get("failed", 0),
+                    "inconclusive": m.get("inconclusive", 0),
+                }
+                for m in status.get("metricResults", [])
+            ],
+        })
+
+    result = []
+    for rollout in _custom_in_scope(rollouts.get("items", [])):
+        metadata = rollout["metadata"]
+        spec = rollout.get("spec", {})
+        status = rollout.get("status", {})
+        strategy = spec.get("strategy", {})
+        canary_steps = strategy.get("canary", {}).get("steps", [])
+        analysis_runs = sorted(
+            runs_by_rollout.get((metadata["namespace"], metadata["name"]), []),
+            key=lambda r: r["started"] or "",
+            reverse=True,
+        )
+
+        result.append({
+            "name": anonymizer.token("workload", metadata["name"]),
+            "namespace": metadata["namespace"],
+            "strategy": "canary" if "canary" in strategy else "blueGreen" if "blueGreen" in strategy else None,
+            "phase": status.get("phase"),
+            "message": None if anonymizer.enabled else status.get("message"),
+            "paused": bool(spec.get("paused") or status.get("pauseConditions")),
+            "current_step": status.get("currentStepIndex"),
+            "total_steps": len(canary_steps) if canary_steps else None,
+            "stable_revision": status.get("stableRS"),
+            "current_revision": status.get("currentPodHash"),
+            "replicas": status.get("replicas", 0),
+            "updated": status.get("updatedReplicas", 0),
+            "available": status.get("availableReplicas", 0),
+            "analysis_runs": analysis_runs[:3],
+        })
+
+    return json.dumps(result, indent=2)
+
+
+def _condition(obj: dict, condition_type: str) -> dict:
+    """Return a custom resource status condition by type (empty dict if absent)."""
+    return next(
+        (c for c in obj.get("status", {}).get("conditions", []) if c.get("type") == condition_type),
+        {},
+    )
+
+
+@mcp.tool()
+def certmanager_get_certificates(namespace: Optional[str] = None, expiring_within_days: int = 30) -> str:
+    """List cert-manager Certificates that are not ready, failing to renew or expiring soon.
+
+    Includes the renewal time, failed issuance attempts and the latest CertificateRequest
+    outcome, so a certificate that cannot renew shows up even while its TLS secret is still valid.
+    """
+    try:
+        if namespace:
+            certificates = custom_objects.list_namespaced_custom_object(
+                "cert-manager.io", "v1", namespace, "certificates"
+            )
+            requests = custom_objects.list_namespaced_custom_object(
+                "cert-manager.io", "v1", namespace, "certificaterequests"
+            )
+        else:
+            certificates = custom_objects.list_cluster_custom_object("cert-manager.io", "v1", "certificates")
+            requests = custom_objects.list_cluster_custom_object("cert-manager.io", "v1", "certificaterequests")
+    except ApiException as e:
+        if e.status == 404:
+            return "cert-manager is not installed in this cluster"
+        return f"Kubernetes API error: {e.reason}"
+
+    # Latest CertificateRequest per certificate (owner reference)
+    latest_request: dict[tuple[str, str], dict] = {}
+    for request in _custom_in_scope(requests.get("items", [])):
+        metadata = request["metadata"]
+        owner = next(
+            (ref["name"] for ref in metadata.get("ownerReferences", []) if ref["kind"] == "Certificate"),
+            None,
+        )
+        if not owner:
+            continue
+        key = (metadata["namespace"], owner)
+        previous = latest_request.get(key)
+        if not previous or metadata.get("creationTimestamp", "") > previous["metadata"].get("creationTimestamp", ""):
+            latest_request[key] = request
+
+    now = datetime.now(timezone.utc)
+    items = _custom_in_scope(certificates.get("items", []))
+    result = []
+    for certificate in items:
+        metadata = certificate["metadata"]
+        spec = certificate.get("spec", {})
+        status = certificate.get("status", {})
+        ready = _condition(certificate, "Ready")
+
+        days_left = None
+        if status.get("notAfter"):
+            not_after = datetime.fromisoformat(status["notAfter"].replace("Z", "+00:00"))
+            days_left = round((not_after - now).total_seconds() / 86400, 1)
+
+        problems = []
+        if ready.get("status") != "True":
+            problems.append("not_ready")
+        if status.get("failedIssuanceAttempts") or status.get("lastFailureTime"):
+            problems.append("issuance_failing")
+        if days_left is not None and days_left <= expiring_within_days:
+            problems.append("expiring")
+        if not problems:
+            continue
+
+        request = latest_request.get((metadata["namespace"], metadata["name"]))
+        request_ready = _condition(request, "Ready") if request else {}
+        result.append({
+            # Certificate, secret and request names usually embed the application name
+            "name": anonymizer.token("workload", metadata["name"]),
+            "namespace": metadata["namespace"],
+            "problems": problems,
+            "secret_name": anonymizer.token("workload", spec.get("secretName")),
+            "issuer": f"{spec.get('issuerRef', {}).get('kind', 'Issuer')}/{spec.get('issuerRef', {}).get('name')}",
+            "dns_names": None if anonymizer.enabled else spec.get("dnsNames"),
+            "ready_reason": ready.get("reason"),
+            "ready_message": None if anonymizer.enabled else ready.get("message"),
+            "not_after": status.get("notAfter"),
+            "days_until_expiry": days_left,
+            "renewal_time": status.get("renewalTime"),
+            "failed_issuance_attempts": status.get("failedIssuanceAttempts"),
+            "last_failure_time": status.get("lastFailureTime"),
+            "latest_request": {
+                "name": anonymizer.token("workload", request["metadata"]["name"]),
+                "ready_reason": request_ready.get("reason"),
+                "ready_message": None if anonymizer.enabled else request_ready.get("message"),
+                "denied": _condition(request, "Denied").get("status") == "True",
+            } if request else None,
+        })
+
+    if not result:
+        return f"All {len(items)} certificates are ready and valid for more than {expiring_within_days} days"
+
+    result.sort(key=lambda c: c["days_until_expiry"] if c["days_until_expiry"] is not None else -1)
+
+    return json.dumps({"total_certificates": len(items), "problems": result}, indent=2)
+
+
+def _transition_details(details: dict) -> dict:
+    """Pseudonymize stored pod details and withhold free-text messages in privacy mode."""
+    if not anonymizer.enabled:
+        return details
+    details["node"] = anonymizer.token("node", details.get("node"))
+    for section in ("conditions", "containers", "events", "node_conditions"):
+        for entry in details.get(section) or []:
+            for key in ("message", "waiting_message"):
+                if key in entry:
+                    entry[key] = None
+    return details
+
+
+@mcp.tool()
+def get_pod_transitions(
+    namespace: Optional[str] = None,
+    hours: int = 168,
+    limit: int = 100,
+    pod: Optional[str] = None,
+    include_details: bool = False,
+) -> str:
+    """Get pod phase changes and container terminations recorded by the pod watcher.
+
+    Catches short-lived failures (crashes, OOMKills, evictions) of pods that may no
+    longer exist in the cluster. Defaults to the last 7 days. Set include_details
+    (ideally with a pod) to get describe-like detail captured when the pod was
+    unhealthy: conditions, container states, tolerations, volumes, its events and
+    its node's conditions.
+    """
+    if not WATCHDOG_DB_PATH or not os.path.exists(WATCHDOG_DB_PATH):
+        return "Pod transition history not available"
+
+    since = (datetime.now() - timedelta(hours=hours)).isoformat()
+    query = """
+        SELECT namespace, pod, container, transition_type, from_phase, to_phase,
+               reason, exit_code, signal, observed_at, details
+        FROM pod_transitions
+        WHERE cluster_name = ? AND observed_at >= ?
+    """
+    params: list = [CLUSTER_NAME, since]
+    if namespace:
+        query += " AND namespace = ?"
+        params.append(namespace)
+    if pod:
+        query += " AND pod = ?"
+        params.append(anonymizer.resolve(pod))
+    query += " ORDER BY observed_at DESC LIMIT ?"
+    params.append(limit)
+
+    try:
+        with sqlite3.connect(f"file:{WATCHDOG_DB_PATH}?mode=ro", uri=True) as db:
+            db.row_factory = sqlite3.Row
+            rows = db.execute(query, params).fetchall()
+    except sqlite3.Error as e:
+        return f"Pod transition history not available: {e}"
+
+    if not rows:
+        return "No pod transitions recorded in this period"
+
+    result = []
+    for row in rows:
+        transition = dict(row)
+        transition["pod"] = anonymizer.token("pod", transition["pod"])
+        details = transition.pop("details")
+        if include_details and details:
+            transition["details"] = _transition_details(json.loads(details))
+        result.append(transition)
+
+    return json.dumps(result, indent=2)
+
+
+@mcp.tool()
+def get_workload_dependencies(namespace: Optional[str] = None, workload: Optional[str] = None) -> str:
+    """Get inferred service-to-service dependencies (Service selectors + env var hostnames).
+
+    Pass a failing workload to see who depends on it (dependents) and what it depends on,
+    to write blast-radius notes such as "checkout depends on failing redis".
+    """
+    if not WATCHDOG_DB_PATH or not os.path.exists(WATCHDOG_DB_PATH):
+        return "Dependency graph not available"
+
+    # A token passed back by the model may stand for a workload or a Service name
+    workload = anonymizer.resolve(workload)
+
+    query = """
+        SELECT namespace, workload, kind, target_namespace, target_service,
+               target_workload, via
+        FROM workload_dependencies
+        WHERE cluster_name = ?
+    """
+    params: list = [CLUSTER_NAME]
+    if namespace:
+        query += " AND (namespace = ? OR target_namespace = ?)"
+        params.extend([namespace, namespace])
+    if workload:
+        query += " AND (workload = ? OR target_workload = ? OR target_service = ?)"
+        params.extend([workload, workload, workload])
+
+    try:
+        with sqlite3.connect(f"file:{WATCHDOG_DB_PATH}?mode=ro", uri=True) as db:
+            db.row_factory = sqlite3.Row
+            rows = db.execute(query, params).fetchall()
+    except sqlite3.Error as e:
+        return f"Dependency graph not available: {e}"
+
+    if not rows:
+        return "No dependencies inferred"
+
+    result = []
+    for row in rows:
+        edge = dict(row)
+        edge["workload"] = anonymizer.token("workload", edge["workload"])
+        edge["target_workload"] = anonymizer.token("workload", edge["target_workload"])
+        # Service names usually are the workload name, so they share its token
+        edge["target_service"] = anonymizer.token("workload", edge["target_service"])
+        result.append(edge)
+
+    return json.dumps(result, indent=2)
+
+
 if __name__ == "__main__":
     mcp.run(transport="stdio")
@@ -9,12 +9,33 @@ from typing import Optional
 import httpx
 from mcp.server.fastmcp import FastMCP
 
+from anonymizer import Anonymizer
+from namespaces import namespace_in_scope, parse_patterns
+
 
 mcp = FastMCP("prometheus")
 
 PROMETHEUS_URL = os.environ.get("PROMETHEUS_URL", "http://host.docker.internal:9090").rstrip("/")
 print(f"Prometheus URL: {PROMETHEUS_URL}", file=sys.stderr)
 
+# kube-state-metrics listings leave out namespaces filtered by NAMESPACES_EXCLUDE/INCLUDE
+NAMESPACES_EXCLUDE = parse_patterns(os.environ.get("NAMESPACES_EXCLUDE", ""))
+NAMESPACES_INCLUDE = parse_patterns(os.environ.get("NAMESPACES_INCLUDE", ""))
+
+# Privacy mode: pseudonymize names in label values
+anonymizer = Anonymizer.from_env()
+ANONYMIZED_LABELS = {"pod": "pod", "node": "node", "deployment": "workload"}
+
+
+def _anonymize_metric(metric: dict) -> dict:
+    """Replace pod/node/workload label values with tokens."""
+    if not anonymizer.enabled:
+        return metric
+    return {
+        label: anonymizer.token(ANONYMIZED_LABELS[label], value) if label in ANONYMIZED_LABELS else value
+        for label, value in metric.items()
+    }
+
 
 def _parse_duration(duration: str) -> int:
     """Parse duration string to seconds."""
@@ -28,6 +49,7 @@ def _parse_duration(duration: str) -> int:
 def prometheus_query(query: str) -> str:
     """Execute instant PromQL query. Returns current values of metrics."""
     try:
+        query = anonymizer.resolve_text(query)
         with httpx.Client(timeout=30.0) as client:
             response = client.get(
                 f"{PROMETHEUS_URL}/api/v1/query",
@@ -46,7 +68,7 @@ def prometheus_query(query: str) -> str:
             formatted = []
             for item in result:
                 formatted.append({
-                    "metric": item["metric"],
+                    "metric": _anonymize_metric(item["metric"]),
                     "value": item["value"][1]
                 })
 
@@ -61,6 +83,7 @@ def prometheus_query(query: str) -> str:
 def prometheus_query_range(query: str, duration: str = "1h", step: str = "1m") -> str:
     """Execute range PromQL query over a time period. Useful for trends."""
     try:
+        query = anonymizer.resolve_text(query)
         duration_seconds = _parse_duration(duration)
         end_time = int(time.time())
         start_time = end_time - duration_seconds
@@ -90,7 +113,7 @@ def prometheus_query_range(query: str, duration: str = "1h", step: str = "1m") -
                 values = [float(v[1]) for v in item["values"]]
                 if values:
                     formatted.append({
-                        "metric": item["metric"],
+                        "metric": _anonymize_metric(item["metric"]),
                         "min": min(values),
                         "max": max(values),
                         "avg": sum(values) / len(values),
@@ -109,6 +132,8 @@ def prometheus_query_range(query: str, duration: str = "1h", step: str = "1m") -
 def prometheus_check_pod_memory(pod: str, namespace: str) -> str:
     """Check memory usage vs limits for a specific pod. Helper to quickly identify OOM issues."""
     try:
+        pod_token = pod
+        pod = anonymizer.resolve(pod)
         queries = {
             "usage": f'container_memory_usage_bytes{{pod="{pod}", namespace="{namespace}", container!=""}}',
             "limit": f'kube_pod_container_resource_limits{{pod="{pod}", namespace="{namespace}", resource="memory"}}',
@@ -135,7 +160,7 @@ def prometheus_check_pod_memory(pod: str, namespace: str) -> str:
                     results[name] = f"Error: {str(e)}"
 
         analysis = {
-            "pod": pod,
+            "pod": pod_token,
             "namespace": namespace,
             "usage_bytes": results.get("usage", "N/A"),
             "limit_bytes": results.get("limit", "N/A"),
@@ -154,6 +179,8 @@ def prometheus_check_pod_memory(pod: str, namespace: str) -> str:
 def prometheus_check_pod_cpu(pod: str, namespace: str) -> str:
     """Check CPU usage vs limits/requests for a specific pod."""
     try:
+        pod_token = pod
+        pod = anonymizer.resolve(pod)
         queries = {
             "usage": f'rate(container_cpu_usage_seconds_total{{pod="{pod}", namespace="{namespace}", container!=""}}[5m])',
             "limit": f'kube_pod_container_resource_limits{{pod="{pod}", namespace="{namespace}", resource="cpu"}}',
@@ -180,7 +207,7 @@ def prometheus_check_pod_cpu(pod: str, namespace: str) -> str:
                     results[name] = f"Error: {str(e)}"
 
         analysis = {
-            "pod": pod,
+            "pod": pod_token,
             "namespace": namespace,
             "usage_cores": results.get("usage", "N/A"),
             "limit_cores": results.get("limit", "N/A"),
@@ -195,5 +222,162 @@ def prometheus_check_pod_cpu(pod: str, namespace: str) -> str:
         return f"Prometheus not available: {str(e)}"
 
 
+def _query_vector(client: httpx.Client, query: str) -> list[dict]:
+    """Run an instant query and return the raw result vector (empty on failure)."""
+    response = client.get(f"{PROMETHEUS_URL}/api/v1/query", params={"query": query})
+    response.raise_for_status()
+    data = response.json()
+    if data["status"] != "success":
+        return []
+    return data["data"]["result"]
+
+
+def _namespace_matcher(namespace: Optional[str]) -> str:
+    """Build a PromQL label matcher for an optional namespace."""
+    return f'{{namespace="{namespace}"}}' if namespace else ""
+
+
+def _in_scope(items: list[dict]) -> list[dict]:
+    """Drop series whose namespace label is filtered out by the include/exclude patterns."""
+    return [
+        item for item in items
+        if namespace_in_scope(item["metric"].get("namespace", ""), NAMESPACES_INCLUDE, NAMESPACES_EXCLUDE)
+    ]
+
+
+@mcp.tool()
+def ksm_get_pods(namespace: Optional[str] = None) -> str:
+    """List pods from kube-state-metrics: phase, restarts, node and waiting reason.
+
+    Alternative to kubectl_get_pods when the Kubernetes API is not accessible.
+    """
+    ns = _namespace_matcher(namespace)
+    pods: dict[tuple[str, str], dict] = {}
+
+    def pod_entry(metric: dict) -> dict:
+        key = (metric.get("namespace", ""), metric.get("pod", ""))
+        if key not in pods:
+            pods[key] = {
+                "name": anonymizer.token("pod", key[1]),
+                "namespace": key[0],
+                "status": None,
+                "restarts": 0,
+                "node": None,
+                "waiting_reasons": [],
+            }
+        return pods[key]
+
+    try:
+        with httpx.Client(timeout=30.0) as client:
+            for item in _in_scope(_query_vector(client, f"kube_pod_status_phase{ns} == 1")):
+                pod_entry(item["metric"])["status"] = item["metric"].get("phase")
+
+            for item in _in_scope(_query_vector(
+                client, f"sum by (namespace, pod) (kube_pod_container_status_restarts_total{ns})"
+            )):
+                pod_entry(item["metric"])["restarts"] = int(float(item["value"][1]))
+
+            for item in _in_scope(_query_vector(client, f"kube_pod_info{ns}")):
+                pod_entry(item["metric"])["node"] = anonymizer.token("node", item["metric"].get("node"))
+
+            for item in _in_scope(
+                _query_vector(client, f"kube_pod_container_status_waiting_reason{ns} == 1")
+            ):
+                pod_entry(item["metric"])["waiting_reasons"].append(item["metric"].get("reason"))
+
+    except httpx.ConnectError as e:
+        return f"Prometheus not available: {str(e)}"
+    except httpx.HTTPError as e:
+        return f"HTTP error: {str(e)}"
+
+    if not pods:
+        return "No kube-state-metrics data returned"
+
+    return json.dumps(list(pods.values()), indent=2)
+
+
+@mcp.tool()
+def ksm_get_deployments(namespace: Optional[str] = None) -> str:
+    """List deployments from kube-state-metrics with desired, available and updated replicas.
+
+    Alternative to kubectl_get_deployments when the Kubernetes API is not accessible.
+    """
+    ns = _namespace_matcher(namespace)
+    metrics = {
+        "replicas": "kube_deployment_spec_replicas",
+        "available": "kube_deployment_status_replicas_available",
+        "updated": "kube_deployment_status_replicas_updated",
+        "unavailable": "kube_deployment_status_replicas_unavailable",
+    }
+    deployments: dict[tuple[str, str], dict] = {}
+
+    try:
+        with httpx.Client(timeout=30.0) as client:
+            for field, metric_name in metrics.items():
+                for item in _in_scope(_query_vector(client, f"{metric_name}{ns}")):
+                    key = (item["metric"].get("namespace", ""), item["metric"].get("deployment", ""))
+                    entry = deployments.setdefault(key, {
+                        "name": anonymizer.token("workload", key[1]),
+                        "namespace": key[0],
+                    })
+                    entry[field] = int(float(item["value"][1]))
+
+    except httpx.ConnectError as e:
+        return f"Prometheus not available: {str(e)}"
+    except httpx.HTTPError as e:
+        return f"HTTP error: {str(e)}"
+
+    if not deployments:
+        return "No kube-state-metrics data returned"
+
+    return json.dumps(list(deployments.values()), indent=2)
+
+
+@mcp.tool()
+def get_deprecated_api_usage() -> str:
+    """List deprecated Kubernetes APIs still being requested, with the release that removes them.
+
+    Based on the API server metric apiserver_requested_deprecated_apis; use it for upgrade readiness.
+    """
+    try:
+        with httpx.Client(timeout=30.0) as client:
+            deprecated = _query_vector(client, "apiserver_requested_deprecated_apis")
+            requests = _query_vector(
+                client,
+                "sum by (group, version, resource) "
+                "(increase(apiserver_request_total{version!=\"\"}[7d]))",
+            )
+    except httpx.ConnectError as e:
+        return f"Prometheus not available: {str(e)}"
+    except httpx.HTTPError as e:
+        return f"HTTP error: {str(e)}"
+
+    if not deprecated:
+        return "No deprecated API usage reported by the API server"
+
+    request_counts = {
+        (m["metric"].get("group", ""), m["metric"].get("version"), m["metric"].get("resource")):
+            round(float(m["value"][1]))
+        for m in requests
+    }
+
+    result = []
+    seen = set()
+    for item in deprecated:
+        metric = item["metric"]
+        key = (metric.get("group", ""), metric.get("version"), metric.get("resource"))
+        if key in seen:
+            continue
+        seen.add(key)
+        result.append({
+            "api": f"{key[0] + '/' if key[0] else ''}{key[1]}",
+            "resource": key[2],
+            "removed_release": metric.get("removed_release"),
+            "requests_last_7d": request_counts.get(key),
+        })
+
+    return json.dumps(result, indent=2)
+
+
 if __name__ == "__main__":
     mcp.run(transport="stdio")
@@ -0,0 +1,66 @@
+"""Namespace include/exclude patterns shared by the app and the MCP servers.
+
+NAMESPACES_EXCLUDE and NAMESPACES_INCLUDE are comma-separated lists where each
+entry is a namespace name, a glob (``ci-*``) or, with the ``re:`` prefix, a
+regular expression (``re:pr-\\d+``) matched against the whole namespace name.
+Entries without the prefix are never read as regexes: ``ci-*`` must not
+match ``ci``.
+"""
+
+import fnmatch
+import re
+from functools import lru_cache
+from typing import Optional
+
+
+def parse_patterns(value: str) -> list[str]:
+    """Split a comma-separated pattern list, dropping empty entries."""
+    return [pattern.strip() for pattern in (value or "").split(",") if pattern.strip()]
+
+
+REGEX_PREFIX = "re:"
+
+
+@lru_cache(maxsize=256)
+def _compile(pattern: str) -> Optional[re.Pattern]:
+    """Compile a regex pattern (without its prefix), or None when it is not a valid one."""
+    try:
+        return re.compile(pattern)
+    except re.error:
+        return None
+
+
+def namespace_matches(namespace: str, patterns: list[str]) -> bool:
+    """Return True when a namespace matches any name, glob or re: regex pattern.
+
+    Args:
+        namespace: Namespace name
+        patterns: Output of parse_patterns()
+
+    Returns:
+        Whether the namespace matches
+    """
+    for pattern in patterns:
+        if pattern.startswith(REGEX_PREFIX):
+            regex = _compile(pattern[len(REGEX_PREFIX):])
+            if regex and regex.fullmatch(namespace):
+                return True
+        elif namespace == pattern or fnmatch.fnmatchcase(namespace, pattern):
+            return True
+    return False
+
+
+def namespace_in_scope(namespace: str, include: list[str], exclude: list[str]) -> bool:
+    """Return True when a namespace should be analyzed.
+
+    Args:
+        namespace: Namespace name
+        include: Include patterns; empty means every namespace
+        exclude: Exclude patterns, applied after the include list
+
+    Returns:
+        Whether the namespace is in scope
+    """
+    if include and not namespace_matches(namespace, include):
+        return False
+    return not namespace_matches(namespace, exclude)
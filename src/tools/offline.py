@@ -0,0 +1,195 @@
+"""Serve Kubernetes API reads from exported manifests (offline audits).
+
+Exports such as ``kubectl get pods,nodes,events,deployments,replicasets -A -o json``
+(or ``-o yaml``) are loaded from a directory, and list/get requests made through
+the official client are answered from them, so the same collectors and MCP
+tools can audit an air-gapped cluster. Anything not in the export (logs,
+metrics, health endpoints) answers 404 like a missing API.
+
+Shared by the application (src.tools.offline) and the MCP server (offline).
+"""
+
+import json
+import os
+import re
+import sys
+from typing import Optional
+from urllib.parse import parse_qsl, urlparse
+
+import yaml
+from kubernetes import client
+
+MANIFEST_EXTENSIONS = (".json", ".yaml", ".yml")
+
+# Kinds whose plural is not derived by the rules in _plural()
+IRREGULAR_PLURALS = {"Endpoints": "endpoints", "ComponentStatus": "componentstatuses"}
+
+# /api/v1/... and /apis/<group>/<version>/..., optionally namespaced, optionally one object
+PATH_PATTERN = re.compile(
+    r"^/(?:api/(?P<core_version>v1)|apis/(?P<group>[^/]+)/(?P<version>[^/]+))"
+    r"(?:/namespaces/(?P<namespace>[^/]+))?/(?P<resource>[^/]+)(?:/(?P<name>[^/]+))?(?:/(?P<subresource>[^/]+))?$"
+)
+
+
+def _plural(kind: str) -> str:
+    """Return the resource name of a kind (Pod -> pods, Ingress -> ingresses)."""
+    if kind in IRREGULAR_PLURALS:
+        return IRREGULAR_PLURALS[kind]
+    lowered = kind.lower()
+    if lowered.endswith("s"):
+        return lowered + "es"
+    if lowered.endswith("y") and lowered[-2:-1] not in "aeiou":
+        return lowered[:-1] + "ies"
+    return lowered + "s"
+
+
+def _group(api_version: str) -> str:
+    """Return the API group of an apiVersion ("" for the core group)."""
+    return api_version.split("/", 1)[0] if "/" in api_version else ""
+
+
+def load_manifests(path: str) -> list[dict]:
+    """Load every object of the JSON/YAML exports in a directory (or one file).
+
+    Lists (kind: List or *List) are flattened; objects without a kind are skipped.
+
+    Args:
+        path: Export directory or file
+
+    Returns:
+        List of objects
+    """
+    files = [path] if os.path.isfile(path) else [
+        os.path.join(root, name)
+        for root, _, names in os.walk(path)
+        for name in sorted(names)
+        if name.endswith(MANIFEST_EXTENSIONS)
+    ]
+
+    objects = []
+    for file_path in sorted(files):
+        with open(file_path, encoding="utf-8") as f:
+            documents = [json.load(f)] if file_path.endswith(".json") else list(yaml.safe_load_all(f))
+        for document in documents:
+            if not isinstance(document, dict):
+                continue
+            items = document.get("items") if document.get("kind", "").endswith("List") else [document]
+            for item in items or []:
+                if isinstance(item, dict) and item.get("kind"):
+                    # Items of kubectl lists carry their own apiVersion and kind; a typed list may not
+                    item.setdefault("apiVersion", document.get("apiVersion", "v1"))
+                    objects.append(item)
+    return objects
+
+
+def _field(obj: dict, dotted: str) -> str:
+    """Read a dotted field (metadata.name, involvedObject.kind) as a string."""
+    value = obj
+    for part in dotted.split("."):
+        value = value.get(part) if isinstance(value, dict) else None
+    return "" if value is None else str(value)
+
+
+def _matches_fields(obj: dict, selector: str) -> bool:
+    """Evaluate a field selector (a=b, a==b, a!=b, comma-joined)."""
+    for term in filter(None, (t.strip() for t in selector.split(","))):
+        negate = "!=" in term
+        key, value = re.split(r"!=|==|=", term, maxsplit=1)
+        if (_field(obj, key.strip()) == value.strip()) == negate:
+            return False
+    return True
+
+
+def _matches_labels(obj: dict, selector: str) -> bool:
+    """Evaluate a label selector (k=v, k==v, k!=v, k, !k, k in (a,b), k notin (a,b))."""
+    labels = (obj.get("metadata") or {}).get("labels") or {}
+    for term in re.findall(r"[^,(]+(?:\([^)]*\))?", selector):
+        term = term.strip()
+        if not term:
+            continue
+        set_match = re.match(r"^(\S+)\s+(in|notin)\s+\((.*)\)$", term)
+        if set_match:
+            key, operator, values = set_match.groups()
+            inside = labels.get(key) in {v.strip() for v in values.split(",")}
+            if inside != (operator == "in"):
+                return False
+        elif "!=" in term:
+            key, value = term.split("!=", 1)
+            if labels.get(key.strip()) == value.strip():
+                return False
+        elif "=" in term:
+            key, value = re.split(r"==|=", term, maxsplit=1)
+            if labels.get(key.strip()) != value.strip():
+                return False
+        elif term.startswith("!"):
+            if term[1:] in labels:
+                return False
+        elif term not in labels:
+            return False
+    return True
+
+
+class _OfflineResponse:
+    """Minimal stand-in for the urllib3 response the client deserializes."""
+
+    def __init__(self, payload: dict) -> None:
+        self.status = 200
+        self.reason = "OK"
+        self.data = json.dumps(payload).encode("utf-8")
+
+    def getheader(self, name: str, default: Optional[str] = None) -> Optional[str]:
+        return "application/json" if name.lower() == "content-type" else default
+
+    def getheaders(self) -> dict:
+        return {"Content-Type": "application/json"}
+
+
+class OfflineApiClient(client.ApiClient):
+    """ApiClient answering read requests from exported manifests.
+
+    Write requests are rejected: offline audits are read-only like the
+    live collectors.
+    """
+
+    def __init__(self, path: str) -> None:
+        """Load the export.
+
+        Args:
+            path: Export directory or file
+        """
+        configuration = client.Configuration()
+        configuration.host = "http://offline"
+        super().__init__(configuration)
+        self.objects = load_manifests(path)
+        print(f"Loaded {len(self.objects)} objects from {path} (offline mode)", file=sys.stderr)
+
+    def request(self, method, url, query_params=None, headers=None, *args, **kwargs):
+        parsed = urlparse(url)
+        match = PATH_PATTERN.match(parsed.path)
+        if method != "GET" or not match or match["subresource"]:
+            raise client.ApiException(status=404 if method == "GET" else 405, reason="Not in offline export")
+
+        group = "" if match["core_version"] else match["group"]
+        params = dict(query_params or []) | dict(parse_qsl(parsed.query))
+        items = [
+            obj for obj in self.objects
+            if _plural(obj["kind"]) == match["resource"]
+            # Events are served by both the core and the events.k8s.io APIs
+            and (_group(obj["apiVersion"]) == group or match["resource"] == "events")
+            and (not match["namespace"] or (obj.get("metadata") or {}).get("namespace") == match["namespace"])
+            and _matches_fields(obj, params.get("fieldSelector", ""))
+            and _matches_labels(obj, params.get("labelSelector", ""))
+        ]
+
+        if match["name"]:
+            obj = next((o for o in items if (o.get("metadata") or {}).get("name") == match["name"]), None)
+            if obj is None:
+                raise client.ApiException(status=404, reason="Not Found")
+            return _OfflineResponse(obj)
+
+        return _OfflineResponse({
+            "apiVersion": f"{group}/{match['version']}" if group else "v1",
+            "kind": "List",
+            "metadata": {},
+            "items": items,
+        })
@@ -0,0 +1,124 @@
+"""Optional OpenTelemetry tracing of the report pipeline.
+
+Tracing is enabled when OTEL_EXPORTER_OTLP_ENDPOINT is set and the
+`tracing` extra is installed; otherwise spans are no-ops. The durations of
+the main pipeline stages are recorded per job either way.
+"""
+
+import time
+from contextlib import contextmanager
+from contextvars import ContextVar
+from typing import Iterator, Optional
+
+import structlog
+
+from src import __version__
+from src.config import settings
+
+logger = structlog.get_logger()
+
+_tracer = None
+
+# Span name -> pipeline stage whose duration is recorded with the job
+STAGE_SPANS = {
+    "collector.cluster_stats": "collection",
+    "analyzer.prompt": "prompt_build",
+    "analyzer.claude": "llm",
+    "analyzer.rollup_narrative": "llm",
+    "pdfgen.render": "pdf_render",
+    "reporter.slack_upload": "delivery",
+}
+
+_stage_timings: ContextVar[Optional[dict]] = ContextVar("stage_timings", default=None)
+
+
+def init_tracing() -> None:
+    """Configure the OTLP exporter once per process."""
+    global _tracer
+    if _tracer or not settings.otel_exporter_otlp_endpoint:
+        return
+
+    try:
+        from opentelemetry import trace
+        from opentelemetry.exporter.otlp.proto.http.trace_exporter import OTLPSpanExporter
+        from opentelemetry.sdk.resources import Resource
+        from opentelemetry.sdk.trace import TracerProvider
+        from opentelemetry.sdk.trace.export import BatchSpanProcessor
+    except ImportError:
+        logger.warning(
+            "tracing_unavailable",
+            reason="opentelemetry packages not installed (pip install .[tracing])",
+        )
+        return
+
+    provider = TracerProvider(
+        resource=Resource.create({
+            "service.name": settings.otel_service_name,
+            "service.version": __version__,
+            "k8s.cluster.name": settings.cluster_name,
+        })
+    )
+    provider.add_span_processor(
+        BatchSpanProcessor(
+            OTLPSpanExporter(endpoint=f"{settings.otel_exporter_otlp_endpoint.rstrip('/')}/v1/traces")
+        )
+    )
+    trace.set_tracer_provider(provider)
+    _tracer = trace.get_tracer("k8s-watchdog-ai")
+
+    logger.info("tracing_initialized", endpoint=settings.otel_exporter_otlp_endpoint)
+
+
+def shutdown_tracing() -> None:
+    """Flush pending spans before the process exits."""
+    if not _tracer:
+        return
+
+    from opentelemetry import trace
+
+    trace.get_tracer_provider().shutdown()
+
+
+@contextmanager
+def span(name: str, **attributes) -> Iterator[Optional[object]]:
+    """Trace a pipeline stage.
+
+    Args:
+        name: Span name (e.g., 'collector.cluster_stats', 'analyzer.claude')
+        **attributes: Span attributes; None values are skipped
+
+    Yields:
+        The active span, or None when tracing is disabled
+    """
+    start = time.monotonic()
+    try:
+        if not _tracer:
+            yield None
+            return
+
+        with _tracer.start_as_current_span(name) as current:
+            for key, value in attributes.items():
+                if value is not None:
+                    current.set_attribute(key, value)
+            yield current
+    finally:
+        timings = _stage_timings.get()
+        if timings is not None and name in STAGE_SPANS:
+            stage = STAGE_SPANS[name]
+            # Retries and DM uploads add up
+            timings[stage] = round(timings.get(stage, 0.0) + time.monotonic() - start, 3)
+
+
+@contextmanager
+def record_stage_timings() -> Iterator[dict]:
+    """Collect the duration of the pipeline stages traced inside the block.
+
+    Yields:
+        Dict of stage -> seconds, filled as the stages complete
+    """
+    timings: dict[str, float] = {}
+    token = _stage_timings.set(timings)
+    try:
+        yield timings
+    finally:
+        _stage_timings.reset(token)
@@ -0,0 +1,6 @@
+"""Background watchers that record cluster activity between reports."""
+
+from .pods import PodWatcher
+from .workloads import WorkloadSampler
+
+__all__ = ["PodWatcher", "WorkloadSampler"]
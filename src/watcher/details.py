@@ -0,0 +1,140 @@
+import time
+from collections import deque
+from typing import Optional
+
+import structlog
+from kubernetes import client
+from kubernetes.client import ApiException
+
+logger = structlog.get_logger()
+
+# Pod phases worth a describe when a pod enters them
+UNHEALTHY_PHASES = {"Failed", "Pending", "Unknown"}
+
+MAX_EVENTS = 10
+
+
+class DetailRateLimiter:
+    """Bound the extra API calls of describe collection.
+
+    A pod is described at most once per cooldown, and no more than
+    max_per_hour pods are described overall, so a crash-looping fleet
+    cannot flood the API server.
+    """
+
+    def __init__(self, max_per_hour: int, cooldown_seconds: float) -> None:
+        """Initialize the limiter.
+
+        Args:
+            max_per_hour: Describes allowed per rolling hour
+            cooldown_seconds: Minimum delay between two describes of the same pod
+        """
+        self.max_per_hour = max_per_hour
+        self.cooldown_seconds = cooldown_seconds
+        self._recent: deque[float] = deque()
+        self._last_by_pod: dict[str, float] = {}
+
+    def allow(self, pod_uid: str) -> bool:
+        """Return True (and count the call) when the pod may be described now."""
+        now = time.monotonic()
+        while self._recent and now - self._recent[0] > 3600:
+            self._recent.popleft()
+        last = self._last_by_pod.get(pod_uid)
+        if len(self._recent) >= self.max_per_hour or (last is not None and now - last < self.cooldown_seconds):
+            return False
+        self._recent.append(now)
+        self._last_by_pod[pod_uid] = now
+        if len(self._last_by_pod) > 10000:
+            self._last_by_pod = {
+                uid: seen for uid, seen in self._last_by_pod.items() if now - seen < self.cooldown_seconds
+            }
+        return True
+
+
+def _conditions(conditions: Optional[list]) -> list[dict]:
+    """Keep the fields of pod or node conditions that explain a failure."""
+    return [
+        {"type": c.type, "status": c.status, "reason": c.reason, "message": c.message}
+        for c in conditions or []
+    ]
+
+
+def _volume_type(volume: client.V1Volume) -> str:
+    """Return the source type of a volume (persistent_volume_claim, config_map...)."""
+    sources = volume.to_dict()
+    return next((key for key, value in sources.items() if key != "name" and value is not None), "unknown")
+
+
+def _container_states(pod: client.V1Pod) -> list[dict]:
+    """Summarize the current and last state of each container."""
+    states = []
+    for cs in (pod.status.init_container_statuses or []) + (pod.status.container_statuses or []):
+        waiting = cs.state.waiting if cs.state else None
+        terminated = cs.last_state.terminated if cs.last_state else None
+        states.append({
+            "container": cs.name,
+            "ready": cs.ready,
+            "restarts": cs.restart_count,
+            "waiting_reason": waiting.reason if waiting else None,
+            "waiting_message": waiting.message if waiting else None,
+            "last_terminated_reason": terminated.reason if terminated else None,
+            "last_exit_code": terminated.exit_code if terminated else None,
+        })
+    return states
+
+
+def describe_pod(core_v1: client.CoreV1Api, pod: client.V1Pod) -> dict:
+    """Collect `kubectl describe`-like detail for an unhealthy pod.
+
+    Args:
+        core_v1: CoreV1Api of the watcher
+        pod: Pod from the watch event
+
+    Returns:
+        Dict with conditions, container states, tolerations, volumes, the
+        recent events of the pod and the non-ready conditions of its node
+    """
+    details = {
+        "node": pod.spec.node_name,
+        "conditions": _conditions(pod.status.conditions),
+        "containers": _container_states(pod),
+        "tolerations": [
+            {"key": t.key, "operator": t.operator, "value": t.value, "effect": t.effect}
+            for t in pod.spec.tolerations or []
+        ],
+        "volumes": [{"name": v.name, "type": _volume_type(v)} for v in pod.spec.volumes or []],
+        "events": [],
+        "node_conditions": [],
+    }
+
+    try:
+        events = core_v1.list_namespaced_event(
+            pod.metadata.namespace,
+            field_selector=f"involvedObject.kind=Pod,involvedObject.name={pod.metadata.name}",
+        ).items
+        events.sort(key=lambda e: (e.last_timestamp or e.event_time or e.metadata.creation_timestamp), reverse=True)
+        details["events"] = [
+            {
+                "type": e.type,
+                "reason": e.reason,
+                "message": e.message,
+                "count": e.count,
+                "last_seen": (e.last_timestamp or e.event_time or e.metadata.creation_timestamp).isoformat(),
+            }
+            for e in events[:MAX_EVENTS]
+        ]
+    except ApiException as e:
+        logger.warning("pod_details_events_unavailable", status=e.status, source="watcher")
+
+    if pod.spec.node_name:
+        try:
+            node = core_v1.read_node(pod.spec.node_name)
+            details["node_conditions"] = [
+                c for c in _conditions(node.status.conditions)
+                # Ready=True and pressure conditions=False are the healthy states
+                if (c["type"] == "Ready") != (c["status"] == "True")
+            ]
+        except ApiException as e:
+            logger.warning("pod_details_node_unavailable", status=e.status, source="watcher")
+
+    return details
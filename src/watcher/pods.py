@@ -0,0 +1,209 @@
+import asyncio
+import json
+import threading
+from datetime import datetime
+from typing import Optional
+
+import structlog
+from kubernetes import client, watch
+from kubernetes.client import ApiException
+
+from src.config import settings
+from src.kube import load_kube_config
+from src.stats.exclusions import is_excluded
+from src.storage import ReportStorage
+from src.watcher.details import UNHEALTHY_PHASES, DetailRateLimiter, describe_pod
+
+logger = structlog.get_logger()
+
+
+class PodWatcher:
+    """Watch pod updates and record short-lived failures.
+
+    Pods that crash and get replaced between two reports leave no trace in
+    the live cluster state the agent inspects. This watcher keeps a stream
+    open against the API server and stores phase transitions and container
+    terminations in the pod_transitions table, which the Kubernetes MCP
+    server exposes to the agent.
+    """
+
+    def __init__(self, storage: ReportStorage) -> None:
+        """Initialize pod watcher.
+
+        Args:
+            storage: ReportStorage instance used to persist transitions
+        """
+        self.storage = storage
+        self._loop: Optional[asyncio.AbstractEventLoop] = None
+        self._thread: Optional[threading.Thread] = None
+        self._stop = threading.Event()
+        self._watch: Optional[watch.Watch] = None
+        # pod uid -> (phase, {container name: restart count})
+        self._pods: dict[str, tuple[str, dict[str, int]]] = {}
+        self._details_limiter = (
+            DetailRateLimiter(settings.pod_details_max_per_hour, settings.pod_details_cooldown_minutes * 60)
+            if settings.pod_details_enabled else None
+        )
+
+        load_kube_config()
+        self.core_v1 = client.CoreV1Api()
+
+    def start(self) -> None:
+        """Start watching pods in a background thread."""
+        self._loop = asyncio.get_running_loop()
+        self._thread = threading.Thread(target=self._run, name="pod-watcher", daemon=True)
+        self._thread.start()
+        logger.info("pod_watcher_started", source="watcher")
+
+    def stop(self) -> None:
+        """Stop the watch stream."""
+        self._stop.set()
+        if self._watch:
+            self._watch.stop()
+        logger.info("pod_watcher_stopped", source="watcher")
+
+    def _run(self) -> None:
+        """Watch loop, restarted whenever the stream ends or expires."""
+        resource_version = None
+
+        while not self._stop.is_set():
+            self._watch = watch.Watch()
+            try:
+                for event in self._watch.stream(
+                    self.core_v1.list_pod_for_all_namespaces,
+                    resource_version=resource_version,
+                    timeout_seconds=300,
+                ):
+                    pod = event["object"]
+                    resource_version = pod.metadata.resource_version
+                    self._handle_event(event["type"], pod)
+
+            except ApiException as e:
+                if e.status == 410:
+                    # Resource version too old, start over from a fresh list
+                    resource_version = None
+                    continue
+                logger.error("pod_watcher_api_error", error=e.reason, source="watcher")
+                self._stop.wait(10)
+
+            except Exception as e:
+                logger.error(
+                    "pod_watcher_error",
+                    error=str(e),
+                    error_type=type(e).__name__,
+                    source="watcher",
+                )
+                self._stop.wait(10)
+
+    def _handle_event(self, event_type: str, pod: client.V1Pod) -> None:
+        """Compare a pod update with the last known state and record changes.
+
+        Args:
+            event_type: Watch event type (ADDED, MODIFIED, DELETED)
+            pod: Pod object from the event
+        """
+        if not settings.namespace_in_scope(pod.metadata.namespace) or is_excluded(
+            None, pod.metadata.namespace, annotations=pod.metadata.annotations
+        ):
+            return
+
+        uid = pod.metadata.uid
+
+        if event_type == "DELETED":
+            self._pods.pop(uid, None)
+            return
+
+        phase = pod.status.phase or "Unknown"
+        restarts = {
+            cs.name: cs.restart_count for cs in pod.status.container_statuses or []
+        }
+
+        previous = self._pods.get(uid)
+        self._pods[uid] = (phase, restarts)
+
+        # First sighting (initial list or new pod): nothing to compare against
+        if previous is None:
+            return
+
+        previous_phase, previous_restarts = previous
+
+        if phase != previous_phase:
+            self._record(
+                pod,
+                transition_type="phase",
+                from_phase=previous_phase,
+                to_phase=phase,
+                reason=pod.status.reason,
+            )
+
+        for cs in pod.status.container_statuses or []:
+            terminated = None
+            if cs.restart_count > previous_restarts.get(cs.name, 0) and cs.last_state:
+                terminated = cs.last_state.terminated
+            elif cs.state and cs.state.terminated and phase == "Failed":
+                terminated = cs.state.terminated
+
+            if terminated and (terminated.exit_code or terminated.reason != "Completed"):
+                self._record(
+                    pod,
+                    transition_type="container_terminated",
+                    container=cs.name,
+                    from_phase=previous_phase,
+                    to_phase=phase,
+                    reason=terminated.reason,
+                    exit_code=terminated.exit_code,
+                    signal=terminated.signal,
+                )
+
+    def _record(self, pod: client.V1Pod, **transition) -> None:
+        """Persist a transition through the async storage on the main loop.
+
+        Args:
+            pod: Pod the transition belongs to
+            **transition: Transition fields passed to storage
+        """
+        logger.info(
+            "pod_transition_observed",
+            namespace=pod.metadata.namespace,
+            pod=pod.metadata.name,
+            source="watcher",
+            **transition,
+        )
+
+        future = asyncio.run_coroutine_threadsafe(
+            self.storage.insert_pod_transition(
+                namespace=pod.metadata.namespace,
+                pod=pod.metadata.name,
+                observed_at=datetime.now().isoformat(),
+                details=self._describe(pod, transition),
+                **transition,
+            ),
+            self._loop,
+        )
+        try:
+            future.result(timeout=30)
+        except Exception as e:
+            logger.error("pod_transition_save_failed", error=str(e), source="watcher")
+
+    def _describe(self, pod: client.V1Pod, transition: dict) -> Optional[str]:
+        """Collect describe detail for an unhealthy transition, within the rate limit.
+
+        Args:
+            pod: Pod the transition belongs to
+            transition: Transition fields
+
+        Returns:
+            Details as JSON, or None when disabled, healthy or rate-limited
+        """
+        unhealthy = (
+            transition["transition_type"] == "container_terminated"
+            or transition.get("to_phase") in UNHEALTHY_PHASES
+        )
+        if not self._details_limiter or not unhealthy or not self._details_limiter.allow(pod.metadata.uid):
+            return None
+
+        try:
+            return json.dumps(describe_pod(self.core_v1, pod), default=str)
+        except Exception as e:
+            logger.warning("pod_details_failed", pod=pod.metadata.name, error=str(e), source="watcher")
+            return None
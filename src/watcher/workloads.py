@@ -0,0 +1,128 @@
+import asyncio
+from datetime import datetime
+from typing import Optional
+
+import structlog
+from kubernetes import client
+
+from src.config import settings
+from src.kube import load_kube_config
+from src.storage import ReportStorage
+
+logger = structlog.get_logger()
+
+# Workloads annotated with this key set to "true" are watched
+WATCH_ANNOTATION = "watchdog.helmcode.com/watch"
+
+
+def _is_watched(workload, watched: set[str]) -> bool:
+    """Return True when a workload is listed in WATCHED_WORKLOADS or annotated."""
+    metadata = workload.metadata
+    annotations = metadata.annotations or {}
+    return (
+        f"{metadata.namespace}/{metadata.name}" in watched
+        or annotations.get(WATCH_ANNOTATION, "").lower() == "true"
+    )
+
+
+class WorkloadSampler:
+    """Sample watched workloads every few minutes.
+
+    Weekly reports only see the state at generation time; watched workloads
+    get a ready/desired and restart sample every WATCHED_SAMPLE_INTERVAL
+    seconds, stored in workload_samples for trend charts and guaranteed
+    coverage in the report.
+    """
+
+    def __init__(self, storage: ReportStorage) -> None:
+        """Initialize the sampler.
+
+        Args:
+            storage: ReportStorage instance used to persist samples
+        """
+        self.storage = storage
+        self._task: Optional[asyncio.Task] = None
+
+        load_kube_config()
+        self.core_v1 = client.CoreV1Api()
+        self.apps_v1 = client.AppsV1Api()
+
+    def start(self) -> None:
+        """Start sampling in a background task."""
+        self._task = asyncio.create_task(self._run())
+        logger.info(
+            "workload_sampler_started",
+            interval=settings.watched_sample_interval,
+            configured=len(settings.watched_workload_list),
+            source="watcher",
+        )
+
+    def stop(self) -> None:
+        """Stop sampling."""
+        if self._task:
+            self._task.cancel()
+        logger.info("workload_sampler_stopped", source="watcher")
+
+    async def _run(self) -> None:
+        """Sampling loop; a failed round is logged and retried at the next interval."""
+        while True:
+            try:
+                samples = await asyncio.to_thread(self.sample)
+                if samples:
+                    await self.storage.insert_workload_samples(samples, datetime.now().isoformat())
+            except asyncio.CancelledError:
+                raise
+            except Exception as e:
+                logger.error(
+                    "workload_sampling_failed",
+                    error=str(e),
+                    error_type=type(e).__name__,
+                    source="watcher",
+                )
+            await asyncio.sleep(settings.watched_sample_interval)
+
+    def sample(self) -> list[dict]:
+        """Read the current state of every watched workload.
+
+        Returns:
+            One dict per watched workload (namespace, kind, name, desired, ready, restarts)
+        """
+        watched = set(settings.watched_workload_list)
+        workloads = []
+        for deployment in self.apps_v1.list_deployment_for_all_namespaces().items:
+            if _is_watched(deployment, watched):
+                workloads.append(("Deployment", deployment, deployment.spec.replicas or 0,
+                                  deployment.status.ready_replicas or 0))
+        for stateful_set in self.apps_v1.list_stateful_set_for_all_namespaces().items:
+            if _is_watched(stateful_set, watched):
+                workloads.append(("StatefulSet", stateful_set, stateful_set.spec.replicas or 0,
+                                  stateful_set.status.ready_replicas or 0))
+        for daemon_set in self.apps_v1.list_daemon_set_for_all_namespaces().items:
+            if _is_watched(daemon_set, watched):
+                workloads.append(("DaemonSet", daemon_set, daemon_set.status.desired_number_scheduled or 0,
+                                  daemon_set.status.number_ready or 0))
+
+        samples = []
+        for kind, workload, desired, ready in workloads:
+            match_labels = (workload.spec.selector.match_labels or {}) if workload.spec.selector else {}
+            restarts = 0
+            if match_labels:
+                pods = self.core_v1.list_namespaced_pod(
+                    workload.metadata.namespace,
+                    label_selector=",".join(f"{key}={value}" for key, value in match_labels.items()),
+                ).items
+                restarts = sum(
+                    cs.restart_count for pod in pods for cs in pod.status.container_statuses or []
+                )
+            samples.append({
+                "namespace": workload.metadata.namespace,
+                "kind": kind,
+                "name": workload.metadata.name,
+                "desired": desired,
+                "ready": ready,
+                "restarts": restarts,
+            })
+
+        logger.info("watched_workloads_sampled", workloads=len(samples), source="watcher")
+
+        return samples
@@ -0,0 +1,63 @@
+"""Shared fixtures for hermetic analyzer tests.
+
+The model is never called: K8sWatchdogAgent runs against a FixtureProvider
+built from fake_output(), so prompt building, response parsing and
+validation run exactly as in production.
+"""
+
+import os
+
+# Required settings, set before src.config instantiates Settings
+os.environ.setdefault("CLAUDE_CODE_OAUTH_TOKEN", "test-token")
+os.environ.setdefault("SLACK_WEBHOOK_URL", "https://hooks.slack.invalid/test")
+os.environ.setdefault("CLUSTER_NAME", "test-cluster")
+
+import pytest  # noqa: E402
+
+from src.config import settings  # noqa: E402
+
+VALID_REPORT_DATA = {
+    "health_status": "yellow",
+    "high_restart_pods": [{"namespace": "payments", "pod": "api-7d9f", "restarts": 12}],
+    "rightsizing": [],
+    "events_summary": [],
+    "findings": [],
+    "recommendations": [],
+    "recommendation_followup": [],
+}
+
+
+def make_report_html(body: str = "<p>All good.</p>") -> str:
+    """Return a complete report document the validator accepts."""
+    return f"""<!DOCTYPE html>
+<html>
+<head>
+<meta charset="UTF-8">
+<title>Report</title>
+</head>
+<body>
+<div class="header"><h1>Kubernetes Health Report</h1></div>
+<div class="section">{body}</div>
+</body>
+</html>"""
+
+
+@pytest.fixture(autouse=True)
+def hermetic_settings(monkeypatch, tmp_path):
+    """Pin the settings the analyzer reads, whatever the developer's .env holds."""
+    monkeypatch.setattr(settings, "cluster_name", "test-cluster")
+    monkeypatch.setattr(settings, "privacy_mode", False)
+    monkeypatch.setattr(settings, "report_max_attempts", 3)
+    monkeypatch.setattr(settings, "llm_record_path", None)
+    monkeypatch.setattr(settings, "llm_fixture_path", None)
+    monkeypatch.setattr(settings, "cluster_context_path", None)
+    monkeypatch.setattr(settings, "workload_data_source", "api")
+    monkeypatch.setattr(settings, "offline_manifests_path", None)
+    monkeypatch.setattr(settings, "namespace_thresholds", "")
+    monkeypatch.setattr(settings, "data_dir", str(tmp_path))
+
+
+@pytest.fixture
+def report_data() -> dict:
+    """A structured data block that passes validation."""
+    return {key: list(value) if isinstance(value, list) else value for key, value in VALID_REPORT_DATA.items()}
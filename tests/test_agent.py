@@ -0,0 +1,129 @@
+"""Validation and re-ask loop of generate_weekly_report(), against recorded outputs."""
+
+import pytest
+
+from src.config import settings
+from src.orchestrator import FixtureProvider, K8sWatchdogAgent, fake_output
+from src.reporter.deterministic import build_deterministic_report
+from tests.conftest import make_report_html
+
+
+def _agent(*outputs: dict) -> tuple[K8sWatchdogAgent, FixtureProvider]:
+    provider = FixtureProvider(list(outputs))
+    return K8sWatchdogAgent(provider=provider), provider
+
+
+async def test_valid_report_needs_one_call(report_data):
+    agent, provider = _agent(fake_output(make_report_html(), report_data, num_turns=4, cost_usd=0.25))
+
+    report_html, metadata = await agent.generate_weekly_report()
+
+    assert len(provider.calls) == 1
+    assert "Kubernetes Health Report" in report_html
+    assert "watchdog-data" not in report_html
+    assert metadata["report_data"] == report_data
+    assert metadata["attempts"] == 1
+    assert metadata["num_turns"] == 4
+    assert metadata["total_cost_usd"] == 0.25
+    assert metadata["output_format"] == "html"
+    assert sorted(metadata["mcp_servers_used"]) == ["kubernetes", "prometheus"]
+    assert "deterministic" not in metadata
+
+
+async def test_invalid_report_is_re_asked_with_the_problems(report_data):
+    truncated = make_report_html().replace("</body>\n</html>", "")
+    agent, provider = _agent(
+        fake_output(truncated, report_data, cost_usd=0.10),
+        fake_output(make_report_html(), report_data, cost_usd=0.05),
+    )
+
+    _, metadata = await agent.generate_weekly_report()
+
+    assert len(provider.calls) == 2
+    correction = provider.calls[1]["prompt"]
+    assert "rejected by the automated validator" in correction
+    assert "does not end with </html>" in correction
+    assert metadata["attempts"] == 2
+    assert metadata["total_cost_usd"] == pytest.approx(0.15)
+    assert [call["cost_usd"] for call in agent.calls] == [0.10, 0.05]
+
+
+async def test_missing_data_block_is_re_asked_then_dropped(monkeypatch):
+    monkeypatch.setattr(settings, "report_max_attempts", 2)
+    agent, provider = _agent(fake_output(make_report_html()), fake_output(make_report_html()))
+
+    report_html, metadata = await agent.generate_weekly_report()
+
+    # The visible report is valid: it is kept without structured data
+    assert len(provider.calls) == 2
+    assert "block is missing or is not valid JSON" in provider.calls[1]["prompt"]
+    assert metadata["report_data"] == {}
+    assert "Kubernetes Health Report" in report_html
+    assert "deterministic" not in metadata
+
+
+async def test_exhausted_attempts_fall_back_to_statistics_report(monkeypatch):
+    monkeypatch.setattr(settings, "report_max_attempts", 2)
+    broken = "<html><body><div>Half a report about the payments namespace"
+    agent, provider = _agent(fake_output(broken, cost_usd=0.2), fake_output(broken, cost_usd=0.3))
+
+    report_html, metadata = await agent.generate_weekly_report()
+
+    assert len(provider.calls) == 2
+    assert metadata["deterministic"] is True
+    assert metadata["fallback_reason"] == "validation"
+    assert "failed validation after 2 attempts" in metadata["notice"]
+    assert metadata["total_cost_usd"] == pytest.approx(0.5)
+    assert "AI Analysis (Unvalidated)" in report_html
+    assert "Half a report about the payments namespace" in report_html
+    assert report_html.rstrip().endswith("</html>")
+
+
+def test_budget_fallback_report_carries_its_reason():
+    report_html, metadata = build_deterministic_report(None, "The monthly LLM budget is exhausted.", "budget")
+
+    assert metadata["deterministic"] is True
+    assert metadata["fallback_reason"] == "budget"
+    assert metadata["notice"] == "The monthly LLM budget is exhausted."
+    assert "budget_notice" not in metadata
+    assert "The monthly LLM budget is exhausted." in report_html
+
+
+async def test_markdown_output_is_rendered():
+    markdown_report = (
+        "# Weekly report\n\n## Issues\n\n- api restarts 12 times\n\n"
+        '<script type="application/json" id="watchdog-data">{"health_status": "green"}</script>'
+    )
+    agent, _ = _agent(fake_output(markdown_report))
+
+    report_html, metadata = await agent.generate_weekly_report()
+
+    assert metadata["output_format"] == "markdown"
+    assert metadata["attempts"] == 1
+    assert metadata["report_data"] == {"health_status": "green"}
+    assert report_html.startswith("<!DOCTYPE html>")
+    assert "<title>Weekly report</title>" in report_html
+    assert "<li>api restarts 12 times</li>" in report_html
+
+
+async def test_replay_attaches_no_mcp_servers(report_data):
+    agent, _ = _agent(fake_output(make_report_html(), report_data))
+
+    _, metadata = await agent.generate_weekly_report(replay=True)
+
+    assert metadata["mcp_servers_used"] == []
+
+
+async def test_custom_system_prompt_replaces_the_built_in_one(report_data):
+    agent, provider = _agent(fake_output(make_report_html(), report_data))
+
+    await agent.generate_weekly_report(system_prompt="You are a terse SRE.")
+
+    assert provider.calls[0]["system_prompt"] == "You are a terse SRE."
+
+
+async def test_fixture_exhaustion_is_reported():
+    agent, _ = _agent()
+
+    with pytest.raises(RuntimeError, match="LLM fixture exhausted"):
+        await agent.generate_weekly_report()
@@ -0,0 +1,43 @@
+from src.config import settings
+from src.stats import ksm
+from src.stats.snapshot import validate_snapshot
+
+def series(**labels) -> dict:
+    return {"metric": labels, "value": [0, "1"]}
+
+
+SERIES = {
+    "kube_pod_status_phase == 1": [
+        series(namespace="payments", pod="api-1", phase="Running"),
+        series(namespace="payments", pod="api-2", phase="Pending"),
+        series(namespace="kube-system", pod="dns-1", phase="Running"),
+    ],
+    "sum by (namespace, pod) (kube_pod_container_status_restarts_total)": [
+        {"metric": {"namespace": "payments", "pod": "api-1"}, "value": [0, "7"]},
+        {"metric": {"namespace": "kube-system", "pod": "dns-1"}, "value": [0, "3"]},
+    ],
+    "kube_node_info": [
+        series(node="node-a"),
+        series(node="node-b"),
+    ],
+    'kube_node_status_condition{condition="Ready", status="true"} == 1': [series(node="node-a")],
+}
+
+
+def test_ksm_stats_have_the_snapshot_shape(monkeypatch):
+    monkeypatch.setattr(ksm, "_query_vector", lambda query: SERIES[query])
+    monkeypatch.setattr(settings, "namespaces_exclude", "kube-system")
+    monkeypatch.setattr(settings, "namespaces_include", "")
+
+    stats = ksm.collect_ksm_stats(restarts_this_week=4)
+
+    assert validate_snapshot(stats) == []
+    assert stats["total_pods"] == 2
+    assert stats["running_pods"] == 1
+    assert stats["pods_by_phase"] == {"Running": 1, "Pending": 1}
+    assert stats["total_restarts"] == 7
+    assert stats["restarts_this_week"] == 4
+    assert (stats["ready_nodes"], stats["total_nodes"]) == (1, 2)
+    assert stats["appendix"]["restarting_pods"] == [
+        {"namespace": "payments", "pod": "api-1", "restarts": 7, "reason": ""}
+    ]
@@ -0,0 +1,132 @@
+"""Privacy mode of the Kubernetes MCP tools, against an offline export and a local database."""
+
+import importlib
+import json
+import sqlite3
+import sys
+from pathlib import Path
+from types import SimpleNamespace
+
+import pytest
+
+from src.tools.anonymizer import TOKEN_PATTERN, load_mapping
+
+TOOLS_DIR = str(Path(__file__).resolve().parent.parent / "src" / "tools")
+
+
+@pytest.fixture(scope="module")
+def mcp_kubernetes(tmp_path_factory):
+    """Import the MCP server as its own process does, reading an empty offline export."""
+    export = tmp_path_factory.mktemp("export")
+    with pytest.MonkeyPatch.context() as mp:
+        mp.syspath_prepend(TOOLS_DIR)
+        mp.setenv("OFFLINE_MANIFESTS_PATH", str(export))
+        module = importlib.import_module("mcp_kubernetes")
+        yield module
+        sys.modules.pop("mcp_kubernetes", None)
+
+
+@pytest.fixture
+def privacy(mcp_kubernetes, monkeypatch, tmp_path):
+    """Turn privacy mode on; returns the path of the token mapping file."""
+    map_path = tmp_path / "mapping.jsonl"
+    monkeypatch.setattr(mcp_kubernetes, "anonymizer", mcp_kubernetes.Anonymizer(str(map_path), "test-salt"))
+    return map_path
+
+
+@pytest.fixture
+def dependency_db(mcp_kubernetes, monkeypatch, tmp_path):
+    db_path = tmp_path / "watchdog.db"
+    with sqlite3.connect(db_path) as db:
+        db.execute("""
+            CREATE TABLE workload_dependencies (
+                id INTEGER PRIMARY KEY AUTOINCREMENT, cluster_name TEXT NOT NULL,
+                namespace TEXT NOT NULL, workload TEXT NOT NULL, kind TEXT NOT NULL,
+                target_namespace TEXT NOT NULL, target_service TEXT NOT NULL,
+                target_workload TEXT, via TEXT, observed_at TIMESTAMP NOT NULL
+            )
+        """)
+        db.executemany(
+            "INSERT INTO workload_dependencies (cluster_name, namespace, workload, kind, target_namespace,"
+            " target_service, target_workload, via, observed_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, '2026-10-01')",
+            [
+                ("test-cluster", "shop", "checkout", "Deployment", "shop", "redis-primary", "redis", "env:REDIS_HOST"),
+                ("test-cluster", "shop", "cart", "Deployment", "shop", "pricing", None, "env:PRICING_URL"),
+            ],
+        )
+    monkeypatch.setattr(mcp_kubernetes, "WATCHDOG_DB_PATH", str(db_path))
+    monkeypatch.setattr(mcp_kubernetes, "CLUSTER_NAME", "test-cluster")
+
+
+@pytest.mark.usefixtures("dependency_db")
+def test_dependencies_are_pseudonymized(mcp_kubernetes, privacy):
+    output = mcp_kubernetes.get_workload_dependencies()
+
+    for name in ("checkout", "cart", "redis-primary", "redis", "pricing"):
+        assert f'"{name}"' not in output
+    edges = json.loads(output)
+    assert all(TOKEN_PATTERN.fullmatch(edge["target_service"]) for edge in edges)
+    mapping = load_mapping(str(privacy))
+    assert {mapping[edge["target_service"]] for edge in edges} == {"redis-primary", "pricing"}
+
+
+@pytest.mark.usefixtures("dependency_db")
+def test_dependency_filter_accepts_a_service_token(mcp_kubernetes, privacy):
+    service_token = mcp_kubernetes.anonymizer.token("workload", "pricing")
+
+    edges = json.loads(mcp_kubernetes.get_workload_dependencies(workload=service_token))
+
+    assert len(edges) == 1
+    assert edges[0]["target_service"] == service_token
+    assert edges[0]["workload"] == mcp_kubernetes.anonymizer.token("workload", "cart")
+
+
+@pytest.mark.usefixtures("dependency_db")
+def test_dependencies_keep_real_names_without_privacy_mode(mcp_kubernetes):
+    edges = json.loads(mcp_kubernetes.get_workload_dependencies(workload="redis"))
+
+    assert [(e["workload"], e["target_service"]) for e in edges] == [("checkout", "redis-primary")]
+
+
+@pytest.mark.parametrize(
+    ("image", "registry", "path", "suffix"),
+    [
+        ("registry.corp.example/payments/api:1.4.2", "registry.corp.example", "payments/api", ":1.4.2"),
+        ("localhost:5000/tools/debug", "localhost:5000", "tools/debug", ""),
+        ("nginx@sha256:abc123", "docker.io", "nginx", "@sha256:abc123"),
+    ],
+)
+def test_image_references_keep_only_the_tag_readable(mcp_kubernetes, privacy, image, registry, path, suffix):
+    anonymized = mcp_kubernetes._anonymize_image(image)
+
+    registry_token, _, rest = anonymized.partition("/")
+    assert rest.endswith(suffix)
+    image_token = rest[: len(rest) - len(suffix)]
+    mapping = load_mapping(str(privacy))
+    assert (mapping[registry_token], mapping[image_token]) == (registry, path)
+
+
+def test_image_pull_failures_are_pseudonymized(mcp_kubernetes, privacy, monkeypatch):
+    waiting = SimpleNamespace(reason="ImagePullBackOff", message="pull access denied for registry.corp.example")
+    pod = SimpleNamespace(
+        metadata=SimpleNamespace(name="api-7d9f", namespace="payments"),
+        status=SimpleNamespace(
+            init_container_statuses=None,
+            container_statuses=[
+                SimpleNamespace(
+                    image="registry.corp.example/payments/api:1.4.2", state=SimpleNamespace(waiting=waiting)
+                )
+            ],
+        ),
+    )
+    core_v1 = SimpleNamespace(list_namespaced_pod=lambda namespace: SimpleNamespace(items=[pod]))
+    monkeypatch.setattr(mcp_kubernetes, "core_v1", core_v1)
+    monkeypatch.setattr(mcp_kubernetes, "_list_events", lambda namespace=None: [])
+
+    output = mcp_kubernetes.get_image_pull_failures(namespace="payments")
+
+    assert "registry.corp.example" not in output
+    assert "payments/api" not in output
+    [registry] = json.loads(output)
+    assert registry["images"][0]["image"].endswith(":1.4.2")
+    assert load_mapping(str(privacy))[registry["registry"]] == "registry.corp.example"
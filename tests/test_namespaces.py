@@ -0,0 +1,48 @@
+from src.stats.thresholds import parse_thresholds, thresholds_for
+from src.tools.namespaces import namespace_in_scope, namespace_matches, parse_patterns
+
+
+def test_globs_are_not_read_as_regexes():
+    assert not namespace_matches("ci", ["ci-*"])
+    assert not namespace_matches("kube", ["kube-*"])
+    assert not namespace_matches("pa", ["pay*"])
+
+    assert namespace_matches("ci-1234", ["ci-*"])
+    assert namespace_matches("kube-system", ["kube-*"])
+    assert namespace_matches("payments", ["pay*"])
+
+
+def test_exact_names_match_only_themselves():
+    assert namespace_matches("payments", ["payments"])
+    assert not namespace_matches("payments-staging", ["payments"])
+
+
+def test_regexes_need_the_re_prefix():
+    assert namespace_matches("pr-12", [r"re:pr-\d+"])
+    assert not namespace_matches("pr-12x", [r"re:pr-\d+"])
+    assert not namespace_matches("pr-12", [r"pr-\d+"])
+
+
+def test_invalid_regex_is_ignored():
+    assert not namespace_matches("team-a", ["re:team-(", "other"])
+    assert namespace_matches("team-a", ["re:team-(", "team-*"])
+
+
+def test_scope_applies_include_then_exclude():
+    include = parse_patterns("team-*, re:pr-\\d+")
+    exclude = parse_patterns("team-legacy")
+
+    assert namespace_in_scope("team-a", include, exclude)
+    assert namespace_in_scope("pr-7", include, exclude)
+    assert not namespace_in_scope("team-legacy", include, exclude)
+    assert not namespace_in_scope("team", include, exclude)
+    assert not namespace_in_scope("default", include, exclude)
+
+
+def test_thresholds_accept_regex_namespaces():
+    rules = parse_thresholds(r"re:pr-\d+:restarts=5;payments:restarts=0,warnings=0")
+
+    assert rules == [(r"re:pr-\d+", {"restarts": 5}), ("payments", {"restarts": 0, "warnings": 0})]
+    assert thresholds_for("pr-42", rules) == {"restarts": 5}
+    assert thresholds_for("payments", rules) == {"restarts": 0, "warnings": 0}
+    assert thresholds_for("pay", rules) is None
@@ -0,0 +1,77 @@
+import pytest
+
+from src.config import settings
+from src.orchestrator import K8sWatchdogAgent
+from src.orchestrator import agent as agent_module
+
+OPEN_RECOMMENDATIONS = [
+    {
+        "id": 7,
+        "category": "resources",
+        "namespace": "payments",
+        "target": "api",
+        "action": "Raise the memory limit to 512Mi",
+        "first_seen": "2026-09-01T08:00:00",
+    }
+]
+
+
+@pytest.fixture
+def agent():
+    return K8sWatchdogAgent()
+
+
+def test_weekly_prompt_covers_cluster_and_scope(agent, monkeypatch):
+    monkeypatch.setattr(settings, "namespaces_exclude", "kube-system,monitoring")
+    monkeypatch.setattr(settings, "namespaces_include", "")
+
+    system_prompt, user_prompt = agent.build_prompts()
+
+    assert "test-cluster" in system_prompt
+    assert "Generate a weekly health report for cluster test-cluster" in user_prompt
+    assert "Excluded namespaces (names, globs or re: regexes): kube-system, monitoring" in user_prompt
+    assert "Only these namespaces are in scope" not in user_prompt
+    assert "VERIFIED CLUSTER STATISTICS" not in user_prompt
+
+
+def test_statistics_are_embedded(agent, monkeypatch):
+    monkeypatch.setattr(agent_module, "format_stats_for_prompt", lambda stats: "Pods: 42 running")
+
+    _, user_prompt = agent.build_prompts(cluster_stats={"pods": {"total": 42}})
+
+    assert "VERIFIED CLUSTER STATISTICS" in user_prompt
+    assert "Pods: 42 running" in user_prompt
+
+
+def test_namespace_deep_dive_prompt(agent):
+    _, user_prompt = agent.build_prompts(namespace="payments", since_hours=48)
+
+    assert "namespace payments of cluster test-cluster" in user_prompt
+    assert "last 48 hours" in user_prompt
+    assert "Generate a weekly health report" not in user_prompt
+
+
+def test_open_recommendations_are_followed_up(agent):
+    _, user_prompt = agent.build_prompts(open_recommendations=OPEN_RECOMMENDATIONS)
+
+    assert "PREVIOUS RECOMMENDATIONS STILL OPEN" in user_prompt
+    assert "[id=7] (resources) payments/api: Raise the memory limit to 512Mi (issued 2026-09-01)" in user_prompt
+
+
+def test_privacy_mode_withholds_open_recommendations(agent, monkeypatch):
+    monkeypatch.setattr(settings, "privacy_mode", True)
+
+    _, user_prompt = agent.build_prompts(open_recommendations=OPEN_RECOMMENDATIONS)
+
+    assert "PREVIOUS RECOMMENDATIONS STILL OPEN" not in user_prompt
+    assert "payments/api" not in user_prompt
+
+
+def test_replay_prompt_does_not_ask_for_tools(agent, monkeypatch):
+    monkeypatch.setattr(settings, "workload_data_source", "ksm")
+
+    _, user_prompt = agent.build_prompts(replay=True)
+
+    assert "REPLAY: no tools are available" in user_prompt
+    assert "using the available tools" not in user_prompt
+    assert "ksm_* tools" not in user_prompt
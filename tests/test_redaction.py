@@ -0,0 +1,48 @@
+from src.reporter.redaction import REDACTED, compile_rules, redact_html, redact_text
+
+
+def test_text_and_attribute_values_are_redacted():
+    rules = compile_rules([r"acme-corp", r"[a-z0-9-]+\.internal\.example"])
+    report_html = (
+        '<div class="acme-corp"><a href="https://grafana.internal.example/d/1" title="acme-corp">'
+        "Dashboards of acme-corp on grafana.internal.example</a></div>"
+    )
+
+    redacted, count = redact_html(report_html, rules)
+
+    assert "acme-corp" not in redacted
+    assert "internal.example" not in redacted
+    assert count == 5
+    assert redacted.startswith(f'<div class="{REDACTED}"><a href="https://{REDACTED}/d/1"')
+
+
+def test_tag_and_attribute_names_are_never_touched():
+    rules = compile_rules([r"div", r"class"])
+
+    redacted, count = redact_html('<div class="summary">div and class</div>', rules)
+
+    assert redacted == f'<div class="summary">{REDACTED} and {REDACTED}</div>'
+    assert count == 2
+
+
+def test_private_ips_are_optional():
+    text = "Node 10.0.3.17 talks to 192.168.1.0/24 and 8.8.8.8"
+
+    assert redact_text(text, compile_rules([]))[1] == 0
+
+    redacted, count = redact_text(text, compile_rules([], private_ips=True))
+    assert redacted == f"Node {REDACTED} talks to {REDACTED} and 8.8.8.8"
+    assert count == 2
+
+
+def test_invalid_patterns_are_skipped():
+    rules = compile_rules(["(unclosed", "acme"])
+
+    assert len(rules) == 1
+    assert redact_text("ACME payments", rules) == (f"{REDACTED} payments", 1)
+
+
+def test_no_rules_leave_the_report_unchanged():
+    report_html = "<p>10.0.0.1</p>"
+
+    assert redact_html(report_html, []) == (report_html, 0)
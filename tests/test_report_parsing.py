@@ -0,0 +1,61 @@
+import json
+
+from src.orchestrator.agent import extract_report_data
+from src.orchestrator.formats import detect_output_format, render_markdown_report
+from tests.conftest import make_report_html
+
+
+def _with_data_block(report_html: str, payload: str) -> str:
+    block = f'<script type="application/json" id="watchdog-data">{payload}</script>'
+    return report_html.replace("</head>", f"{block}</head>", 1)
+
+
+def test_extract_report_data_splits_the_block(report_data):
+    report_html, data = extract_report_data(_with_data_block(make_report_html(), json.dumps(report_data)))
+
+    assert data == report_data
+    assert "watchdog-data" not in report_html
+    assert report_html.rstrip().endswith("</html>")
+
+
+def test_extract_report_data_without_block():
+    report_html = make_report_html()
+
+    assert extract_report_data(report_html) == (report_html, {})
+
+
+def test_extract_report_data_with_invalid_json():
+    report_html, data = extract_report_data(_with_data_block(make_report_html(), "{not json"))
+
+    assert data == {}
+    assert "watchdog-data" not in report_html
+
+
+def test_extract_report_data_rejects_non_objects():
+    _, data = extract_report_data(_with_data_block(make_report_html(), "[1, 2]"))
+
+    assert data == {}
+
+
+def test_detect_html_document():
+    assert detect_output_format("Here it is:\n<!DOCTYPE html><html></html>") == "html"
+    assert detect_output_format("<HTML><body></body></HTML>") == "html"
+
+
+def test_detect_markdown():
+    assert detect_output_format("# Weekly report\n\n- 3 pods restarting") == "markdown"
+    assert detect_output_format("| Pod | Restarts |\n|---|---|\n| api | 3 |") == "markdown"
+    assert detect_output_format("```markdown\nAll good\n```") == "markdown"
+
+
+def test_detect_plain_text():
+    assert detect_output_format("The cluster is healthy.") == "text"
+
+
+def test_render_markdown_report_uses_first_heading_as_title():
+    rendered = render_markdown_report("```markdown\n# Payments health\n\n## Issues\n\n- api restarts\n```")
+
+    assert rendered.startswith("<!DOCTYPE html>")
+    assert "<title>Payments health</title>" in rendered
+    assert "<li>api restarts</li>" in rendered
+    assert "```" not in rendered
@@ -0,0 +1,49 @@
+from src.reporter.sanitize import sanitize_report_html
+from tests.conftest import make_report_html
+
+
+def test_scripts_are_dropped_with_their_content():
+    sanitized = sanitize_report_html(make_report_html("<p>ok</p><script>fetch('https://evil')</script>"))
+
+    assert "<script" not in sanitized
+    assert "evil" not in sanitized
+    assert "<p>ok</p>" in sanitized
+
+
+def test_event_handlers_and_javascript_links_are_removed():
+    sanitized = sanitize_report_html(
+        make_report_html('<div onclick="steal()"><a href="javascript:steal()">x</a><a href="https://ok.example">y</a></div>')
+    )
+
+    assert "onclick" not in sanitized
+    assert "javascript:" not in sanitized
+    assert 'href="https://ok.example"' in sanitized
+
+
+def test_remote_resources_are_removed_and_inline_images_kept():
+    inline = "data:image/png;base64,iVBORw0KGgo="
+    sanitized = sanitize_report_html(make_report_html(
+        f'<img src="https://tracker.example/pixel.png"><img src="{inline}">'
+        '<div style="background: url(https://tracker.example/bg.png)">styled</div>'
+    ))
+
+    assert "tracker.example" not in sanitized
+    assert inline in sanitized
+    assert "styled" in sanitized
+
+
+def test_style_imports_are_stripped():
+    sanitized = sanitize_report_html(
+        make_report_html().replace("</head>", "<style>@import url(https://fonts.example/a.css); h1 { color: red; }</style></head>")
+    )
+
+    assert "@import" not in sanitized
+    assert "h1 { color: red; }" in sanitized
+
+
+def test_document_structure_is_preserved():
+    sanitized = sanitize_report_html(make_report_html("<table><tr><td>api</td><td>12</td></tr></table>"))
+
+    assert sanitized.startswith("<!DOCTYPE html>")
+    assert "<table><tr><td>api</td><td>12</td></tr></table>" in sanitized
+    assert sanitized.rstrip().endswith("</html>")
@@ -0,0 +1,82 @@
+import hashlib
+import hmac
+from urllib.parse import urlencode
+
+from src.reporter.commands import events_response, parse_slash_command, top_restarts_response
+from src.reporter.interactions import MAX_REQUEST_AGE_SECONDS, verify_slack_signature
+
+SECRET = "signing-secret"
+NOW = 1_700_000_000
+
+CLUSTER_STATS = {
+    "appendix": {
+        "restarting_pods": [{"namespace": "payments", "pod": "api-7d9f", "restarts": 12, "reason": "OOMKilled"}],
+        "rightsizing": [],
+        "warning_events": [
+            {"namespace": "payments", "reason": "BackOff", "object": "Pod/api-7d9f", "count": 4},
+            {"namespace": "batch", "reason": "FailedMount", "object": "Pod/job-1", "count": 9},
+        ],
+    },
+}
+
+
+def sign(body: bytes, timestamp: str) -> str:
+    base = f"v0:{timestamp}:".encode("utf-8") + body
+    return "v0=" + hmac.new(SECRET.encode("utf-8"), base, hashlib.sha256).hexdigest()
+
+
+def body_text(response: dict) -> str:
+    return response["blocks"][1]["text"]["text"]
+
+
+def test_parse_slash_command_splits_subcommand_and_args():
+    body = urlencode({"command": "/k8s", "text": "Events  payments", "user_id": "U123"}).encode()
+
+    assert parse_slash_command(body) == {"subcommand": "events", "args": ["payments"], "user_id": "U123"}
+
+
+def test_parse_slash_command_without_text():
+    assert parse_slash_command(b"command=%2Fk8s") == {"subcommand": "", "args": [], "user_id": None}
+
+
+def test_valid_signature_is_accepted():
+    body = b"command=%2Fk8s&text=nodes"
+    timestamp = str(NOW)
+
+    assert verify_slack_signature(SECRET, timestamp, body, sign(body, timestamp), now=NOW)
+
+
+def test_tampered_body_or_wrong_secret_is_rejected():
+    body = b"command=%2Fk8s&text=nodes"
+    timestamp = str(NOW)
+    signature = sign(body, timestamp)
+
+    assert not verify_slack_signature(SECRET, timestamp, body + b"&user_id=U1", signature, now=NOW)
+    assert not verify_slack_signature("other-secret", timestamp, body, signature, now=NOW)
+    assert not verify_slack_signature(SECRET, timestamp, body, "", now=NOW)
+
+
+def test_stale_or_malformed_timestamp_is_rejected():
+    body = b"command=%2Fk8s&text=nodes"
+    stale = str(NOW - MAX_REQUEST_AGE_SECONDS - 1)
+
+    assert not verify_slack_signature(SECRET, stale, body, sign(body, stale), now=NOW)
+    assert not verify_slack_signature(SECRET, "not-a-number", body, sign(body, "not-a-number"), now=NOW)
+
+
+def test_top_restarts_fall_back_to_collected_statistics():
+    response = top_restarts_response([], CLUSTER_STATS)
+
+    assert "api-7d9f" in body_text(response)
+    assert "OOMKilled" in body_text(response)
+
+
+def test_events_come_from_collected_statistics():
+    response = events_response(CLUSTER_STATS, "payments")
+
+    assert "BackOff" in body_text(response)
+    assert "FailedMount" not in body_text(response)
+
+
+def test_events_without_statistics():
+    assert body_text(events_response(None, None)) == "No warning events in the latest report."